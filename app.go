@@ -0,0 +1,241 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Top level model holding one tab per repository
+type app struct {
+	tabs        []model
+	active      int
+	keys        keyMap
+	noChanges   bool // none of the repositories had changes once loaded
+	crash       *crash
+	title       string // set as the terminal title
+	sizeRetries int    // times a zero window size was asked for again
+}
+
+// The tab starts out empty, Init loads it
+func newModel(r repo, tabbed bool) model {
+	return model{repo: r, keys: defaultKeyMap, tabbed: tabbed, hunk: -1, match: -1, loading: true, loadingBranch: true, paths: cfg.Paths}
+}
+
+func newApp(repos []repo) app {
+	a := app{keys: defaultKeyMap, crash: &crash{}}
+	for _, r := range repos {
+		a.tabs = append(a.tabs, newModel(r, len(repos) > 1))
+	}
+	if firstRun && len(a.tabs) > 0 {
+		a.tabs[0].tour = tourPages(defaultKeyMap)
+	}
+	return a
+}
+
+func (a app) hasChanges() bool {
+	for _, tab := range a.tabs {
+		if len(tab.files) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (a app) Init() tea.Cmd {
+	cmds := []tea.Cmd{watchTick()}
+	for _, tab := range a.tabs {
+		cmds = append(cmds, tab.Init())
+	}
+	return tea.Batch(cmds...)
+}
+
+func (a app) loading() bool {
+	for _, tab := range a.tabs {
+		if tab.loading {
+			return true
+		}
+	}
+	return false
+}
+
+func (a app) Update(msg tea.Msg) (next tea.Model, cmd tea.Cmd) {
+	defer func() {
+		if r := recover(); r != nil {
+			a.crash.catch(r)
+			next, cmd = a, tea.Quit
+		}
+	}()
+	// View can't quit, so after a panic there the next message does, the
+	// watch tick at the latest
+	if a.crash.happened() {
+		return a, tea.Quit
+	}
+	next, cmd = a.update(msg)
+	return next.(app).retitle(cmd)
+}
+
+func (a app) update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		var retry tea.Cmd
+		a, retry = a.retrySize(msg)
+		// Every tab gets resized so switching tabs doesn't need a new size
+		if len(a.tabs) > 1 {
+			msg.Height--
+		}
+		cmds := []tea.Cmd{retry}
+		for i := range a.tabs {
+			tab, cmd := a.tabs[i].Update(msg)
+			a.tabs[i] = tab.(model)
+			cmds = append(cmds, cmd)
+		}
+		return a, tea.Batch(cmds...)
+	case sizeRetryMsg:
+		return a, tea.WindowSize()
+	case watchTickMsg:
+		if err := takeGitTimeout(); err != nil && len(a.tabs) > 0 {
+			a.tabs[a.active].status = tr("%v, raise git_timeout in the config if it needs longer", err)
+		}
+		// Background tabs are checked too so they're up to date when switching to them
+		cmds := []tea.Cmd{watchTick()}
+		for i := range a.tabs {
+			tab, cmd := a.tabs[i].Update(msg)
+			a.tabs[i] = tab.(model)
+			cmds = append(cmds, cmd)
+		}
+		return a, tea.Batch(cmds...)
+	case jobStepMsg:
+		// Jobs keep running in the background when switching tabs
+		return a.updateTab(msg.root, msg)
+	case diffLoadedMsg:
+		return a.updateTab(msg.root, msg)
+	case changeSetLoadedMsg:
+		return a.updateTab(msg.root, msg)
+	case searchLoadedMsg:
+		return a.updateTab(msg.root, msg)
+	case statusLoadedMsg:
+		updated, cmd := a.updateTab(msg.root, msg)
+		a = updated.(app)
+		if !a.loading() && !a.hasChanges() && !watchWhenClean {
+			a.noChanges = true
+			return a, tea.Quit
+		}
+		return a, cmd
+	case statusProgressMsg:
+		return a.updateTab(msg.root, msg)
+	case branchLoadedMsg:
+		return a.updateTab(msg.root, msg)
+	case commitFinishedMsg:
+		return a.updateTab(msg.root, msg)
+	case formatFinishedMsg:
+		return a.updateTab(msg.root, msg)
+	case fetchFinishedMsg:
+		return a.updateTab(msg.root, msg)
+	case pushFinishedMsg:
+		return a.updateTab(msg.root, msg)
+	case blobsFetchedMsg:
+		return a.updateTab(msg.root, msg)
+	case issuesLoadedMsg:
+		return a.updateTab(msg.root, msg)
+	case messageGeneratedMsg:
+		return a.updateTab(msg.root, msg)
+	case tea.KeyMsg:
+		if tab := &a.tabs[a.active]; len(a.tabs) > 1 && !tab.typing() {
+			// Sequences first, the tab keys may start or end one
+			key, _ := tab.keys.sequence(tab.chord, keyName(msg))
+			if next, prev := a.keys.nextTab.matches(key), a.keys.prevTab.matches(key); next || prev {
+				if len(tab.chord) > 0 {
+					tab.chord, tab.status = nil, ""
+				}
+				if next {
+					a.active = (a.active + 1) % len(a.tabs)
+				} else {
+					a.active = (a.active + len(a.tabs) - 1) % len(a.tabs)
+				}
+				return a, nil
+			}
+		}
+	}
+
+	tab, cmd := a.tabs[a.active].Update(msg)
+	a.tabs[a.active] = tab.(model)
+	return a, cmd
+}
+
+// Pass a message to the tab of a repository
+func (a app) updateTab(root string, msg tea.Msg) (tea.Model, tea.Cmd) {
+	for i := range a.tabs {
+		if a.tabs[i].repo.root == root {
+			tab, cmd := a.tabs[i].Update(msg)
+			a.tabs[i] = tab.(model)
+			return a, cmd
+		}
+	}
+	return a, nil
+}
+
+func (a app) View() (view string) {
+	defer func() {
+		if r := recover(); r != nil {
+			a.crash.catch(r)
+			view = ""
+		}
+	}()
+	if a.noChanges || a.crash.happened() || a.tabs[a.active].quitting {
+		return ""
+	}
+	if len(a.tabs) == 1 {
+		return a.tabs[0].View()
+	}
+	return a.tabBar() + "\n" + a.tabs[a.active].View()
+}
+
+// The repository to run git commit in once the program exits, if handing off
+// to git's editor
+func (a app) handOff() (repo, bool) {
+	tab := a.tabs[a.active]
+	return tab.repo, tab.handOff
+}
+
+func (a app) tabBar() string {
+	var tabs []string
+	for i, tab := range a.tabs {
+		label := " " + tab.repo.name() + " (" + strconv.Itoa(len(tab.files)) + ") "
+		if i == a.active {
+			tabs = append(tabs, activeTabStyle.Render(label))
+		} else {
+			tabs = append(tabs, unstagedStyle.Render(label))
+		}
+	}
+	return strings.Join(tabs, separatorStyle.Render("│"))
+}
+
+// Read repository paths from a workspace file, one per line.
+// Blank lines and lines starting with '#' are ignored, relative paths are
+// resolved against the directory of the workspace file.
+func readWorkspace(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var dirs []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if !filepath.IsAbs(line) {
+			line = filepath.Join(filepath.Dir(path), line)
+		}
+		dirs = append(dirs, line)
+	}
+	return dirs, scanner.Err()
+}
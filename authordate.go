@@ -0,0 +1,157 @@
+package main
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Like "Ada Lovelace <ada@example.com>"
+var authorLine = regexp.MustCompile(`^[^<>]*[^<>\s][^<>]* <[^<>\s@]+@[^<>\s@]+>$`)
+
+// The formats a date can be typed in, the ones without a zone being local
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	time.RFC1123Z,
+}
+
+// The author and date to commit with instead of git's own, to commit on
+// behalf of someone or backfill work
+type authorDate struct {
+	author string
+	date   string // RFC 3339
+}
+
+// The git commit options for them
+func (a authorDate) options() []string {
+	var options []string
+	if a.author != "" {
+		options = append(options, "--author="+a.author)
+	}
+	if a.date != "" {
+		options = append(options, "--date="+a.date)
+	}
+	return options
+}
+
+// Fields for the author and the date, indexed by these
+const (
+	authorField = iota
+	dateField
+)
+
+type authorDateEditor struct {
+	inputs [2]textinput.Model
+	focus  int
+}
+
+func parseAuthor(s string) (string, error) {
+	s = strings.Join(strings.Fields(s), " ")
+	if s != "" && !authorLine.MatchString(s) {
+		return "", errors.New(tr("Invalid author %q, write it as \"Name <email>\"", s))
+	}
+	return s, nil
+}
+
+// A date in one of dateLayouts or @ and a Unix time, as RFC 3339, which
+// can't be in the future
+func parseDate(s string, now time.Time) (string, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return "", nil
+	}
+	var t time.Time
+	if seconds, ok := strings.CutPrefix(s, "@"); ok {
+		n, err := strconv.ParseInt(seconds, 10, 64)
+		if err != nil {
+			return "", errors.New(tr("Invalid date %q, write it like 2024-05-01 14:30", s))
+		}
+		t = time.Unix(n, 0)
+	} else {
+		parsed := false
+		for _, layout := range dateLayouts {
+			var err error
+			if t, err = time.ParseInLocation(layout, s, time.Local); err == nil {
+				parsed = true
+				break
+			}
+		}
+		if !parsed {
+			return "", errors.New(tr("Invalid date %q, write it like 2024-05-01 14:30", s))
+		}
+	}
+	if t.After(now) {
+		return "", errors.New(tr("The date %s is in the future", t.Format(time.RFC3339)))
+	}
+	return t.Format(time.RFC3339), nil
+}
+
+func (m *model) openAuthorDateEditor() {
+	e := &authorDateEditor{}
+	for i, field := range []struct{ prompt, placeholder, value string }{
+		{tr("Author: "), tr("Name <email>, empty for yours"), m.authorDate.author},
+		{tr("Date: "), tr("2024-05-01 14:30, empty for now"), m.authorDate.date},
+	} {
+		input := textinput.New()
+		input.Prompt = field.prompt
+		input.PromptStyle = promptStyle
+		input.Placeholder = field.placeholder
+		input.Cursor.SetMode(cursor.CursorStatic)
+		input.SetValue(field.value)
+		e.inputs[i] = input
+	}
+	e.inputs[authorField].Focus()
+	m.authorEditor = e
+}
+
+func (m *model) updateAuthorDateEditor(msg tea.KeyMsg) {
+	e := m.authorEditor
+	switch key := keyName(msg); {
+	case key == "up", key == "down", key == "tab", key == "shift+tab":
+		e.inputs[e.focus].Blur()
+		e.focus = 1 - e.focus
+		e.inputs[e.focus].Focus()
+	case key == "enter":
+		author, err := parseAuthor(e.inputs[authorField].Value())
+		if err != nil {
+			m.status = err.Error()
+			return
+		}
+		date, err := parseDate(e.inputs[dateField].Value(), time.Now())
+		if err != nil {
+			m.status = err.Error()
+			return
+		}
+		m.authorDate = authorDate{author, date}
+		m.authorEditor = nil
+	case m.keys.cancel.matches(key):
+		m.authorEditor = nil
+	default:
+		e.inputs[e.focus], _ = e.inputs[e.focus].Update(msg)
+	}
+}
+
+func (m model) authorDateEditorView(width, height int) string {
+	e := m.authorEditor
+	rows := []string{sectionStyle.Render(ansi.Truncate(tr("Author and date of the commit"), width, "…")), ""}
+	for i, input := range e.inputs {
+		if len(rows) == height {
+			break
+		}
+		input.Width = max(width-ansi.StringWidth(input.Prompt)-3, 0)
+		rows = append(rows, cfg.Glyphs.cursor(i == e.focus)+input.View())
+	}
+	return strings.Join(rows, "\n")
+}
@@ -0,0 +1,46 @@
+package main
+
+// Star a file to come back to it later. Bookmarks are kept by path for the
+// session, so they survive reloads and the file moving between sections.
+func (m *model) toggleBookmark(index int) {
+	path := m.files[index].pathFromGitRoot
+	if m.bookmarks == nil {
+		m.bookmarks = make(map[string]bool)
+	}
+	m.keepingCursor(func() {
+		if m.bookmarks[path] {
+			delete(m.bookmarks, path)
+		} else {
+			m.bookmarks[path] = true
+		}
+	})
+}
+
+func (m model) bookmarked(f fileEntry) bool {
+	return m.bookmarks[f.pathFromGitRoot]
+}
+
+// Whether a file is in the list, which may only show bookmarked files
+func (m model) listed(f fileEntry) bool {
+	return !m.bookmarkedOnly || m.bookmarked(f)
+}
+
+func (m model) bookmarkCount() int {
+	n := 0
+	for _, f := range m.files {
+		if m.bookmarked(f) {
+			n++
+		}
+	}
+	return n
+}
+
+func (m *model) toggleBookmarkedOnly() {
+	if !m.bookmarkedOnly && m.bookmarkCount() == 0 {
+		m.status = tr("No files are bookmarked, press %s to bookmark one", m.keys.bookmark.Help().Key)
+		return
+	}
+	m.keepingCursor(func() {
+		m.bookmarkedOnly = !m.bookmarkedOnly
+	})
+}
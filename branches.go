@@ -0,0 +1,199 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// A local branch listed in branchesMode
+type localBranch struct {
+	name    string
+	current bool
+	age     string
+	subject string
+}
+
+// State of branchesMode
+type branchList struct {
+	branches []localBranch
+	cursor   int
+}
+
+// Local branches, the ones committed to most recently first
+func getLocalBranches(r repo) []localBranch {
+	output, err := r.git("for-each-ref", "--sort=-committerdate",
+		"--format=%(refname:short)%00%(HEAD)%00%(committerdate:relative)%00%(subject)", "refs/heads/").Output()
+	if err != nil {
+		return nil
+	}
+	var branches []localBranch
+	for _, line := range splitDiffLines(string(output)) {
+		fields := strings.SplitN(line, "\x00", 4)
+		if len(fields) < 4 {
+			continue
+		}
+		branches = append(branches, localBranch{fields[0], fields[1] == "*", fields[2], fields[3]})
+	}
+	return branches
+}
+
+// List the local branches with the cursor on the current one
+func (m *model) openBranches() {
+	bl := &branchList{branches: getLocalBranches(m.repo)}
+	for i, b := range bl.branches {
+		if b.current {
+			bl.cursor = i
+		}
+	}
+	m.branchList = bl
+	m.mode = branchesMode
+}
+
+func (m *model) updateBranches(key string) {
+	bl := m.branchList
+	switch {
+	case m.keys.up.matches(key):
+		bl.cursor = max(bl.cursor-1, 0)
+	case m.keys.down.matches(key):
+		bl.cursor = min(bl.cursor+1, max(len(bl.branches)-1, 0))
+	case m.keys.newBranch.matches(key):
+		m.openNewBranchPrompt()
+	case m.keys.focusList.matches(key), m.keys.branches.matches(key):
+		m.closeBranches()
+	case len(bl.branches) > 0 && m.keys.switchBranch.matches(key):
+		m.switchBranch(bl.branches[bl.cursor].name)
+	}
+}
+
+func (m *model) closeBranches() {
+	m.branchList = nil
+	m.mode = listMode
+	m.loadDiff()
+}
+
+// Start typing the name of a branch to create from HEAD and switch to
+func (m *model) openNewBranchPrompt() {
+	input := textinput.New()
+	input.Prompt = tr("New branch from HEAD: ")
+	input.PromptStyle = promptStyle
+	input.Cursor.SetMode(cursor.CursorStatic)
+	input.Focus()
+	m.newBranch = &input
+}
+
+func (m *model) updateNewBranch(msg tea.KeyMsg) {
+	switch key := keyName(msg); {
+	case key == "enter":
+		name := strings.TrimSpace(m.newBranch.Value())
+		m.newBranch = nil
+		if name != "" {
+			m.createBranch(name)
+		}
+	case m.keys.cancel.matches(key):
+		m.newBranch = nil
+	default:
+		*m.newBranch, _ = m.newBranch.Update(msg)
+	}
+}
+
+func (m model) newBranchView() string {
+	m.newBranch.Width = max(m.width-ansi.StringWidth(m.newBranch.Prompt)-1, 0)
+	return m.newBranch.View()
+}
+
+// Creating a branch from HEAD keeps the work tree and index as they are,
+// so the changes being staged move over to it
+func (m *model) createBranch(name string) {
+	err := m.repo.run("switch", "-c", name)
+	if m.handleIndexLock(err) {
+		return
+	}
+	if err != nil {
+		m.status = tr("Failed to create %s: %v", name, err)
+		return
+	}
+	m.switched(tr("Created and switched to %s", name))
+}
+
+// Switch to a branch, bringing the changes along as git does. When they'd be
+// overwritten by the branch, offer to stash them and pop them there instead.
+func (m *model) switchBranch(name string) {
+	switch {
+	case name == m.branch:
+		m.status = tr("Already on %s", name)
+		return
+	case m.operation != nil:
+		m.status = tr("Finish or abort the %s in progress before switching branches", m.operation.name)
+		return
+	}
+	err := m.repo.run("switch", name)
+	if m.handleIndexLock(err) {
+		return
+	}
+	if err != nil && strings.Contains(err.Error(), "would be overwritten") {
+		m.ask(tr("Your changes conflict with %s. Stash them, switch and pop them there?", name), func(m *model) {
+			m.switchWithStash(name)
+		})
+		return
+	}
+	if err != nil {
+		m.status = tr("Failed to switch to %s: %v", name, err)
+		return
+	}
+	if len(m.files) > 0 {
+		m.switched(tr("Switched to %s, the changes came along", name))
+	} else {
+		m.switched(tr("Switched to %s", name))
+	}
+}
+
+// Stash everything, untracked files too, switch, then pop the stash on the
+// branch. The stash is kept if popping it conflicts.
+func (m *model) switchWithStash(name string) {
+	r := m.repo
+	if output, err := r.git("stash", "push", "--include-untracked", "-m", "istage: switching to "+name).CombinedOutput(); err != nil {
+		m.status = tr("Failed to stash the changes: %s", firstLine(string(output)))
+		return
+	}
+	if err := r.run("switch", name); err != nil {
+		// Put the changes back where they were
+		r.git("stash", "pop").Run()
+		m.reload()
+		m.status = tr("Failed to switch to %s: %v", name, err)
+		return
+	}
+	output, err := r.git("stash", "pop").CombinedOutput()
+	m.switched(tr("Switched to %s and popped the changes", name))
+	if err != nil {
+		m.status = popFailure(output)
+	}
+}
+
+func (m *model) switched(status string) {
+	m.reload()
+	m.closeBranches()
+	m.status = status
+}
+
+func (m model) branchesView(width, height int) string {
+	bl := m.branchList
+	if len(bl.branches) == 0 {
+		return tr("No local branches yet, press %s to create one", m.keys.newBranch.Help().Key)
+	}
+	rows := []string{sectionStyle.Render(ansi.Truncate(tr("Local branches"), width, "…"))}
+	offset := max(bl.cursor+1-(height-1), 0)
+	for i := offset; i < len(bl.branches) && len(rows) < height; i++ {
+		b := bl.branches[i]
+		name := b.name
+		if b.current {
+			name = cursorStyle.Render(name + " *")
+		}
+		row := cfg.Glyphs.cursor(i == bl.cursor) + name + " " + badgeStyle.Render(b.age) + " " + b.subject
+		rows = append(rows, ansi.Truncate(row, width, "…"))
+	}
+	return strings.Join(rows, "\n")
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// The diffs of every listed file one after the other, read top to bottom
+// like a pull request in changeSetMode
+type changeSet struct {
+	lines  []diffLine
+	files  []changeSetFile
+	titles map[int]bool // lines with the title of a section
+	more   int          // files left out after diffLineLimit lines
+	scroll int
+}
+
+// A file of the change set, its diff in a section starting at the line of
+// its header
+type changeSetFile struct {
+	section section
+	path    string
+	start   int
+}
+
+type changeSetLoadedMsg struct {
+	root      string
+	id        int // the diffID it was loaded for
+	changeSet *changeSet
+	cancelled bool
+}
+
+func getChangeSet(r repo, files []fileEntry) *changeSet {
+	cs := &changeSet{titles: make(map[int]bool)}
+	_, generation := gitContext(r.root)
+	for _, s := range []section{conflictsSection, stagedSection, unstagedSection, untrackedSection} {
+		for _, f := range files {
+			if !slices.Contains(f.sections(), s) {
+				continue
+			}
+			if gitCancelledSince(r.root, generation) {
+				return cs
+			}
+			if len(cs.lines) >= diffLineLimit {
+				cs.more++
+				continue
+			}
+			if len(cs.files) == 0 || cs.files[len(cs.files)-1].section != s {
+				cs.titles[len(cs.lines)] = true
+				cs.lines = append(cs.lines, diffLine{text: s.title()})
+			}
+			cs.files = append(cs.files, changeSetFile{s, f.pathFromGitRoot, len(cs.lines)})
+			cs.lines = append(cs.lines, diffLine{text: f.pathFromCwd})
+			// Not below 0, which would mean no limit
+			lines, _ := getFileDiff(r, f, s, max(diffLineLimit-len(cs.lines), 0))
+			cs.lines = append(cs.lines, parseDiff(lines)...)
+		}
+	}
+	return cs
+}
+
+func (m *model) openChangeSet() {
+	if m.review != nil {
+		m.status = tr("Leave the branch review with %s to read all the changes", m.keys.review.Help().Key)
+		return
+	}
+	m.changeSet = &changeSet{}
+	m.mode = changeSetMode
+	m.loadChangeSet()
+}
+
+// Load the change set again in the background, like loadDiff does for the
+// diff pane
+func (m *model) loadChangeSet() {
+	var files []fileEntry
+	for _, f := range m.files {
+		if m.listed(f) {
+			files = append(files, f)
+		}
+	}
+	m.diffID++
+	m.diffLoading = true
+	r, id := m.repo, m.diffID
+	m.queue(func() tea.Msg {
+		_, generation := gitContext(r.root)
+		cs := getChangeSet(r, files)
+		return changeSetLoadedMsg{r.root, id, cs, gitCancelledSince(r.root, generation)}
+	})
+}
+
+// Show the loaded change set, keeping the scroll position, or leave it once
+// there are no changes left
+func (m *model) changeSetLoaded(msg changeSetLoadedMsg) {
+	if msg.id != m.diffID || m.mode != changeSetMode {
+		return
+	}
+	m.diffLoading = false
+	if msg.cancelled {
+		m.status = tr("Cancelled loading the diff")
+		if len(m.changeSet.files) == 0 {
+			m.closeChangeSet()
+		}
+		return
+	}
+	scroll := m.changeSet.scroll
+	m.changeSet = msg.changeSet
+	m.changeSet.scroll = min(scroll, m.lastChangeSetScroll())
+	if len(m.changeSet.files) == 0 {
+		m.closeChangeSet()
+		m.status = tr("No changes")
+	}
+}
+
+func (m *model) closeChangeSet() {
+	m.changeSet = nil
+	m.mode = listMode
+	m.loadDiff()
+}
+
+func (m model) lastChangeSetScroll() int {
+	// Below the title
+	return max(len(m.changeSet.lines)-m.bodyHeight()+1, 0)
+}
+
+// Index into files of the change set of the one at the top of the view, -1
+// above the first one
+func (cs *changeSet) fileAt(line int) int {
+	current := -1
+	for i, f := range cs.files {
+		if f.start <= line {
+			current = i
+		}
+	}
+	return current
+}
+
+func (m *model) updateChangeSet(key string) tea.Cmd {
+	cs := m.changeSet
+	last := m.lastChangeSetScroll()
+	switch {
+	case m.keys.scrollUp.matches(key):
+		cs.scroll = max(cs.scroll-1, 0)
+	case m.keys.scrollDown.matches(key):
+		cs.scroll = min(cs.scroll+1, last)
+	case m.keys.pageUp.matches(key):
+		cs.scroll = max(cs.scroll-m.bodyHeight(), 0)
+	case m.keys.pageDown.matches(key):
+		cs.scroll = min(cs.scroll+m.bodyHeight(), last)
+	case m.keys.top.matches(key):
+		cs.scroll = 0
+	case m.keys.bottom.matches(key):
+		cs.scroll = last
+	case m.keys.nextFile.matches(key):
+		if i := cs.fileAt(cs.scroll) + 1; i < len(cs.files) {
+			cs.scroll = min(cs.files[i].start, last)
+		}
+	case m.keys.prevFile.matches(key):
+		// The start of the file at the top, or of the one before once there
+		i := cs.fileAt(cs.scroll)
+		if i > 0 && cs.files[i].start == cs.scroll {
+			i--
+		}
+		if i >= 0 {
+			cs.scroll = cs.files[i].start
+		}
+	case m.keys.nextHunk.matches(key):
+		for i := cs.scroll + 1; i < len(cs.lines); i++ {
+			if isHunkHeader(cs.lines[i]) {
+				cs.scroll = min(i, last)
+				break
+			}
+		}
+	case m.keys.prevHunk.matches(key):
+		for i := cs.scroll - 1; i >= 0; i-- {
+			if isHunkHeader(cs.lines[i]) {
+				cs.scroll = i
+				break
+			}
+		}
+	case m.keys.toggle.matches(key):
+		return m.toggleChangeSetFile()
+	case m.keys.focusList.matches(key), m.keys.changeSet.matches(key):
+		m.closeChangeSet()
+	}
+	return nil
+}
+
+// Stage the file at the top of the view, or unstage it when it's in the
+// staged section
+func (m *model) toggleChangeSetFile() tea.Cmd {
+	cs := m.changeSet
+	if len(cs.files) == 0 {
+		return nil
+	}
+	i := cs.fileAt(cs.scroll)
+	if i < 0 {
+		i = 0
+	}
+	f := cs.files[i]
+	for index, entry := range m.files {
+		if entry.pathFromGitRoot == f.path {
+			return m.toggleRows(listRow{f.section, index})
+		}
+	}
+	return nil
+}
+
+func (m model) changeSetView(width, height int) string {
+	cs := m.changeSet
+	title := tr("All changes, %d file(s)", len(cs.files))
+	rows := []string{sectionStyle.Render(ansi.Truncate(title, width, "…"))}
+	if m.diffLoading && len(cs.files) == 0 {
+		return rows[0] + "\n" + badgeStyle.Render(ansi.Truncate(tr("Loading diff, %s to cancel", m.keys.cancel.Help().Key), width, "…"))
+	}
+	for i := cs.scroll; i < len(cs.lines) && len(rows) < height; i++ {
+		l := cs.lines[i]
+		switch {
+		case cs.titles[i]:
+			rows = append(rows, sectionStyle.Render(ansi.Truncate(l.text, width, "…")))
+		case slices.ContainsFunc(cs.files, func(f changeSetFile) bool { return f.start == i }):
+			rows = append(rows, promptStyle.Render(ansi.Truncate("▌ "+l.text, width, "…")))
+		default:
+			rows = append(rows, renderDiffLine(l, width))
+		}
+	}
+	if cs.more > 0 && len(rows) < height && cs.scroll+len(rows)-1 >= len(cs.lines) {
+		rows = append(rows, badgeStyle.Render(ansi.Truncate(tr("… %d more file(s) left out", cs.more), width, "…")))
+	}
+	return strings.Join(rows, "\n")
+}
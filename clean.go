@@ -0,0 +1,167 @@
+package main
+
+import (
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/hzqtc/git-istage/gitx"
+)
+
+// A path git clean would delete, listed in cleanMode. Untracked directories
+// are listed once, ending with a slash, like git clean -d does.
+type cleanCandidate struct {
+	pathFromGitRoot string
+	ignored         bool
+	selected        bool
+}
+
+// State of cleanMode, like `git clean -i`
+type cleanList struct {
+	candidates []cleanCandidate
+	cursor     int
+	ignored    bool // ignored files are listed too, like git clean -x
+}
+
+// Paths printed by a dry run of git clean, run from the root
+func cleanDryRun(r repo, options ...string) []string {
+	output, err := r.atRoot().git(append([]string{"clean", "-n", "-d"}, options...)...).Output()
+	if err != nil {
+		return nil
+	}
+	var paths []string
+	for _, line := range splitDiffLines(string(output)) {
+		if path, ok := strings.CutPrefix(line, "Would remove "); ok {
+			paths = append(paths, gitx.UnquotePath(path))
+		}
+	}
+	return paths
+}
+
+func getCleanCandidates(r repo, ignored bool) []cleanCandidate {
+	if !ignored {
+		var candidates []cleanCandidate
+		for _, path := range cleanDryRun(r) {
+			candidates = append(candidates, cleanCandidate{pathFromGitRoot: path})
+		}
+		return candidates
+	}
+	isIgnored := make(map[string]bool)
+	for _, path := range cleanDryRun(r, "-X") {
+		isIgnored[path] = true
+	}
+	var candidates []cleanCandidate
+	for _, path := range cleanDryRun(r, "-x") {
+		candidates = append(candidates, cleanCandidate{pathFromGitRoot: path, ignored: isIgnored[path]})
+	}
+	return candidates
+}
+
+func (m *model) openClean() {
+	m.cleanList = &cleanList{candidates: getCleanCandidates(m.repo, false)}
+	m.mode = cleanMode
+}
+
+func (m *model) updateClean(key string) {
+	cl := m.cleanList
+	switch {
+	case m.keys.up.matches(key):
+		cl.cursor = max(cl.cursor-1, 0)
+	case m.keys.down.matches(key):
+		cl.cursor = min(cl.cursor+1, max(len(cl.candidates)-1, 0))
+	case m.keys.cleanIgnored.matches(key):
+		cl.ignored = !cl.ignored
+		cl.candidates = getCleanCandidates(m.repo, cl.ignored)
+		cl.cursor = min(cl.cursor, max(len(cl.candidates)-1, 0))
+	case m.keys.focusList.matches(key), m.keys.clean.matches(key):
+		m.closeClean()
+	case len(cl.candidates) == 0:
+		return
+	case m.keys.toggle.matches(key):
+		cl.candidates[cl.cursor].selected = !cl.candidates[cl.cursor].selected
+		cl.cursor = min(cl.cursor+1, len(cl.candidates)-1)
+	case m.keys.toggleAll.matches(key):
+		// Select all, or none once all are
+		all := true
+		for _, c := range cl.candidates {
+			all = all && c.selected
+		}
+		for i := range cl.candidates {
+			cl.candidates[i].selected = !all
+		}
+	case m.keys.deleteSelected.matches(key):
+		m.askToClean()
+	}
+}
+
+func (m *model) closeClean() {
+	m.cleanList = nil
+	m.mode = listMode
+	m.loadDiff()
+}
+
+// List what git clean is about to delete and delete it once confirmed, each
+// batch going to the trash first
+func (m *model) askToClean() {
+	var paths []string
+	for _, c := range m.cleanList.candidates {
+		if c.selected {
+			paths = append(paths, c.pathFromGitRoot)
+		}
+	}
+	if len(paths) == 0 {
+		m.status = tr("Select the files to delete with %s", m.keys.toggle.Help().Key)
+		return
+	}
+	details := []string{tr("Files git clean deletes"), ""}
+	details = append(details, paths[:min(len(paths), pathspecShown)]...)
+	if len(paths) > pathspecShown {
+		details = append(details, tr("and %d more", len(paths)-pathspecShown))
+	}
+	options, untracked := []string{"clean", "-f", "-d"}, "--include-untracked"
+	if m.cleanList.ignored {
+		options, untracked = append(options, "-x"), "--all"
+	}
+	m.confirm = &confirmation{
+		message: tr("Delete %d untracked file(s)?", len(paths)),
+		details: details,
+		onYes: func(m *model) {
+			r, trash := m.repo, m.repo.newTrashDir()
+			m.closeClean()
+			m.queue(m.withSafetyStash("clean", tr("Deleting"), untracked, batchSteps(paths, func(paths []string) error {
+				// A batch that can't be kept in the trash isn't deleted
+				if err := copyToTrash(r, trash, paths); err != nil {
+					return errors.New(tr("failed to copy the files to the trash: %v", err))
+				}
+				return r.atRoot().run(slices.Concat(options, []string{"--"}, paths)...)
+			})))
+		},
+	}
+}
+
+func (m model) cleanView(width, height int) string {
+	cl := m.cleanList
+	title := tr("Untracked files")
+	if cl.ignored {
+		title = tr("Untracked and ignored files")
+	}
+	rows := []string{sectionStyle.Render(ansi.Truncate(title, width, "…"))}
+	if len(cl.candidates) == 0 {
+		return rows[0] + "\n" + tr("Nothing to clean")
+	}
+	offset := max(cl.cursor+1-(height-1), 0)
+	for i := offset; i < len(cl.candidates) && len(rows) < height; i++ {
+		c := cl.candidates[i]
+		glyph := cfg.Glyphs.Unstaged
+		if c.selected {
+			glyph = cfg.Glyphs.Staged
+		}
+		row := cfg.Glyphs.cursor(i == cl.cursor) + glyph + " " + c.pathFromGitRoot
+		if c.ignored {
+			row += " " + badgeStyle.Render(tr("ignored"))
+		}
+		rows = append(rows, ansi.Truncate(row, width, "…"))
+	}
+	return strings.Join(rows, "\n")
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/hzqtc/git-istage/gitx"
+)
+
+// Number of commands kept for the log pane
+const commandLogSize = 1000
+
+// A git command run by the tool, as shown in the command log
+type loggedCommand struct {
+	start    time.Time
+	dir      string
+	args     []string
+	duration time.Duration
+	result   string // exit status, or why the command didn't finish
+	failed   bool
+}
+
+func (c loggedCommand) String() string {
+	return fmt.Sprintf("%s %s %s %s (%s)", c.start.Format(time.RFC3339), c.dir,
+		strings.Join(c.args, " "), c.result, c.duration.Round(time.Millisecond))
+}
+
+var commandLog = struct {
+	sync.Mutex
+	entries []loggedCommand
+	file    *os.File
+}{}
+
+func init() {
+	gitx.Finished = logCommand
+}
+
+// Also append every command to a file, for auditing outside the log pane
+func openCommandLogFile(path string) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	commandLog.file = file
+	return nil
+}
+
+func logCommand(cmd *exec.Cmd, start time.Time, err error) {
+	entry := loggedCommand{
+		start:    start,
+		dir:      cmd.Dir,
+		args:     append([]string{filepath.Base(cmd.Path)}, cmd.Args[1:]...),
+		duration: time.Since(start),
+		result:   "exit 0",
+	}
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr) && exitErr.ExitCode() >= 0:
+		entry.result = fmt.Sprintf("exit %d", exitErr.ExitCode())
+		entry.failed = true
+	case err != nil:
+		entry.result = err.Error()
+		entry.failed = true
+	}
+
+	recordGitTime(entry)
+	if gitx.IsTimeout(err) {
+		gitTimeout.Lock()
+		gitTimeout.err = err
+		gitTimeout.Unlock()
+	}
+
+	commandLog.Lock()
+	defer commandLog.Unlock()
+	commandLog.entries = append(commandLog.entries, entry)
+	if len(commandLog.entries) > commandLogSize {
+		commandLog.entries = commandLog.entries[len(commandLog.entries)-commandLogSize:]
+	}
+	if commandLog.file != nil {
+		fmt.Fprintln(commandLog.file, entry)
+	}
+}
+
+func loggedCommands() []loggedCommand {
+	commandLog.Lock()
+	defer commandLog.Unlock()
+	return append([]loggedCommand(nil), commandLog.entries...)
+}
+
+// Hand the terminal to a git command, like tea.ExecProcess
+func execGit(c *gitx.Cmd, fn tea.ExecCallback) tea.Cmd {
+	start := time.Now()
+	return tea.ExecProcess(c.Cmd, func(err error) tea.Msg {
+		logCommand(c.Cmd, start, err)
+		return fn(err)
+	})
+}
+
+// The most recent commands, newest at the bottom, scrolled up by logScroll
+func (m model) commandLogView(width, height int) string {
+	entries := loggedCommands()
+	if len(entries) == 0 {
+		return tr("No git commands run yet")
+	}
+	end := max(len(entries)-m.logScroll, 1)
+	start := max(end-height, 0)
+	var rows []string
+	for _, c := range entries[start:end] {
+		status := stagedStyle.Render(c.result)
+		if c.failed {
+			status = conflictStyle.Render(c.result)
+		}
+		row := fmt.Sprintf("%s %s %s %s", badgeStyle.Render(c.start.Format("15:04:05")),
+			strings.Join(c.args, " "), status, badgeStyle.Render(c.duration.Round(time.Millisecond).String()))
+		rows = append(rows, ansi.Truncate(row, width, "…"))
+	}
+	return strings.Join(rows, "\n")
+}
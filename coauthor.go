@@ -0,0 +1,63 @@
+package main
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Authors of this many recent commits are offered as co-authors
+const recentAuthorCommits = 500
+
+// The configured collaborators first, then recent authors of the repository
+// other than the current user
+func coAuthorCandidates(r repo) []string {
+	candidates := slices.Clone(cfg.Commit.CoAuthors)
+	output, _ := r.git("config", "user.email").Output()
+	self := strings.TrimSpace(string(output))
+	output, _ = r.git("log", "-n", strconv.Itoa(recentAuthorCommits), "--format=%aN <%aE>").Output()
+	for _, author := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if author == "" || (self != "" && strings.HasSuffix(author, "<"+self+">")) {
+			continue
+		}
+		if !slices.Contains(candidates, author) {
+			candidates = append(candidates, author)
+		}
+	}
+	return candidates
+}
+
+func (m *model) openCoAuthorPicker() {
+	var candidates []string
+	for _, c := range coAuthorCandidates(m.repo) {
+		// Skip the ones already credited
+		if !strings.Contains(m.editor.Value(), coAuthorTrailer(c)) {
+			candidates = append(candidates, c)
+		}
+	}
+	if len(candidates) == 0 {
+		m.status = tr("No co-authors to pick, add some to co_authors in the [commit] config")
+		return
+	}
+	m.picker = newPicker(tr("Co-author: "), candidates, func(m *model, author string) {
+		m.editor.SetValue(addTrailer(m.editor.Value(), coAuthorTrailer(author)))
+	})
+}
+
+func coAuthorTrailer(author string) string {
+	return "Co-authored-by: " + author
+}
+
+var trailerLine = regexp.MustCompile(`^[A-Za-z0-9-]+: `)
+
+// Append a trailer to a commit message, in the trailer block at the end if
+// there is one, or in a new paragraph
+func addTrailer(message, trailer string) string {
+	message = strings.TrimRight(message, "\n")
+	lines := strings.Split(message, "\n")
+	if len(lines) < 3 || !trailerLine.MatchString(lines[len(lines)-1]) {
+		message += "\n"
+	}
+	return message + "\n" + trailer
+}
@@ -0,0 +1,342 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/hzqtc/git-istage/gitx"
+)
+
+type messageGeneratedMsg struct {
+	root      string
+	message   string
+	err       error
+	cancelled bool
+}
+
+type commitFinishedMsg struct {
+	root  string
+	paths []string // files in the commit
+	err   error
+}
+
+// Whether keys are typed into a text field rather than being commands
+func (m model) typing() bool {
+	return m.mode == commitMode || m.note != nil || m.pathspec != nil || m.moving != nil || m.newBranch != nil || m.rangeInput != nil || m.searchInput != nil || m.exportInput != nil || m.gitNote != nil || (m.settings != nil && m.settings.input != nil)
+}
+
+// Paths from the git root of the files with staged changes
+func (m model) stagedPaths() []string {
+	var paths []string
+	for _, f := range m.files {
+		if f.status == staged || f.status == partiallyStaged {
+			paths = append(paths, f.pathFromGitRoot)
+		}
+	}
+	return paths
+}
+
+func checkCommitConfig(c commitConfig) error {
+	if _, err := regexp.Compile(c.BranchPattern); err != nil {
+		return errors.New(tr("Invalid branch_pattern in [commit]: %v", err))
+	}
+	if err := checkGitmoji(c.Gitmoji); err != nil {
+		return err
+	}
+	return checkTrailers(c)
+}
+
+// The start of the subject line taken from the branch name, like "ABC-123: "
+// for feat/ABC-123-login
+func branchSubject(branch string) string {
+	if cfg.Commit.BranchPattern == "" {
+		return ""
+	}
+	re, err := regexp.Compile(cfg.Commit.BranchPattern)
+	if err != nil {
+		return ""
+	}
+	match := re.FindStringSubmatchIndex(branch)
+	if match == nil {
+		return ""
+	}
+	return string(re.ExpandString(nil, cfg.Commit.SubjectTemplate, branch, match))
+}
+
+func newCommitEditor() *textarea.Model {
+	editor := textarea.New()
+	editor.Placeholder = tr("Commit message")
+	editor.ShowLineNumbers = false
+	editor.CharLimit = 0
+	editor.MaxHeight = 0
+	editor.Cursor.SetMode(cursor.CursorStatic)
+	editor.Focus()
+	return &editor
+}
+
+// Quit for git commit to run in the terminal, with the editor git is set up
+// with, see commitInEditor
+func (m *model) handOffCommit() tea.Cmd {
+	if len(m.stagedPaths()) == 0 {
+		m.status = tr("Nothing staged to commit")
+		return nil
+	}
+	m.quitting, m.handOff = true, true
+	return tea.Quit
+}
+
+// Run git commit on the index the program left, in the terminal it left, and
+// return the exit code to end the program with
+func (r repo) commitInEditor() int {
+	args := []string{"commit"}
+	if cfg.Commit.Verbose {
+		args = append(args, "--verbose")
+	}
+	cmd := r.atRoot().interactiveGit(args...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr):
+		return exitErr.ExitCode()
+	case err != nil:
+		fmt.Println(tr("Error:"), err)
+		return 1
+	}
+	return 0
+}
+
+// Open the commit message editor, keeping the draft of a commit that was
+// cancelled, and offer to add the review notes of the staged files
+func (m *model) openCommitEditor() {
+	paths := m.stagedPaths()
+	if len(paths) == 0 {
+		m.status = tr("Nothing staged to commit")
+		return
+	}
+	if m.editor == nil {
+		m.editor = newCommitEditor()
+		m.editor.SetValue(branchSubject(m.branch))
+		m.trailers = defaultTrailers(m.repo)
+	}
+	m.mode = commitMode
+	m.sizeEditor()
+	bullets := m.noteBullets(paths)
+	if bullets == "" || strings.Contains(m.editor.Value(), bullets) {
+		return
+	}
+	m.ask(tr("Add the review notes of %d staged file(s) to the message?", strings.Count(bullets, "\n")+1), func(m *model) {
+		message := strings.TrimRight(m.editor.Value(), "\n")
+		if message == "" {
+			// Leave the subject line to write
+			message = "\n"
+		}
+		m.editor.SetValue(message + "\n\n" + bullets)
+		// Back to the subject line
+		for m.editor.Line() > 0 {
+			m.editor.CursorUp()
+		}
+		m.editor.CursorStart()
+	})
+}
+
+func (m *model) updateCommit(msg tea.KeyMsg) tea.Cmd {
+	if m.committing {
+		return nil
+	}
+	if m.picker != nil {
+		m.updatePicker(msg)
+		return nil
+	}
+	if m.trailersEditor != nil {
+		m.updateTrailersEditor(msg)
+		return nil
+	}
+	if m.authorEditor != nil {
+		m.updateAuthorDateEditor(msg)
+		return nil
+	}
+	if m.generating {
+		if m.keys.cancel.matches(keyName(msg)) {
+			cancelGitCommands(m.repo.root)
+		}
+		return nil
+	}
+	switch key := keyName(msg); {
+	case m.keys.generateMessage.matches(key):
+		return m.generateMessage()
+	case m.keys.coAuthor.matches(key):
+		m.openCoAuthorPicker()
+	case m.keys.trailers.matches(key):
+		m.openTrailersEditor()
+	case m.keys.authorDate.matches(key):
+		m.openAuthorDateEditor()
+	case m.keys.gitmoji.matches(key):
+		m.openGitmojiPicker()
+	case m.keys.commitSubmit.matches(key) && m.job != nil:
+		m.status = tr("Wait for %s to finish or press %s to cancel", strings.ToLower(m.job.title), m.keys.cancel.Help().Key)
+	case m.keys.commitSubmit.matches(key):
+		return m.commit()
+	case m.keys.cancel.matches(key):
+		m.mode = listMode
+	default:
+		var cmd tea.Cmd
+		*m.editor, cmd = m.editor.Update(msg)
+		if key == "#" && cfg.Commit.Issues != "" {
+			return tea.Batch(cmd, m.completeIssue())
+		}
+		return cmd
+	}
+	return nil
+}
+
+// Commit the index with the message from the editor, in the background since
+// hooks may take a while
+func (m *model) commit() tea.Cmd {
+	message := strings.TrimSpace(m.editor.Value())
+	if message == "" {
+		m.status = tr("Aborting commit due to empty commit message")
+		return nil
+	}
+	m.committing = true
+	r, paths, trailers, options := m.repo, m.stagedPaths(), m.trailers, m.authorDate.options()
+	return func() tea.Msg {
+		message, err := withTrailers(r, message, trailers)
+		if err != nil {
+			return commitFinishedMsg{r.root, paths, err}
+		}
+		return commitFinishedMsg{r.root, paths, r.backend.Commit(message, options...)}
+	}
+}
+
+func (m *model) commitFinished(msg commitFinishedMsg) {
+	m.committing = false
+	if msg.err != nil {
+		m.status = tr("Commit failed: %v", msg.err)
+		return
+	}
+	for _, path := range msg.paths {
+		delete(m.notes, path)
+	}
+	m.editor = nil
+	m.trailers = nil
+	m.authorDate = authorDate{}
+	m.mode = listMode
+	m.reload()
+	m.loadDiff()
+	subject, _ := m.repo.git("log", "-1", "--format=%h %s").Output()
+	m.status = tr("Committed %s, %s to attach a git note", strings.TrimSpace(string(subject)), m.keys.recentCommits.Help().Key)
+	m.offerPullRequest()
+}
+
+// Run the configured message generator on the staged diff, in the background
+// since it may take a while, e.g. when it asks a language model
+func (m *model) generateMessage() tea.Cmd {
+	if cfg.Commit.Generator == "" {
+		m.status = tr("Set generator in the [commit] config to generate messages")
+		return nil
+	}
+	m.generating = true
+	r := m.repo
+	return func() tea.Msg {
+		ctx, generation := gitContext(r.root)
+		diff, err := r.git("diff", "--cached", "--no-color", "--no-ext-diff").Output()
+		if err != nil {
+			return messageGeneratedMsg{root: r.root, err: err}
+		}
+		cmd := exec.CommandContext(ctx, "sh", "-c", cfg.Commit.Generator)
+		cmd.Dir = r.root
+		cmd.Stdin = bytes.NewReader(diff)
+		cmd.WaitDelay = gitx.KillWaitDelay
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		output, err := cmd.Output()
+		if err != nil {
+			if msg := firstLine(stderr.String()); msg != "" {
+				err = errors.New(msg)
+			}
+		}
+		return messageGeneratedMsg{r.root, strings.TrimSpace(string(output)), err, gitCancelledSince(r.root, generation)}
+	}
+}
+
+// Put the generated message in the editor, asking before replacing one
+// that's already written
+func (m *model) messageGenerated(msg messageGeneratedMsg) {
+	m.generating = false
+	switch {
+	case msg.cancelled:
+		m.status = tr("Cancelled generating the message")
+		return
+	case msg.err != nil:
+		m.status = tr("Message generator failed: %v", msg.err)
+		return
+	case msg.message == "":
+		m.status = tr("The message generator printed nothing")
+		return
+	}
+	replace := func(m *model) {
+		if m.editor != nil {
+			m.editor.SetValue(msg.message)
+		}
+	}
+	if m.editor == nil || strings.TrimSpace(m.editor.Value()) == "" {
+		replace(m)
+		return
+	}
+	m.ask(tr("Replace the message with the generated one?"), replace)
+}
+
+// Fill the body below the title of the commit view
+func (m *model) sizeEditor() {
+	if m.editor != nil {
+		m.editor.SetWidth(m.width)
+		m.editor.SetHeight(max(m.bodyHeight()-2, 1))
+	}
+}
+
+func (m model) commitView(width, height int) string {
+	if m.picker != nil {
+		return m.pickerView(width, height)
+	}
+	if m.trailersEditor != nil {
+		return m.trailersEditorView(width, height)
+	}
+	if m.authorEditor != nil {
+		return m.authorDateEditorView(width, height)
+	}
+	title := tr("Commit %d staged file(s)", len(m.stagedPaths()))
+	switch {
+	case m.committing:
+		title = tr("Committing…")
+	case m.generating:
+		title = tr("Generating the message, %s to cancel", m.keys.cancel.Help().Key)
+	}
+	details := ansi.Truncate(m.detailsLine(), width, "…")
+	return sectionStyle.Render(ansi.Truncate(title, width, "…")) + "\n" + details + "\n" + m.editor.View()
+}
+
+// The author, date and trailers below the title of the commit view, like
+// "Date: 2024-05-01T14:30:00+02:00 · Refs: ABC-1 · Fixes: #2"
+func (m model) detailsLine() string {
+	var parts []string
+	if m.authorDate.author != "" {
+		parts = append(parts, tr("Author: ")+m.authorDate.author)
+	}
+	if m.authorDate.date != "" {
+		parts = append(parts, tr("Date: ")+m.authorDate.date)
+	}
+	for _, t := range m.trailers {
+		parts = append(parts, t.String())
+	}
+	return badgeStyle.Render(strings.Join(parts, " · "))
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Shells `git-istage completion` writes a script for
+var completionShells = []string{"bash", "zsh", "fish"}
+
+// What the values of the flags that take one complete to: one of words, or
+// a file for fileFlags. Numbers and other values complete to nothing, and
+// boolean flags take none.
+var flagValues = map[string]func() []string{
+	"theme":   themeNames,
+	"profile": func() []string { return profileKinds },
+}
+
+var fileFlags = []string{"workspace", "record", "replay"}
+
+type completedFlag struct {
+	name, usage string
+	boolean     bool
+	file        bool
+	words       []string // the values it takes
+}
+
+func completedFlags() []completedFlag {
+	var flags []completedFlag
+	flag.VisitAll(func(f *flag.Flag) {
+		c := completedFlag{name: f.Name, usage: f.Usage, file: slices.Contains(fileFlags, f.Name)}
+		if b, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && b.IsBoolFlag() {
+			c.boolean = true
+		} else if words, ok := flagValues[f.Name]; ok {
+			c.words = slices.Sorted(slices.Values(words()))
+		}
+		flags = append(flags, c)
+	})
+	return flags
+}
+
+// The subcommands, with the words their arguments complete to
+type completedSubcommands struct {
+	shells, config, settings string
+}
+
+func subcommandWords() completedSubcommands {
+	c := defaultConfig()
+	var settings []string
+	for _, s := range settingsOf(&c) {
+		settings = append(settings, s.name)
+	}
+	return completedSubcommands{
+		shells:   strings.Join(completionShells, " "),
+		config:   strings.Join(slices.Sorted(maps.Keys(configCommands)), " "),
+		settings: strings.Join(settings, " "),
+	}
+}
+
+// Write the completion script for shell, completing the flags, their values,
+// the subcommands and repository directories, for both git-istage and git
+// istage
+func writeCompletion(w io.Writer, shell string) error {
+	flags, sub := completedFlags(), subcommandWords()
+	switch shell {
+	case "bash":
+		writeBashCompletion(w, flags, sub)
+	case "zsh":
+		writeZshCompletion(w, flags, sub)
+	case "fish":
+		writeFishCompletion(w, flags, sub)
+	default:
+		return errors.New(tr("Unknown shell %q, completion is available for %v", shell, completionShells))
+	}
+	return nil
+}
+
+func writeBashCompletion(w io.Writer, flags []completedFlag, sub completedSubcommands) {
+	var names, files, others []string
+	fmt.Fprintln(w, "# bash completion for git-istage, from `git-istage completion bash`")
+	fmt.Fprintln(w, "# Takes the word to complete, the one before and the arguments before it")
+	fmt.Fprintln(w, "__git_istage_reply() {")
+	fmt.Fprintln(w, "\tlocal cur=$1 prev=$2")
+	fmt.Fprintln(w, "\tshift 2")
+	fmt.Fprintln(w, "\tcase $1 in")
+	fmt.Fprintf(w, "\tcompletion) (($# == 1)) && COMPREPLY=($(compgen -W %q -- \"$cur\")); return ;;\n", sub.shells)
+	fmt.Fprintln(w, "\tconfig)")
+	fmt.Fprintf(w, "\t\tif (($# == 1)); then\n\t\t\tCOMPREPLY=($(compgen -W %q -- \"$cur\"))\n", sub.config)
+	fmt.Fprintf(w, "\t\telif (($# == 2)) && [[ $2 == get || $2 == set ]]; then\n\t\t\tCOMPREPLY=($(compgen -W %q -- \"$cur\"))\n\t\tfi\n", sub.settings)
+	fmt.Fprintln(w, "\t\treturn ;;")
+	fmt.Fprintln(w, "\tesac")
+	fmt.Fprintln(w, "\tcase $prev in")
+	for _, f := range flags {
+		names = append(names, "--"+f.name)
+		switch {
+		case f.boolean:
+		case f.words != nil:
+			fmt.Fprintf(w, "\t-%s|--%[1]s) COMPREPLY=($(compgen -W %q -- \"$cur\")); return ;;\n", f.name, strings.Join(f.words, " "))
+		case f.file:
+			files = append(files, "-"+f.name+"|--"+f.name)
+		default:
+			others = append(others, "-"+f.name+"|--"+f.name)
+		}
+	}
+	if files != nil {
+		fmt.Fprintf(w, "\t%s) COMPREPLY=($(compgen -f -- \"$cur\")); return ;;\n", strings.Join(files, "|"))
+	}
+	if others != nil {
+		fmt.Fprintf(w, "\t%s) return ;;\n", strings.Join(others, "|"))
+	}
+	fmt.Fprintln(w, "\tesac")
+	fmt.Fprintf(w, "\tif [[ $cur == -* ]]; then\n\t\tCOMPREPLY=($(compgen -W %q -- \"$cur\"))\n", strings.Join(names, " "))
+	fmt.Fprintln(w, "\telif (($# == 0)); then\n\t\tCOMPREPLY=($(compgen -W \"completion config\" -- \"$cur\") $(compgen -d -- \"$cur\"))")
+	fmt.Fprintln(w, "\telse\n\t\tCOMPREPLY=($(compgen -d -- \"$cur\"))\n\tfi")
+	fmt.Fprintln(w, "}")
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "__git_istage() {")
+	fmt.Fprintln(w, "\t__git_istage_reply \"${COMP_WORDS[COMP_CWORD]}\" \"${COMP_WORDS[COMP_CWORD-1]}\" \"${COMP_WORDS[@]:1:COMP_CWORD-1}\"")
+	fmt.Fprintln(w, "}")
+	fmt.Fprintln(w, "complete -o filenames -F __git_istage git-istage")
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "# Called by git's own completion for `git istage`, which sets cur, prev, words and cword")
+	fmt.Fprintln(w, "_git_istage() {")
+	fmt.Fprintln(w, "\tcompopt -o filenames 2>/dev/null")
+	fmt.Fprintln(w, "\t__git_istage_reply \"$cur\" \"$prev\" \"${words[@]:2:cword-2}\"")
+	fmt.Fprintln(w, "}")
+}
+
+// Escape text for the description of an _arguments spec
+func zshDescription(text string) string {
+	return strings.NewReplacer("'", `'\''`, "[", `\[`, "]", `\]`, ":", `\:`).Replace(text)
+}
+
+func writeZshCompletion(w io.Writer, flags []completedFlag, sub completedSubcommands) {
+	// git's completion calls _git-istage for `git istage` too, with the
+	// words from istage on
+	fmt.Fprintln(w, "#compdef git-istage")
+	fmt.Fprintln(w, "# zsh completion for git-istage, from `git-istage completion zsh`")
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "_git-istage() {")
+	fmt.Fprintln(w, "\tif ((CURRENT > 2)); then")
+	fmt.Fprintln(w, "\t\tcase $words[2] in")
+	fmt.Fprintf(w, "\t\tcompletion) ((CURRENT == 3)) && compadd %s; return ;;\n", sub.shells)
+	fmt.Fprintln(w, "\t\tconfig)")
+	fmt.Fprintf(w, "\t\t\tif ((CURRENT == 3)); then\n\t\t\t\tcompadd %s\n", sub.config)
+	fmt.Fprintf(w, "\t\t\telif ((CURRENT == 4)) && [[ $words[3] == (get|set) ]]; then\n\t\t\t\tcompadd %s\n\t\t\tfi\n", sub.settings)
+	fmt.Fprintln(w, "\t\t\treturn ;;")
+	fmt.Fprintln(w, "\t\tesac")
+	fmt.Fprintln(w, "\telif [[ $PREFIX != -* ]]; then")
+	fmt.Fprintln(w, "\t\tcompadd completion config")
+	fmt.Fprintln(w, "\tfi")
+	fmt.Fprintln(w, "\t_arguments -S \\")
+	for _, f := range flags {
+		spec := fmt.Sprintf("--%s[%s]", f.name, zshDescription(f.usage))
+		switch {
+		case f.boolean:
+		case f.words != nil:
+			spec += fmt.Sprintf(":%s:(%s)", f.name, strings.Join(f.words, " "))
+		case f.file:
+			spec += fmt.Sprintf(":%s:_files", f.name)
+		default:
+			spec += fmt.Sprintf(":%s: ", f.name)
+		}
+		fmt.Fprintf(w, "\t\t'%s' \\\n", spec)
+	}
+	fmt.Fprintln(w, "\t\t'*:repository:_directories'")
+	fmt.Fprintln(w, "}")
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, `if [[ $zsh_eval_context[-1] == loadautofunc ]]; then`)
+	fmt.Fprintln(w, "\t_git-istage \"$@\"")
+	fmt.Fprintln(w, "else")
+	fmt.Fprintln(w, "\tcompdef _git-istage git-istage")
+	fmt.Fprintln(w, "fi")
+}
+
+func writeFishCompletion(w io.Writer, flags []completedFlag, sub completedSubcommands) {
+	// The arguments of config complete by how many words come before
+	const words = "test (count (commandline -opc)) -eq"
+	fmt.Fprintln(w, "# fish completion for git-istage, from `git-istage completion fish`")
+	fmt.Fprintln(w, "complete -c git-istage -f -n 'not __fish_seen_subcommand_from completion config' -a '(__fish_complete_directories)'")
+	fmt.Fprintln(w, "complete -c git-istage -f -n __fish_use_subcommand -a 'completion config'")
+	fmt.Fprintf(w, "complete -c git-istage -f -n '__fish_seen_subcommand_from completion; and %s 2' -a '%s'\n", words, sub.shells)
+	fmt.Fprintf(w, "complete -c git-istage -f -n '__fish_seen_subcommand_from config; and %s 2' -a '%s'\n", words, sub.config)
+	fmt.Fprintf(w, "complete -c git-istage -f -n '__fish_seen_subcommand_from get set; and %s 3' -a '%s'\n", words, sub.settings)
+	for _, f := range flags {
+		line := fmt.Sprintf("complete -c git-istage -l %s", f.name)
+		switch {
+		case f.boolean:
+		case f.words != nil:
+			line += fmt.Sprintf(" -x -a '%s'", strings.Join(f.words, " "))
+		case f.file:
+			line += " -r -F"
+		default:
+			line += " -x"
+		}
+		fmt.Fprintf(w, "%s -d '%s'\n", line, strings.ReplaceAll(f.usage, "'", `\'`))
+	}
+}
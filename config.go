@@ -0,0 +1,176 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+)
+
+type config struct {
+	Theme string `toml:"theme"`
+	// Language of the interface, from the locale if unset
+	Language   string `toml:"language"`
+	ShowFooter bool   `toml:"show_footer"`
+	// Set the terminal title to the repository and branch while running
+	TerminalTitle bool `toml:"terminal_title"`
+	// Group the file list into sections like git status instead of a flat list
+	GroupByStatus bool         `toml:"group_by_status"`
+	Sparse        sparseConfig `toml:"sparse"`
+	// Lay out a list longer than the screen in as many columns as fit
+	ListColumns bool `toml:"list_columns"`
+	// Number the files of the list for the digit keys to jump to
+	FileNumbers bool `toml:"file_numbers"`
+	// Show paths from the repository root, the current directory, or absolute
+	Paths pathDisplay `toml:"paths"`
+	// Stash changes before discarding or cleaning many files at once
+	AutoStash bool `toml:"auto_stash"`
+	// Append every git command run to this file
+	LogFile string `toml:"log_file"`
+	// How long a git command may run before it's killed, like "2m", without
+	// limit when "0"
+	GitTimeout string `toml:"git_timeout"`
+	// Stands for <leader> in the key sequences of [keys]
+	Leader  string              `toml:"leader"`
+	Keys    map[string][]string `toml:"keys"`
+	Glyphs  glyphConfig         `toml:"glyphs"`
+	Diff    diffConfig          `toml:"diff"`
+	Commit  commitConfig        `toml:"commit"`
+	Secrets secretsConfig       `toml:"secrets"`
+	// External tools bound to keys
+	Launchers []launcherConfig `toml:"launchers"`
+	// Commands formatting a file by a glob of its name, run with sh in the
+	// repository root with the file's path in $FILE
+	Formatters map[string]string `toml:"formatters"`
+}
+
+type diffConfig struct {
+	// Lines the diff can scroll past its end, like an editor's scroll past end
+	ScrollPastEnd int `toml:"scroll_past_end"`
+	// Lines of context kept above a hunk when jumping to it, like scrolloff
+	ScrollOff int `toml:"scrolloff"`
+	// How similar in percent a deleted and an added file must be to count
+	// as a rename, git's default if 0
+	FindRenames int `toml:"find_renames"`
+	// Color lines that only moved, within or between files, apart from
+	// other added and removed lines
+	ColorMoved bool `toml:"color_moved"`
+	// Scroll to the next hunk still to stage or unstage after staging or
+	// unstaging one, like the y/n rhythm of git add -p
+	AdvanceHunks bool `toml:"advance_hunks"`
+	// Unchanged lines kept around a change when folding the context
+	FoldContext int `toml:"fold_context"`
+	// Color the diff with the color.diff slots of the git config over the
+	// theme, and color moved lines when diff.colorMoved is set
+	GitColors bool `toml:"git_colors"`
+}
+
+type commitConfig struct {
+	// Shell command that gets the staged diff on stdin and prints a commit message
+	Generator string `toml:"generator"`
+	// Regular expression matched against the branch name, and the subject
+	// line to start with when it matches, with $1 for its first group
+	BranchPattern   string `toml:"branch_pattern"`
+	SubjectTemplate string `toml:"subject_template"`
+	// People to offer for Co-authored-by trailers, as "Name <email>"
+	CoAuthors []string `toml:"co_authors"`
+	// Shell command printing the open issues to complete a # in the message
+	// with, as JSON like `gh issue list --json number,title` or as lines of a
+	// number and a title
+	Issues string `toml:"issues"`
+	// Start the subject with a gitmoji picked in the editor, as its "code" or
+	// as the "emoji" itself, off when empty
+	Gitmoji string `toml:"gitmoji"`
+	// Keys of the trailers to fill in with the trailers editor
+	Trailers []string `toml:"trailers"`
+	// Trailers each commit starts with, as "Key: value", by a glob of the
+	// repository's directory name or path
+	DefaultTrailers map[string][]string `toml:"default_trailers"`
+	// Run git commit --verbose when handing off to git's editor
+	Verbose bool `toml:"verbose"`
+	// Shell command offered after a commit, once the branch is pushed, like
+	// `gh pr create --fill` or `glab mr create`
+	PullRequest string `toml:"pull_request"`
+}
+
+type sparseConfig struct {
+	// Leave files outside the sparse-checkout cone out of the list
+	HideOutside bool `toml:"hide_outside"`
+	// Ask before staging files outside the cone, which materializes them in the index
+	Warn bool `toml:"warn"`
+}
+
+func defaultConfig() config {
+	return config{
+		ShowFooter:    true,
+		TerminalTitle: true,
+		GroupByStatus: true,
+		Leader:        ",",
+		AutoStash:     true,
+		GitTimeout:    "2m",
+		Paths:         pathsFromRoot,
+		Glyphs:        defaultGlyphs(),
+		Sparse: sparseConfig{
+			Warn: true,
+		},
+		Diff: diffConfig{
+			AdvanceHunks: true,
+			FoldContext:  1,
+			GitColors:    true,
+		},
+		Commit: commitConfig{
+			Trailers: defaultTrailerKeys(),
+		},
+		Secrets: secretsConfig{
+			Patterns: defaultSecretPatterns(),
+		},
+	}
+}
+
+func configPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "git-istage", "config.toml")
+}
+
+// Load the config file, a missing file is not an error
+func loadConfig() (config, error) {
+	cfg := defaultConfig()
+	path := configPath()
+	if path == "" {
+		return cfg, nil
+	}
+	if _, err := toml.DecodeFile(path, &cfg); err != nil && !os.IsNotExist(err) {
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+// Check the values of a config that can be wrong, like an unknown theme or
+// a pattern that doesn't compile
+func checkConfig(c config) error {
+	if c.Diff.FindRenames < 0 || c.Diff.FindRenames > 100 {
+		return errors.New(tr("find_renames must be a percentage from 1 to 100"))
+	}
+	if c.Theme != "" {
+		if _, err := findTheme(c.Theme); err != nil {
+			return err
+		}
+	}
+	if _, err := findCatalog(c.Language); err != nil {
+		return err
+	}
+	if _, err := parseGitTimeout(c.GitTimeout); err != nil {
+		return err
+	}
+	for _, err := range []error{checkGlyphs(c.Glyphs), checkCommitConfig(c.Commit), checkFormatters(c.Formatters),
+		checkSecretsConfig(c.Secrets), checkPathDisplay(c.Paths)} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
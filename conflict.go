@@ -0,0 +1,89 @@
+package main
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Resolve a conflict by taking one side of the merge as a whole and
+// marking the file resolved. A side that deleted the file resolves by
+// removing it.
+func resolveConflict(r repo, f fileEntry, side string) error {
+	x, y := f.xy[0], f.xy[1]
+	deleted := (side == "ours" && (x == 'D' || f.xy == "UA")) ||
+		(side == "theirs" && (y == 'D' || f.xy == "AU"))
+	if deleted {
+		return r.run("rm", "-q", "--", f.pathFromCwd)
+	}
+	if err := r.run("checkout", "--"+side, "--", f.pathFromCwd); err != nil {
+		return err
+	}
+	return r.backend.Stage(f.pathFromCwd)
+}
+
+// Ask to resolve a conflicted file with one side, with its merge diff in view
+func (m *model) resolveWith(index int, side string) {
+	f := m.files[index]
+	if f.status != conflicted {
+		m.status = tr("%s has no conflict to resolve", f.pathFromGitRoot)
+		return
+	}
+
+	// Make sure the merge diff is on screen while deciding
+	fullScreen := m.fullScreenDiff
+	if m.width < minSplitWidth {
+		m.fullScreenDiff = true
+	}
+	m.loadDiff()
+	m.confirm = &confirmation{
+		message: tr("Resolve %s using %s?", f.pathFromGitRoot, side),
+		onYes: func(m *model) {
+			m.fullScreenDiff = fullScreen
+			if err := resolveConflict(m.repo, f, side); m.handleIndexLock(err) {
+				return
+			} else if err != nil {
+				m.status = tr("Failed to check out %s version of %s", side, f.pathFromGitRoot)
+			} else {
+				m.status = tr("Resolved %s using %s", f.pathFromGitRoot, side)
+			}
+			m.reload()
+			m.loadDiff()
+		},
+		onNo: func(m *model) {
+			m.fullScreenDiff = fullScreen
+		},
+	}
+}
+
+type mergetoolFinishedMsg struct {
+	path string
+	err  error
+}
+
+// Suspend the UI and run the configured merge tool on a conflicted file
+func (m *model) runMergetool(index int) tea.Cmd {
+	f := m.files[index]
+	if f.status != conflicted {
+		m.status = f.pathFromGitRoot + " has no conflict to resolve"
+		return nil
+	}
+	cmd := m.repo.interactiveGit("mergetool", "--", f.pathFromCwd)
+	return execGit(cmd, func(err error) tea.Msg {
+		return mergetoolFinishedMsg{f.pathFromGitRoot, err}
+	})
+}
+
+func (m *model) mergetoolFinished(msg mergetoolFinishedMsg) {
+	m.reload()
+	m.loadDiff()
+	for _, f := range m.files {
+		if f.pathFromGitRoot == msg.path && f.status == conflicted {
+			m.status = tr("%s is still conflicted", msg.path)
+			return
+		}
+	}
+	if msg.err != nil {
+		m.status = tr("git mergetool failed: %v", msg.err)
+	} else {
+		m.status = tr("Resolved %s", msg.path)
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"runtime/debug"
+	"sync"
+)
+
+// Number of git commands a crash report ends with
+const crashCommands = 20
+
+// A panic in Update or View. The program quits on it, so the terminal is
+// restored from the alternate screen before the report is printed.
+type crash struct {
+	sync.Mutex
+	value any
+	stack []byte
+}
+
+// Keep the panic being recovered from, with where it happened
+func (c *crash) catch(value any) {
+	c.Lock()
+	defer c.Unlock()
+	if c.value == nil {
+		c.value, c.stack = value, debug.Stack()
+	}
+}
+
+func (c *crash) happened() bool {
+	c.Lock()
+	defer c.Unlock()
+	return c.value != nil
+}
+
+// The panic, its stack trace and the git commands run last
+func (c *crash) report(w io.Writer) {
+	c.Lock()
+	defer c.Unlock()
+	fmt.Fprintf(w, tr("git-istage crashed: %v")+"\n\n%s\n", c.value, c.stack)
+	writeLastCommands(w)
+}
+
+func writeLastCommands(w io.Writer) {
+	commands := loggedCommands()
+	if len(commands) == 0 {
+		return
+	}
+	fmt.Fprintln(w, tr("Last git commands:"))
+	for _, c := range commands[max(0, len(commands)-crashCommands):] {
+		fmt.Fprintln(w, "  "+c.String())
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/hzqtc/git-istage/gitx"
+)
+
+// Run a command that may talk to a remote in the background, where git and
+// ssh fail rather than ask for credentials, a passphrase or to trust a host
+// on the terminal the UI is drawn on. Errors carry git's message.
+func runInBackground(cmd *gitx.Cmd) error {
+	cmd.Env = append(cmd.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if ssh := batchSSHCommand(cmd.Dir); ssh != "" {
+		cmd.Env = append(cmd.Env, "GIT_SSH_COMMAND="+ssh)
+	}
+	output, err := cmd.CombinedOutput()
+	if msg := firstLine(string(output)); err != nil && msg != "" && !gitx.IsTimeout(err) {
+		err = errors.New(strings.TrimPrefix(msg, "fatal: "))
+	}
+	return err
+}
+
+// The ssh command git would run, the user's own if set, told not to ask
+// anything. Empty for GIT_SSH, which may not be ssh to take the option.
+func batchSSHCommand(dir string) string {
+	ssh, ok := os.LookupEnv("GIT_SSH_COMMAND")
+	if !ok {
+		ssh = trimmedOutput(gitx.Command(dir, "config", "core.sshCommand").Output())
+	}
+	switch {
+	case ssh != "":
+	case os.Getenv("GIT_SSH") != "":
+		return ""
+	default:
+		ssh = "ssh"
+	}
+	return ssh + " -o BatchMode=yes"
+}
+
+// Whether runInBackground failed because git would have asked for a user
+// name or password, or ssh for a passphrase or whether to trust the host
+func needsCredentials(err error) bool {
+	if err == nil {
+		return false
+	}
+	for _, failure := range []string{"terminal prompts disabled", "Permission denied (", "Host key verification failed"} {
+		if strings.Contains(err.Error(), failure) {
+			return true
+		}
+	}
+	return false
+}
+
+// Run the command again with the terminal, the UI suspended meanwhile, for
+// git to ask for credentials there and its credential helper to keep them
+func runInTerminal(cmd *gitx.Cmd, done func(err error) tea.Msg) tea.Cmd {
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	return execGit(cmd, func(err error) tea.Msg {
+		if msg := firstLine(stderr.String()); err != nil && msg != "" {
+			err = errors.New(strings.TrimPrefix(msg, "fatal: "))
+		}
+		return done(err)
+	})
+}
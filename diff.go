@@ -0,0 +1,626 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+const tabWidth = 4
+
+// Lines of a diff loaded at first, so a huge generated file doesn't freeze
+// the diff pane. The rest is loaded when asked for, see loadFullDiff.
+const diffLineLimit = 20000
+
+// Run git diff the way it shows on the command line: textconv filters and
+// external diff drivers configured in .gitattributes are applied, but color
+// is left to the diff pane even if the user forces it in their git config.
+func (r repo) diff(args ...string) []byte {
+	output, _ := r.limitedDiff(-1, args...)
+	return output
+}
+
+// The first limit lines of the diff and how many more there are
+func (r repo) limitedDiff(limit int, args ...string) ([]byte, int) {
+	options := []string{"--textconv", "--ext-diff", "--no-color"}
+	if r.functionContext {
+		options = append(options, "--function-context")
+	}
+	if r.ignoreCR {
+		options = append(options, "--ignore-cr-at-eol")
+	}
+	output, more, _ := r.backend.Diff(limit, append(options, args...)...)
+	return output, more
+}
+
+// Get the diff of a file as shown in the diff pane, limited to the staged or
+// unstaged changes in those sections of the list, and to limit lines
+func getFileDiff(r repo, f fileEntry, s section, limit int) ([]string, int) {
+	var output []byte
+	var more int
+	switch {
+	case s == stagedSection, s == noSection && f.status == staged:
+		output, more = r.limitedDiff(limit, "--cached", "--", f.pathFromCwd)
+	case f.status == conflicted:
+		// Shows the combined diff against both sides of the merge
+		output, more = r.limitedDiff(limit, "--", f.pathFromCwd)
+	case s == noSection && f.status == partiallyStaged:
+		output, more = r.limitedDiff(limit, "--cached", "--", f.pathFromCwd)
+		if limit >= 0 {
+			limit = max(limit-strings.Count(string(output), "\n"), 0)
+		}
+		unstaged, unstagedMore := r.limitedDiff(limit, "--", f.pathFromCwd)
+		output, more = append(output, unstaged...), more+unstagedMore
+	default:
+		if isTracked(r, f.pathFromCwd) {
+			output, more = r.limitedDiff(limit, "--", f.pathFromCwd)
+		} else {
+			// Exits with 1 when there are differences, so only the output matters
+			output, more = r.limitedDiff(limit, "--no-index", "--", "/dev/null", f.pathFromCwd)
+		}
+	}
+
+	return presentDiff(r, f, output), more
+}
+
+// Lines of git diff output as the diff pane shows them
+func presentDiff(r repo, f fileEntry, output []byte) []string {
+	lines := describeSymlinks(describeTypeChanges(collapseModeLines(splitDiffLines(string(output)))))
+	if driver := diffDriver(r, f.pathFromCwd); driver != "" && len(lines) > 0 {
+		lines = append([]string{"diff driver: " + driver}, lines...)
+	}
+	return lines
+}
+
+// The diff driver set for a path by .gitattributes, if any
+func diffDriver(r repo, path string) string {
+	output, err := r.git("check-attr", "-z", "diff", "--", path).Output()
+	if err != nil {
+		return ""
+	}
+	// Output is "<path>\x00diff\x00<value>\x00"
+	fields := strings.Split(string(output), "\x00")
+	if len(fields) < 3 {
+		return ""
+	}
+	switch value := fields[2]; value {
+	case "unspecified", "set":
+		return ""
+	case "unset":
+		return "binary"
+	default:
+		return value
+	}
+}
+
+// Replace the "old mode"/"new mode" header pair with a single line
+func collapseModeLines(lines []string) []string {
+	var result []string
+	for i := 0; i < len(lines); i++ {
+		oldMode, ok := strings.CutPrefix(lines[i], "old mode ")
+		if ok && i+1 < len(lines) {
+			if newMode, ok := strings.CutPrefix(lines[i+1], "new mode "); ok {
+				result = append(result, "mode changed: "+oldMode+" → "+newMode)
+				i++
+				continue
+			}
+		}
+		result = append(result, lines[i])
+	}
+	return result
+}
+
+// Head the pair of sections git diff prints for a path whose type changed,
+// the old file deleted and the new one added, with a single line saying so
+func describeTypeChanges(lines []string) []string {
+	var result []string
+	header, deleted := "", ""
+	for _, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			if line != header {
+				deleted = ""
+			}
+			header = line
+		case strings.HasPrefix(line, "deleted file mode "):
+			deleted = strings.TrimPrefix(line, "deleted file mode ")
+		case strings.HasPrefix(line, "new file mode ") && deleted != "":
+			c := modeChange{from: deleted, to: strings.TrimPrefix(line, "new file mode ")}
+			if kinds := c.typeChange(); kinds != "" {
+				// Before the header of the deleting section
+				at := slices.Index(result, header)
+				result = slices.Insert(result, at, "type changed: "+kinds)
+			}
+			deleted = ""
+		}
+		result = append(result, line)
+	}
+	return result
+}
+
+// Show the link targets of symlinks in a diff instead of treating them as file content
+func describeSymlinks(lines []string) []string {
+	var result []string
+	for start := 0; start < len(lines); {
+		end := start + 1
+		for end < len(lines) && !strings.HasPrefix(lines[end], "diff --git ") {
+			end++
+		}
+		result = append(result, describeSymlink(lines[start:end])...)
+		start = end
+	}
+	return result
+}
+
+func describeSymlink(section []string) []string {
+	isLink := false
+	body := len(section)
+	for i, line := range section {
+		if strings.HasPrefix(line, "@@") {
+			body = i
+			break
+		}
+		if strings.HasSuffix(line, " 120000") {
+			// "new file mode 120000", "deleted file mode 120000" or "index <old>..<new> 120000"
+			isLink = true
+		}
+	}
+	if !isLink {
+		return section
+	}
+
+	var oldTarget, newTarget string
+	for _, line := range section[body:] {
+		switch {
+		case strings.HasPrefix(line, "-"):
+			oldTarget = line[1:]
+		case strings.HasPrefix(line, "+"):
+			newTarget = line[1:]
+		}
+	}
+	result := slices.Clone(section[:body])
+	switch {
+	case oldTarget == "":
+		return append(result, "symlink → "+newTarget)
+	case newTarget == "":
+		return append(result, "symlink removed, was → "+oldTarget)
+	default:
+		return append(result, "symlink: "+oldTarget+" → "+newTarget)
+	}
+}
+
+func isTracked(r repo, path string) bool {
+	return r.git("ls-files", "--error-unmatch", "--", path).Run() == nil
+}
+
+func splitDiffLines(diff string) []string {
+	diff = strings.TrimSuffix(diff, "\n")
+	if diff == "" {
+		return nil
+	}
+	return strings.Split(diff, "\n")
+}
+
+// A line of the diff pane
+type diffLine struct {
+	text string
+	// Number of +/-/space columns the line starts with: 1 in a regular hunk,
+	// one per parent in the hunks of a combined diff and 0 outside of hunks
+	columns int
+	moved   bool      // removed or added here, the other way elsewhere
+	state   hunkState // of a hunk header, see markHunkStates
+	// Shown instead of text for a line of a file's content, see filePreview
+	preview string
+	folded  []diffLine // unchanged lines this marker stands for, see foldContext
+}
+
+func parseDiff(lines []string) []diffLine {
+	var result []diffLine
+	columns := 0
+	for _, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "diff "):
+			columns = 0
+		case strings.HasPrefix(line, "@@"):
+			// "@@ -1 +1 @@" for regular diffs, "@@@ -1 -1 +1 @@@" for a combined diff of 2 parents
+			result = append(result, diffLine{text: line})
+			columns = len(line) - len(strings.TrimLeft(line, "@")) - 1
+			continue
+		}
+		result = append(result, diffLine{text: line, columns: columns})
+	}
+	return result
+}
+
+func renderDiffLine(l diffLine, width int) string {
+	if l.preview != "" {
+		return ansi.Truncate(strings.ReplaceAll(l.preview, "\t", strings.Repeat(" ", tabWidth)), width, "")
+	}
+	if l.folded != nil {
+		return separatorStyle.Render(ansi.Truncate(l.text, width, "…"))
+	}
+	if l.state != hunkUnmarked {
+		label := ansi.Truncate(" "+l.state.String(), width, "")
+		return renderDiffLine(diffLine{text: l.text}, width-ansi.StringWidth(label)) + badgeStyle.Render(label)
+	}
+	line := strings.ReplaceAll(l.text, "\t", strings.Repeat(" ", tabWidth))
+	line = ansi.Truncate(line, width, "")
+	if l.columns > 1 {
+		return renderCombinedDiffLine(line, l.columns)
+	}
+	switch {
+	case l.columns == 1 && l.moved && strings.HasPrefix(line, "+"):
+		return diffMovedAddedStyle.Render(line)
+	case l.columns == 1 && l.moved && strings.HasPrefix(line, "-"):
+		return diffMovedRemovedStyle.Render(line)
+	case l.columns == 1 && strings.HasPrefix(line, "+"):
+		return diffAddedStyle.Render(line)
+	case l.columns == 1 && strings.HasPrefix(line, "-"):
+		return diffRemovedStyle.Render(line)
+	case l.columns == 1:
+		return line
+	case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"),
+		strings.HasPrefix(line, "diff "), strings.HasPrefix(line, "index "):
+		return diffMetaStyle.Render(line)
+	case strings.HasPrefix(line, "mode changed: "), strings.HasPrefix(line, "type changed: "), strings.HasPrefix(line, "symlink"):
+		return diffModeStyle.Render(line)
+	case strings.HasPrefix(line, "@@"):
+		return diffHunkStyle.Render(line)
+	default:
+		return line
+	}
+}
+
+// Color a line of a combined diff: each column says whether the line was
+// added or removed relative to one parent, and conflict markers stand out
+func renderCombinedDiffLine(line string, columns int) string {
+	columns = min(columns, len(line))
+	prefix, content := line[:columns], line[columns:]
+
+	var b strings.Builder
+	for _, c := range prefix {
+		switch c {
+		case '+':
+			b.WriteString(diffAddedStyle.Render("+"))
+		case '-':
+			b.WriteString(diffRemovedStyle.Render("-"))
+		default:
+			b.WriteRune(c)
+		}
+	}
+
+	switch {
+	case isConflictMarker(content):
+		b.WriteString(conflictStyle.Render(content))
+	case strings.Contains(prefix, "-"):
+		b.WriteString(diffRemovedStyle.Render(content))
+	case strings.Contains(prefix, "+"):
+		b.WriteString(diffAddedStyle.Render(content))
+	default:
+		b.WriteString(content)
+	}
+	return b.String()
+}
+
+func isConflictMarker(content string) bool {
+	for _, marker := range []string{"<<<<<<<", "|||||||", "=======", ">>>>>>>"} {
+		if strings.HasPrefix(content, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// Height of the panes excluding the header, status line and footer
+func (m model) bodyHeight() int {
+	h := m.height - 2
+	if m.operation != nil {
+		// Banner line
+		h--
+	}
+	if cfg.ShowFooter {
+		h--
+	}
+	return max(h, 1)
+}
+
+// Scroll the diff pane by delta lines, 0 only bringing it up to date
+func (m *model) scrollDiff(delta int) {
+	m.syncDiffPane()
+	switch {
+	case delta > 0:
+		m.pane.ScrollDown(delta)
+	case delta < 0:
+		m.pane.ScrollUp(-delta)
+	default:
+		m.pane.SetYOffset(m.pane.YOffset)
+	}
+}
+
+// The diff pane: diffLines rendered into a viewport, see syncDiffPane
+type diffPane struct {
+	viewport.Model
+	shown       diffPaneKey // what the content was rendered from
+	rendered    []string    // diffLines rendered without highlights
+	renderedFor diffPaneKey
+}
+
+// What the content of the diff pane is rendered from, to render it again
+// only when one of them changes rather than on every scroll step
+type diffPaneKey struct {
+	id        int
+	lines     int
+	more      int
+	width     int
+	height    int
+	current   int // hunk header shown selected, -1 for none
+	selection lineSelection
+	search    string
+}
+
+// Width and height of the diff pane in the current layout, see View
+func (m model) diffPaneSize() (int, int) {
+	height := m.bodyHeight()
+	switch {
+	case m.dualShown():
+		// Below the title of its side
+		width := m.width - m.listPaneWidth() - 1
+		if m.fullScreenDiff || m.width < minSplitWidth {
+			width = m.width
+		}
+		staged, unstaged := dualWidths(width)
+		if m.dualSide == stagedSection {
+			return staged, height - 1
+		}
+		return unstaged, height - 1
+	case m.fullScreenDiff && m.mode != recoveryMode, m.width < minSplitWidth:
+		return m.width, height
+	case m.mode == historyMode, m.mode == recoveryMode:
+		return m.width - m.width/2 - 1, height
+	default:
+		return m.width - m.listPaneWidth() - 1, height
+	}
+}
+
+// Render diffLines into the diff pane if anything it shows changed, keeping
+// the scroll position as far as the new content allows
+func (m *model) syncDiffPane() {
+	if m.diffLoading {
+		// Keeps the position to restore once loaded, see loadFullDiff
+		return
+	}
+	width, height := m.diffPaneSize()
+	key := diffPaneKey{id: m.diffID, lines: len(m.diffLines), more: m.diffMore, width: width, height: height, current: -1, search: m.searchTerm}
+	// Mark what the hunk keys act on
+	if m.mode == diffMode && m.lineSelect == nil {
+		key.current = m.currentHunk()
+	}
+	if m.lineSelect != nil {
+		key.selection = *m.lineSelect
+	}
+	if key == m.pane.shown {
+		return
+	}
+	plain := key
+	plain.current, plain.selection = -1, lineSelection{}
+	if plain != m.pane.renderedFor {
+		m.pane.rendered = nil
+		for _, line := range m.diffLines {
+			m.pane.rendered = append(m.pane.rendered, m.renderPaneLine(line, width))
+		}
+		m.pane.renderedFor = plain
+	}
+	lines := slices.Clone(m.pane.rendered)
+	for i, line := range m.diffLines {
+		if i == key.current || m.selectedLine(i) {
+			lines[i] = selectedLineStyle.Render(m.renderPaneLine(line, width))
+		}
+	}
+	if m.diffMore > 0 && len(lines) > 0 {
+		more := tr("… %d more lines, %s in the focused diff to load them", m.diffMore, m.keys.loadFullDiff.Help().Key)
+		lines = append(lines, badgeStyle.Render(ansi.Truncate(more, width, "…")))
+	}
+	// Scrolling past the end always leaves the last line on screen
+	pastEnd := min(max(cfg.Diff.ScrollPastEnd, 0), height-1)
+	if len(lines) > height {
+		lines = append(lines, make([]string, pastEnd)...)
+	}
+	offset := m.pane.YOffset
+	m.pane.Width, m.pane.Height = width, height
+	m.pane.SetContent(strings.Join(lines, "\n"))
+	m.pane.SetYOffset(offset)
+	m.pane.shown = key
+}
+
+// A line of the diff pane, with what the diff is searched for highlighted
+func (m model) renderPaneLine(line diffLine, width int) string {
+	rendered := renderDiffLine(line, width)
+	if m.searchTerm != "" && len(searchRanges(line.text, m.searchTerm)) > 0 {
+		return highlightSearch(rendered, m.searchTerm)
+	}
+	return rendered
+}
+
+// Scroll to the next or previous hunk header, keeping the configured lines
+// of context above it
+func (m *model) jumpToHunk(direction int) {
+	current := m.currentHunk()
+	if current < 0 {
+		current = m.pane.YOffset + min(max(cfg.Diff.ScrollOff, 0), m.bodyHeight()/2)
+	}
+	for i := current + direction; i >= 0 && i < len(m.diffLines); i += direction {
+		if isHunkHeader(m.diffLines[i]) {
+			m.showHunk(i)
+			return
+		}
+	}
+}
+
+// Make the hunk with the header at h the current one, scrolled to the top
+// of the pane below scrolloff lines of context
+func (m *model) showHunk(h int) {
+	context := min(max(cfg.Diff.ScrollOff, 0), m.bodyHeight()/2)
+	m.hunk = h
+	m.scrollDiff(0)
+	m.pane.SetYOffset(h - context)
+}
+
+type diffLoadedMsg struct {
+	root      string
+	id        int
+	lines     []diffLine
+	more      int // lines left out after diffLineLimit
+	cancelled bool
+	missing   []string   // blobs a partial clone lacks, the diff isn't loaded then
+	other     []diffLine // the other side of the dual diff
+}
+
+// Load the diff of the selected row in the background, so a slow diff can be cancelled
+func (m *model) loadDiff() {
+	switch {
+	case m.mode == historyMode:
+		m.loadHistoryDiff()
+		return
+	case m.mode == recoveryMode:
+		if m.recovery.files != nil {
+			m.loadRecoveryDiff()
+		}
+		return
+	case m.mode == changeSetMode:
+		m.loadChangeSet()
+		return
+	}
+	m.pane.YOffset = 0
+	m.diffLines = nil
+	m.otherDiff = nil
+	m.diffMore = 0
+	m.lineSelect = nil
+	m.hunk = -1
+	m.match = -1
+	m.advance = nil
+	m.diffID++
+	m.diffLoading = false
+	m.diffMissing = false
+	row, ok := m.selectedRow()
+	if !ok || row.file < 0 {
+		return
+	}
+	m.diffLoading = true
+	r, f, id, review := m.repo, m.files[row.file], m.diffID, m.review
+	s, dual, preview, fold := m.diffSection(row), m.dualShown(), m.preview, m.folding
+	limit := m.diffLimit(fmt.Sprint(review != nil, s, f.pathFromGitRoot))
+	m.queue(func() tea.Msg {
+		_, generation := gitContext(r.root)
+		if preview && f.untracked && review == nil {
+			if lines, more, ok := filePreview(r, f, limit); ok {
+				return diffLoadedMsg{r.root, id, lines, more, false, nil, nil}
+			}
+		}
+		blobsOf := s
+		if dual {
+			// The blobs of both sides
+			blobsOf = stagedSection
+		}
+		if missing := missingBlobs(r, f, blobsOf, review); len(missing) > 0 {
+			return diffLoadedMsg{root: r.root, id: id, missing: missing}
+		}
+		var lines []string
+		var more int
+		if review != nil {
+			lines, more = getReviewDiff(r, f, *review, limit)
+		} else {
+			lines, more = getFileDiff(r, f, s, limit)
+		}
+		parsed := parseDiff(lines)
+		if review == nil {
+			markHunkStates(r, f, s, parsed)
+		}
+		if cfg.Diff.ColorMoved && f.status != conflicted {
+			markMoved(parsed, movedCandidates(r, f, s, review))
+		}
+		var other []diffLine
+		if dual {
+			lines, _ := getFileDiff(r, f, otherSide(s), limit)
+			other = parseDiff(lines)
+			markHunkStates(r, f, otherSide(s), other)
+		}
+		if fold {
+			parsed, other = foldContext(parsed, cfg.Diff.FoldContext), foldContext(other, cfg.Diff.FoldContext)
+		}
+		return diffLoadedMsg{r.root, id, parsed, more, gitCancelledSince(r.root, generation), nil, other}
+	})
+}
+
+func (m *model) diffLoaded(msg diffLoadedMsg) {
+	if msg.id != m.diffID {
+		return
+	}
+	m.diffLoading = false
+	if msg.cancelled {
+		m.status = tr("Cancelled loading the diff")
+		return
+	}
+	if len(msg.missing) > 0 {
+		m.diffMissing = true
+		if row, ok := m.selectedRow(); ok && row.file >= 0 {
+			m.askToFetchBlobs(m.files[row.file].pathFromGitRoot, msg.missing)
+		}
+		return
+	}
+	m.diffLines = msg.lines
+	m.otherDiff = msg.other
+	m.diffMore = msg.more
+	m.scrollDiff(0)
+	if a := m.advance; a != nil {
+		m.advance = nil
+		m.advanceToHunk(*a)
+	}
+}
+
+// How many lines to load of the diff identified by key: all of them once
+// loadFullDiff was asked to
+func (m *model) diffLimit(key string) int {
+	m.diffKey = key
+	if key == m.fullDiff {
+		return -1
+	}
+	return diffLineLimit
+}
+
+// Load the lines left out of the diff in the pane
+func (m *model) loadFullDiff() {
+	if m.diffMore == 0 {
+		return
+	}
+	m.fullDiff = m.diffKey
+	offset := m.pane.YOffset
+	m.loadDiff()
+	m.pane.YOffset = offset
+}
+
+func (m model) diffView(width, height int) string {
+	if m.dualShown() {
+		return m.dualDiffView(width, height)
+	}
+	return m.paneView(width, height)
+}
+
+// The diff pane, or why it's empty
+func (m model) paneView(width, height int) string {
+	if m.diffLoading {
+		return badgeStyle.Render(ansi.Truncate(tr("Loading diff, %s to cancel", m.keys.cancel.Help().Key), width, "…"))
+	}
+	if m.diffMissing {
+		return badgeStyle.Render(ansi.Truncate(tr("Not available locally in this partial clone"), width, "…"))
+	}
+	if len(m.diffLines) == 0 {
+		return ""
+	}
+	pane := m.pane.Model
+	pane.Width, pane.Height = width, height
+	return pane.View()
+}
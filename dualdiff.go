@@ -0,0 +1,102 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Whether the diff area shows the staged changes of the selected file next
+// to its unstaged ones. The diff pane is the side the hunk keys act on, the
+// other side is only shown.
+func (m model) dualShown() bool {
+	if !m.dualDiff || m.review != nil || (m.mode != listMode && m.mode != diffMode) {
+		return false
+	}
+	row, ok := m.selectedRow()
+	// A conflict has no staged side
+	return ok && row.file >= 0 && m.files[row.file].status != conflicted
+}
+
+// The section whose diff the diff pane shows for the selected row
+func (m model) diffSection(row listRow) section {
+	if m.dualShown() {
+		return m.dualSide
+	}
+	return row.section
+}
+
+// The side of the dual diff that isn't s
+func otherSide(s section) section {
+	if s == stagedSection {
+		return unstagedSection
+	}
+	return stagedSection
+}
+
+func (m *model) toggleDualDiff() {
+	m.dualDiff = !m.dualDiff
+	if !m.dualDiff {
+		m.status = tr("The diff shows the changes of the selected section")
+		m.loadDiff()
+		return
+	}
+	// Starting on the side of the selected row
+	m.dualSide = unstagedSection
+	if row, ok := m.selectedRow(); ok && row.file >= 0 {
+		if row.section == stagedSection || (row.section == noSection && m.files[row.file].status == staged) {
+			m.dualSide = stagedSection
+		}
+	}
+	m.status = tr("Staged and unstaged changes side by side, %s in the diff to switch sides", m.keys.switchSide.Help().Key)
+	m.loadDiff()
+}
+
+// Make the other side of the dual diff the one the hunk keys act on
+func (m *model) switchDualSide() {
+	if !m.dualShown() {
+		return
+	}
+	m.dualSide = otherSide(m.dualSide)
+	m.loadDiff()
+}
+
+// Width of the staged side, on the left, and of the unstaged side of a dual
+// diff in width columns
+func dualWidths(width int) (int, int) {
+	staged := (width - 1) / 2
+	return staged, width - 1 - staged
+}
+
+// The staged changes on the left and the unstaged ones on the right, each
+// under its title, the one of the diff pane highlighted
+func (m model) dualDiffView(width, height int) string {
+	stagedWidth, unstagedWidth := dualWidths(width)
+	side := func(s section, width int) string {
+		title := ansi.Truncate(s.title(), width, "…")
+		if s != m.dualSide {
+			return separatorStyle.Render(title) + "\n" + m.otherDiffView(width, height-1)
+		}
+		return sectionStyle.Render(title) + "\n" + m.paneView(width, height-1)
+	}
+	staged := lipgloss.NewStyle().Width(stagedWidth).Render(side(stagedSection, stagedWidth))
+	unstaged := lipgloss.NewStyle().Width(unstagedWidth).Render(side(unstagedSection, unstagedWidth))
+	separator := separatorStyle.Render(strings.TrimSuffix(strings.Repeat("│\n", height), "\n"))
+	return lipgloss.JoinHorizontal(lipgloss.Top, staged, separator, unstaged)
+}
+
+// The top of the other side of the dual diff
+func (m model) otherDiffView(width, height int) string {
+	if m.diffLoading {
+		return ""
+	}
+	if len(m.otherDiff) == 0 {
+		return badgeStyle.Render(ansi.Truncate(tr("No changes"), width, "…"))
+	}
+	var lines []string
+	for _, line := range m.otherDiff[:min(len(m.otherDiff), height)] {
+		lines = append(lines, renderDiffLine(line, width))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// A Markdown summary of the staged changes for a pull request description or
+// a changelog: the files with their line counts and review notes
+func (m model) stagedSummary() string {
+	var lines []string
+	var total diffStat
+	for _, f := range m.files {
+		if f.status != staged && f.status != partiallyStaged {
+			continue
+		}
+		total = total.combine(f.stagedDiff)
+		line := fmt.Sprintf("- `%s` +%d -%d", f.pathFromGitRoot, f.stagedDiff.added, f.stagedDiff.deleted)
+		if from, to, renamed := strings.Cut(f.pathFromGitRoot, " -> "); renamed {
+			line = fmt.Sprintf("- `%s` %s +%d -%d", to, tr("(renamed from `%s`)", from), f.stagedDiff.added, f.stagedDiff.deleted)
+		}
+		switch {
+		case strings.HasPrefix(f.xy, "A"):
+			line += " " + tr("(new)")
+		case strings.HasPrefix(f.xy, "D"):
+			line += " " + tr("(deleted)")
+		}
+		if note := m.notes[f.pathFromGitRoot]; note != "" {
+			line += ": " + note
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	title := "## " + tr("Staged changes")
+	if m.branch != "" && m.branch[0] != '(' {
+		title = "## " + tr("Staged changes on %s", "`"+m.branch+"`")
+	}
+	header := []string{title, "", tr("%d file(s) changed, +%d -%d", len(lines), total.added, total.deleted), ""}
+	return strings.Join(append(header, lines...), "\n") + "\n"
+}
+
+// Start typing where to export the summary of the staged changes to
+func (m *model) openExportPrompt() {
+	if m.stagedSummary() == "" {
+		m.status = tr("Nothing staged to summarize")
+		return
+	}
+	input := textinput.New()
+	input.Prompt = tr("Export the summary to a file, or to the clipboard if empty: ")
+	input.PromptStyle = promptStyle
+	input.Cursor.SetMode(cursor.CursorStatic)
+	input.Focus()
+	m.exportInput = &input
+}
+
+func (m *model) updateExportPrompt(msg tea.KeyMsg) {
+	switch key := keyName(msg); {
+	case key == "enter":
+		path := strings.TrimSpace(m.exportInput.Value())
+		m.exportInput = nil
+		m.exportSummary(path)
+	case m.keys.cancel.matches(key):
+		m.exportInput = nil
+	default:
+		*m.exportInput, _ = m.exportInput.Update(msg)
+	}
+}
+
+func (m model) exportPromptView() string {
+	m.exportInput.Width = max(m.width-ansi.StringWidth(m.exportInput.Prompt)-1, 0)
+	return m.exportInput.View()
+}
+
+// Write the summary to path, relative to the current directory, or to
+// the clipboard of the terminal with OSC 52 for no path
+func (m *model) exportSummary(path string) {
+	summary := m.stagedSummary()
+	if path == "" {
+		tty, err := os.OpenFile("/dev/tty", os.O_WRONLY, 0)
+		if err == nil {
+			_, err = fmt.Fprint(tty, ansi.SetSystemClipboard(summary))
+			tty.Close()
+		}
+		if err != nil {
+			m.status = tr("Failed to copy the summary: %v", err)
+		} else {
+			m.status = tr("Copied the summary to the clipboard")
+		}
+		return
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(m.repo.cwd, path)
+	}
+	write := func(m *model) {
+		if err := os.WriteFile(path, []byte(summary), 0o644); err != nil {
+			m.status = tr("Failed to export the summary: %v", err)
+		} else {
+			m.status = tr("Exported the summary to %s", path)
+		}
+	}
+	if _, err := os.Stat(path); err == nil {
+		m.ask(tr("Overwrite %s?", path), write)
+		return
+	}
+	write(m)
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Size, modification time and mode of the selected file, looked up when
+// asked for and shown over the panes until the next key, the first line
+// being the title
+func getFileInfo(r repo, f fileEntry) []string {
+	lines := []string{f.pathFromCwd, ""}
+	info, err := os.Lstat(filepath.Join(r.root, f.pathFromGitRoot))
+	if err != nil {
+		lines = append(lines, tr("Not in the work tree"))
+	} else {
+		modified := info.ModTime()
+		lines = append(lines,
+			tr("Size: %s", humanSize(info.Size())),
+			tr("Modified: %s, %s", modified.Format("2006-01-02 15:04:05"),
+				tr("%s ago", time.Since(modified).Round(time.Second))),
+			tr("Permissions: %s", info.Mode()))
+	}
+	// Output of `git ls-files -s` looks like "<mode> <object> <stage>\t<path>"
+	output, _ := r.git("-C", r.root, "ls-files", "-s", "--", f.pathFromGitRoot).Output()
+	if fields := strings.Fields(string(output)); len(fields) >= 2 {
+		lines = append(lines, tr("Mode in the index: %s", fields[0]))
+	}
+	return lines
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/hzqtc/git-istage/gitx"
+)
+
+// A tracked file with the skip-worktree or assume-unchanged bit set. Git
+// leaves such files out of `git status`, so their changes can't be staged.
+type flaggedFile struct {
+	pathFromGitRoot string
+	pathFromCwd     string
+	assumeUnchanged bool
+	skipWorktree    bool
+	modified        bool // the work tree content differs from the index
+}
+
+func getFlaggedFiles(r repo, sparse sparseCheckout) []flaggedFile {
+	// Output of `git ls-files -v -s` looks like "<tag> <mode> <object> <stage>\t<path>",
+	// the tag is lowercase for assume-unchanged and 'S' for skip-worktree
+	output, err := r.git("-C", r.root, "ls-files", "-v", "-s").Output()
+	if err != nil {
+		return nil
+	}
+
+	var files []flaggedFile
+	var objects []string
+	for line := range strings.SplitSeq(string(output), "\n") {
+		info, path, ok := strings.Cut(line, "\t")
+		path = gitx.UnquotePath(path)
+		fields := strings.Fields(info)
+		if !ok || len(fields) < 3 {
+			continue
+		}
+		tag := rune(fields[0][0])
+		f := flaggedFile{
+			pathFromGitRoot: path,
+			pathFromCwd:     r.relPath(path),
+			assumeUnchanged: unicode.IsLower(tag),
+			skipWorktree:    unicode.ToUpper(tag) == 'S',
+		}
+		if !f.assumeUnchanged && !f.skipWorktree {
+			continue
+		}
+		// Files outside the sparse-checkout cone are skip-worktree by design
+		if f.skipWorktree && !f.assumeUnchanged && sparse.enabled && !r.exists(path) {
+			continue
+		}
+		files = append(files, f)
+		objects = append(objects, fields[2])
+	}
+
+	// Hash the work tree copies to find out which files are actually changed
+	var paths []string
+	var indexes []int
+	for i, f := range files {
+		info, err := os.Lstat(filepath.Join(r.root, f.pathFromGitRoot))
+		if err == nil && info.Mode().IsRegular() {
+			paths = append(paths, f.pathFromGitRoot)
+			indexes = append(indexes, i)
+		}
+	}
+	if len(paths) > 0 {
+		cmd := r.git("-C", r.root, "hash-object", "--stdin-paths")
+		cmd.Stdin = strings.NewReader(strings.Join(paths, "\n") + "\n")
+		output, _ := cmd.Output()
+		hashes := strings.Fields(string(output))
+		for j, i := range indexes {
+			files[i].modified = j < len(hashes) && hashes[j] != objects[i]
+		}
+	}
+	return files
+}
+
+func (r repo) exists(pathFromGitRoot string) bool {
+	_, err := os.Lstat(filepath.Join(r.root, pathFromGitRoot))
+	return err == nil
+}
+
+func setIndexFlag(r repo, path, flag string, on bool) error {
+	option := "--" + flag
+	if !on {
+		option = "--no-" + flag
+	}
+	if err := r.run("update-index", option, "--", path); err != nil {
+		return fmt.Errorf("git update-index %s failed: %w", option, err)
+	}
+	return nil
+}
+
+func (m model) flagsView(width, height int) string {
+	if len(m.flagged) == 0 {
+		return tr("No files have skip-worktree or assume-unchanged set")
+	}
+
+	rows := []string{tr("Files hidden from git status:")}
+	for i, f := range m.flagged {
+		var flags []string
+		if f.skipWorktree {
+			flags = append(flags, "skip-worktree")
+		}
+		if f.assumeUnchanged {
+			flags = append(flags, "assume-unchanged")
+		}
+		row := cfg.Glyphs.cursor(i == m.flagCursor) + f.pathFromGitRoot + " " + badgeStyle.Render(strings.Join(flags, ", "))
+		if f.modified {
+			row += " " + partiallyStagedStyle.Render(tr("modified"))
+		}
+		rows = append(rows, ansi.Truncate(row, width, ""))
+	}
+
+	offset := max(m.flagCursor+1-height+1, 0)
+	return strings.Join(rows[offset:min(offset+height, len(rows))], "\n")
+}
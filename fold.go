@@ -0,0 +1,91 @@
+package main
+
+import "strings"
+
+// Whether a line of a hunk is unchanged context, blank in all its columns
+func isContext(l diffLine) bool {
+	return l.columns > 0 && len(l.text) >= l.columns && strings.TrimSpace(l.text[:l.columns]) == ""
+}
+
+// Replace the unchanged lines of each hunk further than context lines from
+// a change with a marker holding them, the lines kept at the edges of a hunk
+// being those next to its changes. Runs of fewer than two lines to hide are
+// left as they are, the marker taking a line too.
+func foldContext(lines []diffLine, context int) []diffLine {
+	context = max(context, 0)
+	var result []diffLine
+	for i := 0; i < len(lines); {
+		if !isContext(lines[i]) {
+			result = append(result, lines[i])
+			i++
+			continue
+		}
+		end := i
+		for end < len(lines) && isContext(lines[end]) {
+			end++
+		}
+		// Context after the hunk header or before the end of the hunk is only
+		// kept on the side of the change
+		keepBefore, keepAfter := context, context
+		if i == 0 || lines[i-1].columns == 0 {
+			keepBefore = 0
+		}
+		if end == len(lines) || lines[end].columns == 0 {
+			keepAfter = 0
+		}
+		if end-i-keepBefore-keepAfter < 2 {
+			result = append(result, lines[i:end]...)
+		} else {
+			hidden := lines[i+keepBefore : end-keepAfter]
+			result = append(result, lines[i:i+keepBefore]...)
+			result = append(result, diffLine{
+				text:    tr("… %d unchanged lines", len(hidden)),
+				columns: lines[i].columns,
+				folded:  hidden,
+			})
+			result = append(result, lines[end-keepAfter:end]...)
+		}
+		i = end
+	}
+	return result
+}
+
+// Put back the lines the markers between from and to hold, returning where
+// to is now
+func (m *model) unfold(from, to int) int {
+	var lines []diffLine
+	for i, l := range m.diffLines {
+		if i >= from && i < to && l.folded != nil {
+			lines = append(lines, l.folded...)
+			to += len(l.folded) - 1
+		} else {
+			lines = append(lines, l)
+		}
+	}
+	m.diffLines = lines
+	return to
+}
+
+// Show the lines of the first fold on screen
+func (m *model) unfoldOnScreen() {
+	top, bottom := m.pane.YOffset, m.pane.YOffset+m.bodyHeight()
+	for i := top; i < min(bottom, len(m.diffLines)); i++ {
+		if m.diffLines[i].folded != nil {
+			m.unfold(i, i+1)
+			m.scrollDiff(0)
+			return
+		}
+	}
+	m.status = tr("No folded lines on screen")
+}
+
+func (m *model) toggleFolding() {
+	m.folding = !m.folding
+	if m.folding {
+		m.status = tr("Unchanged lines further than %d from a change are folded, %s in the diff unfolds them",
+			max(cfg.Diff.FoldContext, 0), m.keys.unfold.Help().Key)
+	} else {
+		m.status = tr("Diffs show every unchanged line")
+	}
+	m.loadDiff()
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/hzqtc/git-istage/gitx"
+)
+
+type formatFinishedMsg struct {
+	root string
+	path string // from the root
+	err  error
+}
+
+// The formatter configured for a path, by the first glob of [formatters]
+// in sorted order that matches its name or its path from the root
+func formatterFor(path string) (string, bool) {
+	for _, pattern := range slices.Sorted(maps.Keys(cfg.Formatters)) {
+		byName, _ := filepath.Match(pattern, filepath.Base(path))
+		byPath, _ := filepath.Match(pattern, path)
+		if byName || byPath {
+			return cfg.Formatters[pattern], true
+		}
+	}
+	return "", false
+}
+
+func checkFormatters(formatters map[string]string) error {
+	for pattern := range formatters {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return errors.New(tr("Invalid pattern %q in [formatters]: %v", pattern, err))
+		}
+	}
+	return nil
+}
+
+// Run the formatter of the selected file on it, with sh in the repository
+// root and the path in $FILE, like a launcher but without leaving the UI
+func (m *model) format(index int) tea.Cmd {
+	path := m.files[index].currentPath()
+	command, ok := formatterFor(path)
+	switch {
+	case !ok:
+		m.status = tr("No formatter for %s, add one to [formatters] in the config", path)
+		return nil
+	case !fileExists(filepath.Join(m.repo.root, path)):
+		m.status = tr("%s was deleted, there's nothing to format", path)
+		return nil
+	}
+	m.status = tr("Formatting %s…", path)
+	root := m.repo.root
+	return func() tea.Msg {
+		cmd := exec.Command("sh", "-c", command)
+		cmd.Dir = root
+		cmd.Env = append(os.Environ(), "FILE="+path, "REPO="+root)
+		cmd.WaitDelay = gitx.KillWaitDelay
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		err := cmd.Run()
+		if err != nil {
+			if msg := firstLine(stderr.String()); msg != "" {
+				err = errors.New(msg)
+			}
+		}
+		return formatFinishedMsg{root, path, err}
+	}
+}
+
+// The diff shows what the formatter changed, still to be staged
+func (m *model) formatFinished(msg formatFinishedMsg) {
+	if msg.err != nil {
+		m.status = tr("Formatting %s failed: %v", msg.path, msg.err)
+		return
+	}
+	m.reload()
+	m.loadDiff()
+	m.status = tr("Formatted %s", msg.path)
+}
+
+func fileExists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
@@ -0,0 +1,474 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hzqtc/git-istage/gitx"
+)
+
+type stagingStatus int
+
+const (
+	unstaged stagingStatus = iota
+	staged
+	partiallyStaged
+	conflicted
+	committed // changed by the branch's commits only, when reviewing the branch
+)
+
+type fileEntry struct {
+	pathFromGitRoot string
+	pathFromCwd     string // of the new path for a rename
+	origPathFromCwd string // of the old path for a rename
+	status          stagingStatus
+	diff            diffStat
+	stagedDiff      diffStat
+	unstagedDiff    diffStat
+	outsideSparse   bool // outside the sparse-checkout cone
+	modeChange      modeChange
+	symlink         bool
+	untracked       bool
+	lineEndings     bool   // most of the changes are of line endings, like from CRLF to LF
+	xy              string // status code from `git status --porcelain`
+}
+
+// File mode change between index and work tree, or HEAD and index when
+// the work tree doesn't change the mode
+type modeChange struct {
+	from string
+	to   string
+	// Whether the change is only in the work tree, in which case it can be
+	// staged separately from the content
+	unstaged bool
+}
+
+func (c modeChange) String() string {
+	if c.from == "" {
+		return ""
+	}
+	if c.to == "100755" {
+		return "+x"
+	}
+	if c.from == "100755" {
+		return "-x"
+	}
+	return c.from + "→" + c.to
+}
+
+// Like "file → symlink" when the change is of the file's type, whose diff
+// deletes the old file and adds the new one rather than changing content
+func (c modeChange) typeChange() string {
+	from, to := fileKind(c.from), fileKind(c.to)
+	if c.from == "" || from == to {
+		return ""
+	}
+	return from + " → " + to
+}
+
+// The kind of a tree entry by its mode
+func fileKind(mode string) string {
+	switch mode {
+	case "120000":
+		return "symlink"
+	case "160000":
+		return "submodule"
+	default:
+		return "file"
+	}
+}
+
+type diffStat struct {
+	added   int
+	deleted int
+}
+
+// The entry as it will look once staged or unstaged, before git confirms it
+func (f fileEntry) toggled(stage bool) fileEntry {
+	if stage {
+		f.status = staged
+		f.untracked = false
+		f.stagedDiff, f.unstagedDiff = f.diff, diffStat{}
+		return f
+	}
+	f.status = unstaged
+	// Unstaging a new file makes it untracked again
+	f.untracked = f.xy[0] == 'A'
+	f.stagedDiff, f.unstagedDiff = diffStat{}, f.diff
+	return f
+}
+
+func (d diffStat) combine(o diffStat) diffStat {
+	d.added += o.added
+	d.deleted += o.deleted
+	return d
+}
+
+// Whether most of the lines changed only change their line ending, given the
+// changes with --ignore-cr-at-eol
+func (d diffStat) mostlyLineEndings(ignoringCR diffStat) bool {
+	changed := d.added + d.deleted
+	return changed > 0 && 2*(ignoringCR.added+ignoringCR.deleted) < changed
+}
+
+// A git work tree and the directory git-istage was started from within it
+type repo struct {
+	root    string
+	cwd     string
+	gitDir  string
+	backend gitx.Backend // run from cwd
+	// Hunks of the diffs shown and staged span their whole function
+	functionContext bool
+	// The diffs shown leave out changes of CR at the end of lines
+	ignoreCR bool
+	// The remote of a partial clone, see promisorRemote
+	promisor string
+}
+
+func openRepo(dir string) (repo, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return repo{}, err
+	}
+	r := repo{cwd: absDir, backend: gitx.CLI{Dir: absDir, FindRenames: cfg.Diff.FindRenames}}
+
+	// Check if we are in a git repository
+	checkOutput, err := r.git("rev-parse", "--is-inside-work-tree").Output()
+	var exitErr *exec.ExitError
+	switch {
+	case err != nil && !errors.As(err, &exitErr):
+		// Such as the directory not existing
+		return repo{}, err
+	case err != nil && !strings.Contains(string(exitErr.Stderr), "not a git repository"):
+		// Git's reason, such as the repository belonging to someone else
+		if msg, _, _ := strings.Cut(strings.TrimSpace(string(exitErr.Stderr)), "\n"); msg != "" {
+			return repo{}, errors.New(msg)
+		}
+	}
+	if err != nil || strings.TrimSpace(string(checkOutput)) != "true" {
+		return repo{}, errors.New(tr("Not inside a git repository: %s", absDir))
+	}
+	rootOutput, err := r.git("rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return repo{}, err
+	}
+	r.root = strings.TrimSpace(string(rootOutput))
+	trackGitRuns(r.root, absDir)
+	gitDirOutput, err := r.git("rev-parse", "--absolute-git-dir").Output()
+	if err != nil {
+		return repo{}, err
+	}
+	r.gitDir = strings.TrimSpace(string(gitDirOutput))
+	if r.promisor = promisorRemote(r); r.promisor != "" {
+		r.backend = gitx.CLI{Dir: absDir, FindRenames: cfg.Diff.FindRenames, NoLazyFetch: true}
+	}
+	return r, nil
+}
+
+// Make sure git is there and recent enough before opening repositories, which
+// would otherwise fail as if they weren't repositories
+func checkGit() error {
+	v, err := gitx.Installed()
+	switch {
+	case errors.Is(err, gitx.ErrNotInstalled):
+		return errors.New(tr("git isn't installed, or isn't in PATH. Install it from https://git-scm.com/downloads and try again."))
+	case err != nil:
+		return err
+	case v.Before(gitx.MinVersion):
+		return errors.New(tr("git %v is too old, git-istage needs %v or later. Upgrade it from https://git-scm.com/downloads.", v, gitx.MinVersion))
+	}
+	return nil
+}
+
+// A repository that only exists in memory, see gitx.Demo. Git commands its
+// backend doesn't cover fail, running in a directory that doesn't exist.
+func openDemoRepo() repo {
+	dir := filepath.Join(os.TempDir(), "git-istage-demo", "shop")
+	return repo{root: dir, cwd: dir, gitDir: filepath.Join(dir, ".git"), backend: gitx.NewDemo()}
+}
+
+func (r repo) name() string {
+	return filepath.Base(r.root)
+}
+
+func (r repo) gitConfigBool(key string) bool {
+	output, _ := r.git("config", "--bool", key).Output()
+	return strings.TrimSpace(string(output)) == "true"
+}
+
+// Build a git command that runs from the repository's working directory and
+// gets killed by cancelGitCommands of its root
+func (r repo) git(args ...string) *gitx.Cmd {
+	return gitx.Command(r.cwd, args...)
+}
+
+// A git command given the terminal, which has no timeout
+func (r repo) interactiveGit(args ...string) *gitx.Cmd {
+	return gitx.Interactive(r.cwd, args...)
+}
+
+// The same repository with commands running from its root, for paths that
+// have to be given from there
+func (r repo) atRoot() repo {
+	r.cwd = r.root
+	return r
+}
+
+// Staging status by the XY code of `git status --porcelain`, every code
+// git-status(1) documents. X is the index and Y the work tree.
+var porcelainStatus = map[string]stagingStatus{
+	// Changed in the index only
+	"M ": staged, "T ": staged, "A ": staged, "D ": staged, "R ": staged, "C ": staged,
+	// Changed in the work tree only, " A", " R" and " C" being intent-to-add files
+	" M": unstaged, " T": unstaged, " D": unstaged, " A": unstaged, " R": unstaged, " C": unstaged,
+	// Changed in the index and changed again in the work tree
+	"MM": partiallyStaged, "MT": partiallyStaged, "MD": partiallyStaged,
+	"TM": partiallyStaged, "TT": partiallyStaged, "TD": partiallyStaged,
+	"AM": partiallyStaged, "AT": partiallyStaged, "AD": partiallyStaged,
+	"RM": partiallyStaged, "RT": partiallyStaged, "RD": partiallyStaged,
+	"CM": partiallyStaged, "CT": partiallyStaged, "CD": partiallyStaged,
+	// Unmerged
+	"DD": conflicted, "AU": conflicted, "UD": conflicted, "UA": conflicted,
+	"DU": conflicted, "AA": conflicted, "UU": conflicted,
+	// Untracked
+	"??": unstaged,
+}
+
+// The staging status of a file by its porcelain XY code. Ignored files ("!!")
+// and codes git doesn't document aren't changes to list.
+func interpretGitStatus(xy string) (stagingStatus, bool) {
+	status, ok := porcelainStatus[xy]
+	return status, ok
+}
+
+// How often the files listed so far are shown while git status is running
+const statusProgressInterval = 100 * time.Millisecond
+
+func getGitChanges(r repo) []fileEntry {
+	return streamGitChanges(r, nil)
+}
+
+// Like getGitChanges, also passing the files git status listed so far to
+// progress while it runs. Those miss what the other commands add, like diff
+// stats and whether they're outside the sparse-checkout.
+func streamGitChanges(r repo, progress func([]fileEntry)) []fileEntry {
+	statusCh := make(chan map[string]string)
+	diffStatsCh := make(chan diffStats)
+	ignoringCRCh := make(chan diffStats)
+	sparseCh := make(chan sparseCheckout)
+	modeChangesCh := make(chan map[string]modeChange)
+	go func() {
+		var listed func(map[string]string)
+		if progress != nil {
+			listed = func(status map[string]string) {
+				progress(statusEntries(r, status))
+			}
+		}
+		statusCh <- streamFileStatus(r, listed)
+	}()
+	go func() {
+		diffStatsCh <- getFileDiffStats(r)
+	}()
+	go func() {
+		ignoringCRCh <- getFileDiffStats(r, "--ignore-cr-at-eol")
+	}()
+	go func() {
+		sparseCh <- getSparseCheckout(r)
+	}()
+	go func() {
+		modeChangesCh <- getModeChanges(r)
+	}()
+	status := <-statusCh
+	diffStats := <-diffStatsCh
+	ignoringCR := <-ignoringCRCh
+	sparse := <-sparseCh
+	modeChanges := <-modeChangesCh
+
+	files := statusEntries(r, status)
+	for i := range files {
+		f := &files[i]
+		path := f.pathFromGitRoot
+		f.diff = diffStats.staged[path].combine(diffStats.unstaged[path])
+		f.stagedDiff = diffStats.staged[path]
+		f.unstagedDiff = diffStats.unstaged[path]
+		f.lineEndings = f.diff.mostlyLineEndings(ignoringCR.staged[path].combine(ignoringCR.unstaged[path]))
+		f.outsideSparse = sparse.excludes(path)
+		f.modeChange = modeChanges[path]
+		f.symlink = r.isSymlink(path)
+	}
+	return slices.DeleteFunc(files, func(f fileEntry) bool {
+		return f.outsideSparse && cfg.Sparse.HideOutside
+	})
+}
+
+// Files by their XY codes, sorted by path
+func statusEntries(r repo, status map[string]string) []fileEntry {
+	var files []fileEntry
+	for path, xy := range status {
+		fileStatus, ok := interpretGitStatus(xy)
+		if !ok {
+			continue
+		}
+		f := fileEntry{
+			pathFromGitRoot: path,
+			pathFromCwd:     r.relPath(path),
+			status:          fileStatus,
+			untracked:       xy == "??",
+			xy:              xy,
+		}
+		// git status lists a rename as "old -> new", git commands need each path
+		if from, to, renamed := strings.Cut(path, " -> "); renamed {
+			f.pathFromCwd, f.origPathFromCwd = r.relPath(to), r.relPath(from)
+		}
+		files = append(files, f)
+	}
+	slices.SortFunc(files, func(a, b fileEntry) int {
+		return strings.Compare(a.pathFromGitRoot, b.pathFromGitRoot)
+	})
+	return files
+}
+
+// Get the XY status code of each changed file
+func getFileStatus(r repo) map[string]string {
+	return streamFileStatus(r, nil)
+}
+
+// Read the XY codes as git status prints them, passing the ones read so far
+// to progress every statusProgressInterval. It must not keep the map.
+func streamFileStatus(r repo, progress func(map[string]string)) map[string]string {
+	result := make(map[string]string)
+	last := time.Now()
+	err := r.backend.Status(func(pathFromGitRoot, xy string) {
+		result[pathFromGitRoot] = xy
+		if progress != nil && time.Since(last) >= statusProgressInterval {
+			progress(result)
+			last = time.Now()
+		}
+	})
+	if err != nil {
+		return make(map[string]string)
+	}
+	return result
+}
+
+// Diff stats of the changes in the work tree and in the index
+type diffStats struct {
+	unstaged map[string]diffStat
+	staged   map[string]diffStat
+}
+
+func getFileDiffStats(r repo, args ...string) diffStats {
+	unstagedCh := make(chan map[string]diffStat)
+	stagedCh := make(chan map[string]diffStat)
+	go func() {
+		unstagedCh <- getNumstat(r, args...)
+	}()
+	go func() {
+		stagedCh <- getNumstat(r, append([]string{"--cached"}, args...)...)
+	}()
+	return diffStats{unstaged: <-unstagedCh, staged: <-stagedCh}
+}
+
+func getNumstat(r repo, args ...string) map[string]diffStat {
+	output, _, err := r.backend.Diff(-1, append([]string{"--numstat"}, args...)...)
+	if err != nil {
+		return nil
+	}
+	return parseDiffOutput(string(output))
+}
+
+func getModeChanges(r repo) map[string]modeChange {
+	result := make(map[string]modeChange)
+	cached, _, _ := r.backend.Diff(-1, "--summary", "--cached")
+	for path, c := range parseModeChanges(string(cached)) {
+		result[path] = c
+	}
+	worktree, _, _ := r.backend.Diff(-1, "--summary")
+	for path, c := range parseModeChanges(string(worktree)) {
+		c.unstaged = true
+		result[path] = c
+	}
+	return result
+}
+
+// Parse lines like " mode change 100644 => 100755 path" from `git diff --summary`
+func parseModeChanges(output string) map[string]modeChange {
+	result := make(map[string]modeChange)
+	for line := range strings.SplitSeq(output, "\n") {
+		rest, ok := strings.CutPrefix(line, " mode change ")
+		if !ok {
+			continue
+		}
+		parts := strings.SplitN(rest, " ", 4)
+		if len(parts) < 4 || parts[1] != "=>" {
+			continue
+		}
+		result[gitx.UnquotePath(parts[3])] = modeChange{from: parts[0], to: parts[2]}
+	}
+	return result
+}
+
+func parseDiffOutput(output string) map[string]diffStat {
+	result := make(map[string]diffStat)
+	for line := range strings.SplitSeq(output, "\n") {
+		// "<added>\t<deleted>\t<path>", the path quoted if need be
+		parts := strings.SplitN(line, "\t", 3)
+		if len(parts) < 3 {
+			continue
+		}
+		added, _ := strconv.Atoi(parts[0])
+		deleted, _ := strconv.Atoi(parts[1])
+		pathFromGitRoot := gitx.UnquotePath(parts[2])
+		result[pathFromGitRoot] = diffStat{added, deleted}
+	}
+	return result
+}
+
+// Discard work tree changes of a file. Symlinks are restored or removed as
+// links, their targets are never touched.
+func discardFile(r repo, f fileEntry) error {
+	if isTracked(r, f.pathFromCwd) {
+		return r.run("restore", "--", f.pathFromCwd)
+	}
+	path := filepath.Join(r.root, f.pathFromGitRoot)
+	if f.symlink {
+		return os.Remove(path)
+	}
+	return os.RemoveAll(path)
+}
+
+// Change the mode of a file in the index, keeping the content already staged
+func setIndexMode(r repo, pathFromGitRoot, mode string) error {
+	// Output of `git ls-files -s` looks like "<mode> <object> <stage>\t<path>"
+	output, err := r.git("-C", r.root, "ls-files", "-s", "--", pathFromGitRoot).Output()
+	if err != nil {
+		return err
+	}
+	fields := strings.Fields(string(output))
+	if len(fields) < 2 {
+		return fmt.Errorf("%s is not in the index", pathFromGitRoot)
+	}
+	cacheInfo := mode + "," + fields[1] + "," + pathFromGitRoot
+	return r.run("-C", r.root, "update-index", "--cacheinfo", cacheInfo)
+}
+
+func (r repo) isSymlink(pathFromGitRoot string) bool {
+	info, err := os.Lstat(filepath.Join(r.root, pathFromGitRoot))
+	return err == nil && info.Mode()&os.ModeSymlink != 0
+}
+
+// Convert a path from git root to a relative path of cwd
+func (r repo) relPath(pathFromGitRoot string) string {
+	// many git commands output file path relative to git root
+	// So we need to convert it to relative path to CWD
+	absPath := filepath.Join(r.root, pathFromGitRoot)
+	pathFromCwd, _ := filepath.Rel(r.cwd, absPath)
+	return pathFromCwd
+}
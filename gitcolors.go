@@ -0,0 +1,118 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Colors git names, numbered like the ANSI colors they stand for
+var gitColorNames = []string{"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white"}
+
+// Turn a color of git's config, like "red bold" or "#ff8700 black ul", into
+// a style: the first color is the foreground, the second the background. Not
+// ok for what git wouldn't take either.
+func parseGitColor(value string) (lipgloss.Style, bool) {
+	style := lipgloss.NewStyle()
+	colors := 0
+	for _, word := range strings.Fields(strings.ToLower(value)) {
+		if color, ok := gitColor(word); ok {
+			switch colors {
+			case 0:
+				style = style.Foreground(color)
+			case 1:
+				style = style.Background(color)
+			default:
+				return style, false
+			}
+			colors++
+			continue
+		}
+		on := true
+		if attr, ok := strings.CutPrefix(word, "no"); ok {
+			word, on = strings.TrimPrefix(attr, "-"), false
+		}
+		switch word {
+		case "bold":
+			style = style.Bold(on)
+		case "dim":
+			style = style.Faint(on)
+		case "italic":
+			style = style.Italic(on)
+		case "ul", "underline":
+			style = style.Underline(on)
+		case "blink":
+			style = style.Blink(on)
+		case "reverse":
+			style = style.Reverse(on)
+		case "strike":
+			style = style.Strikethrough(on)
+		default:
+			return style, false
+		}
+	}
+	return style, true
+}
+
+// A color word of git's config. "normal" and "default" leave the terminal's.
+func gitColor(word string) (lipgloss.TerminalColor, bool) {
+	switch {
+	case word == "normal", word == "default":
+		return lipgloss.NoColor{}, true
+	case strings.HasPrefix(word, "#") && (len(word) == 7 || len(word) == 4):
+		return lipgloss.Color(word), true
+	}
+	if n, err := strconv.Atoi(word); err == nil && n >= 0 && n <= 255 {
+		return lipgloss.Color(word), true
+	}
+	bright := strings.HasPrefix(word, "bright")
+	for i, name := range gitColorNames {
+		if strings.TrimPrefix(word, "bright") == name {
+			if bright {
+				i += 8
+			}
+			return lipgloss.Color(strconv.Itoa(i)), true
+		}
+	}
+	return nil, false
+}
+
+// Color the diff like git diff does on the command line, from the color.diff
+// slots of the user's git config over the theme, without color if they turn
+// color.diff off
+func applyGitColors(r repo) {
+	// Keys come lowercased, the last value of one wins like in git
+	values := make(map[string]string)
+	output, _ := r.git("config", "--get-regexp", `^color\.diff(\..*)?$`).Output()
+	for _, line := range splitDiffLines(string(output)) {
+		name, value, _ := strings.Cut(line, " ")
+		values[name] = value
+	}
+	styles := map[string]*lipgloss.Style{
+		"color.diff.meta":     &diffMetaStyle,
+		"color.diff.frag":     &diffHunkStyle,
+		"color.diff.old":      &diffRemovedStyle,
+		"color.diff.new":      &diffAddedStyle,
+		"color.diff.oldmoved": &diffMovedRemovedStyle,
+		"color.diff.newmoved": &diffMovedAddedStyle,
+	}
+	on, err := strconv.ParseBool(values["color.diff"])
+	off := (err == nil && !on) || values["color.diff"] == "never"
+	for name, style := range styles {
+		if off {
+			*style = lipgloss.NewStyle()
+		} else if s, ok := parseGitColor(values[name]); ok && values[name] != "" {
+			*style = s
+		}
+	}
+}
+
+// Whether diff.colorMoved sets a mode for git diff to color moved lines in
+func gitColorMoved(r repo) bool {
+	switch trimmedOutput(r.git("config", "diff.colorMoved").Output()) {
+	case "", "no", "false", "0", "off":
+		return false
+	}
+	return true
+}
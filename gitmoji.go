@@ -0,0 +1,110 @@
+package main
+
+import (
+	"errors"
+	"strings"
+)
+
+// How the gitmoji picker puts the emoji at the start of the subject, set
+// by gitmoji in the [commit] config, off when empty
+const (
+	gitmojiCode  = "code"  // like :sparkles:
+	gitmojiEmoji = "emoji" // like ✨
+)
+
+type gitmoji struct {
+	emoji       string
+	code        string
+	description string
+}
+
+// The commonly used part of gitmoji.dev
+var gitmojis = []gitmoji{
+	{"🎨", ":art:", "Improve structure / format of the code"},
+	{"⚡️", ":zap:", "Improve performance"},
+	{"🔥", ":fire:", "Remove code or files"},
+	{"🐛", ":bug:", "Fix a bug"},
+	{"🚑️", ":ambulance:", "Critical hotfix"},
+	{"✨", ":sparkles:", "Introduce new features"},
+	{"📝", ":memo:", "Add or update documentation"},
+	{"🚀", ":rocket:", "Deploy stuff"},
+	{"💄", ":lipstick:", "Add or update the UI and style files"},
+	{"🎉", ":tada:", "Begin a project"},
+	{"✅", ":white_check_mark:", "Add, update, or pass tests"},
+	{"🔒️", ":lock:", "Fix security or privacy issues"},
+	{"🔖", ":bookmark:", "Release / Version tags"},
+	{"🚨", ":rotating_light:", "Fix compiler / linter warnings"},
+	{"🚧", ":construction:", "Work in progress"},
+	{"💚", ":green_heart:", "Fix CI build"},
+	{"⬇️", ":arrow_down:", "Downgrade dependencies"},
+	{"⬆️", ":arrow_up:", "Upgrade dependencies"},
+	{"📌", ":pushpin:", "Pin dependencies to specific versions"},
+	{"👷", ":construction_worker:", "Add or update CI build system"},
+	{"♻️", ":recycle:", "Refactor code"},
+	{"➕", ":heavy_plus_sign:", "Add a dependency"},
+	{"➖", ":heavy_minus_sign:", "Remove a dependency"},
+	{"🔧", ":wrench:", "Add or update configuration files"},
+	{"🌐", ":globe_with_meridians:", "Internationalization and localization"},
+	{"✏️", ":pencil2:", "Fix typos"},
+	{"⏪️", ":rewind:", "Revert changes"},
+	{"🔀", ":twisted_rightwards_arrows:", "Merge branches"},
+	{"🚚", ":truck:", "Move or rename resources"},
+	{"💥", ":boom:", "Introduce breaking changes"},
+	{"♿️", ":wheelchair:", "Improve accessibility"},
+	{"💡", ":bulb:", "Add or update comments in source code"},
+	{"🗃️", ":card_file_box:", "Perform database related changes"},
+	{"🔊", ":loud_sound:", "Add or update logs"},
+	{"🙈", ":see_no_evil:", "Add or update a .gitignore file"},
+	{"🏷️", ":label:", "Add or update types"},
+	{"🩹", ":adhesive_bandage:", "Simple fix for a non-critical issue"},
+	{"⚰️", ":coffin:", "Remove dead code"},
+}
+
+func checkGitmoji(style string) error {
+	if style != "" && style != gitmojiCode && style != gitmojiEmoji {
+		return errors.New(tr("Unknown gitmoji %q in [commit], use %q or %q", style, gitmojiCode, gitmojiEmoji))
+	}
+	return nil
+}
+
+func (g gitmoji) String() string {
+	return g.emoji + " " + g.code + " " + g.description
+}
+
+// What goes at the start of the subject
+func (g gitmoji) prefix() string {
+	if cfg.Commit.Gitmoji == gitmojiCode {
+		return g.code
+	}
+	return g.emoji
+}
+
+// Start the subject with a gitmoji, instead of the one it starts with
+func withGitmoji(message string, g gitmoji) string {
+	for _, other := range gitmojis {
+		for _, p := range []string{other.emoji, other.code} {
+			if rest, ok := strings.CutPrefix(message, p+" "); ok {
+				message = rest
+			}
+		}
+	}
+	return g.prefix() + " " + message
+}
+
+func (m *model) openGitmojiPicker() {
+	if cfg.Commit.Gitmoji == "" {
+		m.status = tr("Set gitmoji in the [commit] config to pick one")
+		return
+	}
+	var candidates []string
+	for _, g := range gitmojis {
+		candidates = append(candidates, g.String())
+	}
+	m.picker = newPicker(tr("Gitmoji: "), candidates, func(m *model, choice string) {
+		for _, g := range gitmojis {
+			if g.String() == choice {
+				m.editor.SetValue(withGitmoji(m.editor.Value(), g))
+			}
+		}
+	})
+}
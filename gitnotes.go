@@ -0,0 +1,149 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Commits listed in recentMode
+const recentCommitCount = 30
+
+// A commit of the branch with its git note, like review context or
+// benchmark numbers
+type recentCommit struct {
+	hash    string
+	age     string
+	subject string
+	note    string
+}
+
+// State of recentMode
+type recentCommits struct {
+	commits []recentCommit
+	cursor  int
+}
+
+// The latest commits of HEAD, newest first
+func getRecentCommits(r repo) []recentCommit {
+	// Notes may have several lines, so commits end with \x01
+	output, err := r.git("log", "-n", strconv.Itoa(recentCommitCount), "--format=%h%x00%ar%x00%s%x00%N%x01").Output()
+	if err != nil {
+		return nil
+	}
+	var commits []recentCommit
+	for record := range strings.SplitSeq(string(output), "\x01") {
+		fields := strings.SplitN(strings.TrimLeft(record, "\n"), "\x00", 4)
+		if len(fields) < 4 {
+			continue
+		}
+		commits = append(commits, recentCommit{fields[0], fields[1], fields[2], strings.TrimSpace(fields[3])})
+	}
+	return commits
+}
+
+func (m *model) openRecentCommits() {
+	commits := getRecentCommits(m.repo)
+	if len(commits) == 0 {
+		m.status = tr("No commits yet")
+		return
+	}
+	m.recent = &recentCommits{commits: commits}
+	m.mode = recentMode
+}
+
+func (m *model) updateRecentCommits(key string) {
+	rc := m.recent
+	switch {
+	case m.keys.up.matches(key):
+		rc.cursor = max(rc.cursor-1, 0)
+	case m.keys.down.matches(key):
+		rc.cursor = min(rc.cursor+1, len(rc.commits)-1)
+	case m.keys.note.matches(key):
+		m.editGitNote(rc.commits[rc.cursor])
+	case m.keys.focusList.matches(key), m.keys.recentCommits.matches(key):
+		m.recent = nil
+		m.mode = listMode
+		m.loadDiff()
+	}
+}
+
+// Start writing the git note of a commit, or editing the one it has
+func (m *model) editGitNote(c recentCommit) {
+	input := textinput.New()
+	input.Prompt = tr("Git note for %s: ", c.hash)
+	input.PromptStyle = promptStyle
+	input.Cursor.SetMode(cursor.CursorStatic)
+	// The prompt has a single line, the lines of a longer note are joined
+	input.SetValue(strings.ReplaceAll(c.note, "\n", " "))
+	input.Focus()
+	m.gitNote = &noteEditor{c.hash, input}
+}
+
+func (m *model) updateGitNote(msg tea.KeyMsg) {
+	switch key := keyName(msg); {
+	case key == "enter":
+		hash, note := m.gitNote.path, strings.TrimSpace(m.gitNote.input.Value())
+		m.gitNote = nil
+		m.saveGitNote(hash, note)
+	case m.keys.cancel.matches(key):
+		m.gitNote = nil
+	default:
+		m.gitNote.input, _ = m.gitNote.input.Update(msg)
+	}
+}
+
+// git notes add, replacing the note the commit has, or remove it when empty
+func (m *model) saveGitNote(hash, note string) {
+	args := []string{"notes", "add", "-f", "-m", note, hash}
+	if note == "" {
+		args = []string{"notes", "remove", "--ignore-missing", hash}
+	}
+	if err := m.repo.run(args...); err != nil {
+		m.status = tr("Failed to attach the note to %s: %v", hash, err)
+		return
+	}
+	if m.recent != nil {
+		m.recent.commits = getRecentCommits(m.repo)
+		m.recent.cursor = min(m.recent.cursor, max(len(m.recent.commits)-1, 0))
+	}
+	if note == "" {
+		m.status = tr("Removed the note of %s", hash)
+	} else {
+		m.status = tr("Attached the note to %s", hash)
+	}
+}
+
+func (m model) gitNoteView() string {
+	m.gitNote.input.Width = max(m.width-ansi.StringWidth(m.gitNote.input.Prompt)-1, 0)
+	return m.gitNote.input.View()
+}
+
+// Commits with their notes below them, indented
+func (m model) recentCommitsView(width, height int) string {
+	rc := m.recent
+	var lines []string
+	cursorLine := 0
+	for i, c := range rc.commits {
+		if i == rc.cursor {
+			cursorLine = len(lines)
+		}
+		row := cfg.Glyphs.cursor(i == rc.cursor) + cursorStyle.Render(c.hash) + " " + badgeStyle.Render(c.age) + " " + c.subject
+		lines = append(lines, ansi.Truncate(row, width, "…"))
+		for line := range strings.SplitSeq(c.note, "\n") {
+			if line != "" {
+				lines = append(lines, ansi.Truncate("    "+badgeStyle.Render("✎ "+line), width, "…"))
+			}
+		}
+	}
+	rows := []string{sectionStyle.Render(ansi.Truncate(tr("Recent commits and their notes"), width, "…"))}
+	offset := max(cursorLine+1-(height-1), 0)
+	for i := offset; i < len(lines) && len(rows) < height; i++ {
+		rows = append(rows, lines[i])
+	}
+	return strings.Join(rows, "\n")
+}
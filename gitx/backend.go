@@ -0,0 +1,129 @@
+package gitx
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// What git-istage asks of a repository. Paths are relative to the directory
+// it was opened from, except those git status lists, which are from the root.
+// Errors of commands writing the index carry git's own message.
+type Backend interface {
+	// Pass the XY code of every file `git status --porcelain` lists to fn,
+	// as soon as it's listed
+	Status(fn func(path, xy string)) error
+	// The first limit lines of `git diff` with args, all of them for a
+	// negative limit, and how many more there were
+	Diff(limit int, args ...string) ([]byte, int, error)
+	Stage(paths ...string) error
+	// Stage files outside the sparse-checkout cone
+	StageSparse(paths ...string) error
+	Unstage(paths ...string) error
+	// Commit the index, with options like --author passed to git commit
+	Commit(message string, options ...string) error
+	// The checked out branch, or a short commit hash when HEAD is detached
+	Branch() string
+}
+
+// The backend running the git command line in Dir
+type CLI struct {
+	Dir string
+	// How similar in percent a deleted and an added file must be for status
+	// and diffs to show a rename, git's default of 50 if 0
+	FindRenames int
+	// Diffs fail at once on the objects a partial clone lacks, rather than
+	// fetching them from its remote without a word
+	NoLazyFetch bool
+}
+
+func (c CLI) renames() []string {
+	if c.FindRenames == 0 {
+		return nil
+	}
+	return []string{fmt.Sprintf("--find-renames=%d%%", c.FindRenames)}
+}
+
+func (c CLI) Status(fn func(path, xy string)) error {
+	return Command(c.Dir, append([]string{"status", "--porcelain"}, c.renames()...)...).Lines(func(line string) {
+		// The first 2 letters of each line are the status, then comes the path,
+		// or "old -> new" for a rename, each quoted apart
+		if len(line) < 4 {
+			return
+		}
+		path := line[3:]
+		if from, to, renamed := cutRename(path); renamed {
+			path = UnquotePath(from) + " -> " + UnquotePath(to)
+		} else {
+			path = UnquotePath(path)
+		}
+		fn(path, line[:2])
+	})
+}
+
+// Split "old -> new", where old may be quoted and contain " -> " then
+func cutRename(s string) (from, to string, renamed bool) {
+	if strings.HasPrefix(s, `"`) {
+		for i := 1; i < len(s); i++ {
+			switch s[i] {
+			case '\\':
+				i++
+			case '"':
+				to, renamed = strings.CutPrefix(s[i+1:], " -> ")
+				return s[:i+1], to, renamed
+			}
+		}
+	}
+	return strings.Cut(s, " -> ")
+}
+
+// A path as git prints it, in double quotes with C escapes when it has bytes
+// that core.quotePath escapes, like "\346\227\245.txt" for 日.txt or a tab
+func UnquotePath(s string) string {
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return s
+	}
+	// Git's escapes are the ones of Go: \a to \v, \", \\ and octal bytes
+	if path, err := strconv.Unquote(s); err == nil {
+		return path
+	}
+	return s
+}
+
+func (c CLI) Diff(limit int, args ...string) ([]byte, int, error) {
+	// Non-ASCII paths in the headers as they are, rather than octal escapes
+	args = append(append([]string{"-c", "core.quotePath=false", "diff"}, c.renames()...), args...)
+	if !c.NoLazyFetch {
+		return Command(c.Dir, args...).OutputLines(limit)
+	}
+	// GIT_NO_LAZY_FETCH is new in git 2.44, older ones can't fetch at all
+	cmd := Command(c.Dir, append([]string{"-c", "protocol.allow=never"}, args...)...)
+	cmd.Env = append(cmd.Environ(), "GIT_NO_LAZY_FETCH=1")
+	return cmd.OutputLines(limit)
+}
+
+func (c CLI) Stage(paths ...string) error {
+	return Run(c.Dir, append([]string{"add", "--"}, paths...)...)
+}
+
+func (c CLI) StageSparse(paths ...string) error {
+	return Run(c.Dir, append([]string{"add", "--sparse", "--"}, paths...)...)
+}
+
+func (c CLI) Unstage(paths ...string) error {
+	return Run(c.Dir, append([]string{"restore", "--staged", "--"}, paths...)...)
+}
+
+func (c CLI) Commit(message string, options ...string) error {
+	return RunInput(c.Dir, message+"\n", append([]string{"commit", "-F", "-"}, options...)...)
+}
+
+func (c CLI) Branch() string {
+	if output, err := Command(c.Dir, "symbolic-ref", "--short", "-q", "HEAD").Output(); err == nil {
+		return strings.TrimSpace(string(output))
+	}
+	if output, err := Command(c.Dir, "rev-parse", "--short", "HEAD").Output(); err == nil {
+		return "(detached at " + strings.TrimSpace(string(output)) + ")"
+	}
+	return ""
+}
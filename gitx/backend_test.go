@@ -0,0 +1,206 @@
+package gitx
+
+import (
+	"bytes"
+	"io"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestRepo(t *testing.T) CLI {
+	dir := t.TempDir()
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "main"},
+		{"config", "user.name", "Test"},
+		{"config", "user.email", "test@example.com"},
+	} {
+		if err := Run(dir, args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return CLI{Dir: dir}
+}
+
+func writeFile(t *testing.T, c CLI, name, content string) {
+	if err := os.WriteFile(filepath.Join(c.Dir, name), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func status(t *testing.T, c CLI) map[string]string {
+	result := make(map[string]string)
+	if err := c.Status(func(path, xy string) { result[path] = xy }); err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func TestCLIStageUnstageCommit(t *testing.T) {
+	c := newTestRepo(t)
+	writeFile(t, c, "a.txt", "one\n")
+	if got := status(t, c)["a.txt"]; got != "??" {
+		t.Fatalf("status of a new file = %q, want ??", got)
+	}
+	if err := c.Stage("a.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if got := status(t, c)["a.txt"]; got != "A " {
+		t.Fatalf("status after staging = %q, want A", got)
+	}
+	if err := c.Commit("Add a"); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.Branch(); got != "main" {
+		t.Errorf("Branch() = %q, want main", got)
+	}
+
+	writeFile(t, c, "a.txt", "one\ntwo\n")
+	if err := c.Stage("a.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Unstage("a.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if got := status(t, c)["a.txt"]; got != " M" {
+		t.Errorf("status after unstaging = %q, want  M", got)
+	}
+}
+
+func TestCLIDiffLimit(t *testing.T) {
+	c := newTestRepo(t)
+	writeFile(t, c, "a.txt", "one\n")
+	if err := c.Stage("a.txt"); err != nil {
+		t.Fatal(err)
+	}
+	output, more, err := c.Diff(2, "--cached")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := bytes.Count(output, []byte("\n")); lines != 2 || more == 0 {
+		t.Errorf("Diff(2) gave %d lines and %d more, want 2 and the rest", lines, more)
+	}
+	_, more, _ = c.Diff(-1, "--cached")
+	if more != 0 {
+		t.Errorf("Diff(-1) left out %d lines", more)
+	}
+}
+
+func TestCommitFailureCarriesGitMessage(t *testing.T) {
+	c := newTestRepo(t)
+	err := c.Commit("Nothing")
+	if err == nil {
+		t.Fatal("committing an empty index succeeded")
+	}
+	if err.Error() == "exit status 1" {
+		t.Errorf("error %q doesn't say what git said", err)
+	}
+}
+
+func TestIndexLockedUnderATranslatedGit(t *testing.T) {
+	c := newTestRepo(t)
+	writeFile(t, c, "a.txt", "a\n")
+	writeFile(t, c, ".git/index.lock", "")
+	// Git's messages in German, where its catalogs are installed
+	t.Setenv("LC_ALL", "C.UTF-8")
+	t.Setenv("LANGUAGE", "de")
+	delays := lockRetryDelays
+	lockRetryDelays = []time.Duration{time.Millisecond}
+	t.Cleanup(func() { lockRetryDelays = delays })
+	if err := c.Stage("a.txt"); err != ErrIndexLocked {
+		t.Errorf("staging with the index locked fails with %v", err)
+	}
+}
+
+func TestCLIFindRenames(t *testing.T) {
+	c := newTestRepo(t)
+	writeFile(t, c, "old.txt", "the first line\nthe second line\nthe third line\nthe fourth line\nthe fifth line\n")
+	if err := c.Stage("old.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Commit("Add old"); err != nil {
+		t.Fatal(err)
+	}
+	// Moved, with most of it rewritten
+	if err := os.Remove(filepath.Join(c.Dir, "old.txt")); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, c, "new.txt", "the first line\nthe second line\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n")
+	if err := c.Stage("old.txt", "new.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if got := status(t, c); got["old.txt"] != "D " || got["new.txt"] != "A " {
+		t.Errorf("status with git's threshold = %v, want old.txt deleted and new.txt added", got)
+	}
+	c.FindRenames = 20
+	if got := status(t, c)["old.txt -> new.txt"]; got != "R " {
+		t.Errorf("status with a threshold of 20%% = %v, want a rename", status(t, c))
+	}
+	diff, _, err := c.Diff(-1, "--cached", "--summary")
+	if err != nil || !bytes.Contains(diff, []byte("rename old.txt => new.txt")) {
+		t.Errorf("diff --summary with a threshold of 20%% = %q, %v, want a rename", diff, err)
+	}
+}
+
+func TestHangingHookTimesOut(t *testing.T) {
+	c := newTestRepo(t)
+	hook := filepath.Join(c.Dir, ".git", "hooks", "pre-commit")
+	if err := os.WriteFile(hook, []byte("#!/bin/sh\nsleep 5\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, c, "a.txt", "a\n")
+	if err := c.Stage("a.txt"); err != nil {
+		t.Fatal(err)
+	}
+	Timeout = 200 * time.Millisecond
+	t.Cleanup(func() { Timeout = 0 })
+	err := c.Commit("Add a")
+	if !IsTimeout(err) || err.Error() != "git commit timed out after 200ms" {
+		t.Errorf("Commit() = %v, want a timeout", err)
+	}
+}
+
+func TestStatusUnquotesPaths(t *testing.T) {
+	c := newTestRepo(t)
+	writeFile(t, c, "日本.txt", "a\n")
+	writeFile(t, c, "tab\there.txt", "a\n")
+	writeFile(t, c, `"open`, "b\n")
+	if err := c.Stage("日本.txt", `"open`); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Commit("Add 日本.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Run(c.Dir, "mv", "日本.txt", `"quoted".txt`); err != nil {
+		t.Fatal(err)
+	}
+	// Quotes around the whole rename once each name is unquoted
+	if err := Run(c.Dir, "mv", `"open`, `close"`); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{`日本.txt -> "quoted".txt`: "R ", `"open -> close"`: "R ", "tab\there.txt": "??"}
+	if got := status(t, c); !maps.Equal(got, want) {
+		t.Errorf("status = %q, want %q", got, want)
+	}
+	diff, _, err := c.Diff(-1, "--cached", "-M")
+	if err != nil || !bytes.Contains(diff, []byte("rename from 日本.txt")) {
+		t.Errorf("Diff() = %q, %v, want the path unescaped", diff, err)
+	}
+}
+
+func TestLinesFinishesWhenThePipeFails(t *testing.T) {
+	finished := Finished
+	t.Cleanup(func() { Finished = finished })
+	var reported error
+	Finished = func(cmd *exec.Cmd, start time.Time, err error) { reported = err }
+	cmd := Command(t.TempDir(), "version")
+	cmd.Stdout = io.Discard
+	if err := cmd.Lines(func(string) {}); err == nil || reported != err {
+		t.Errorf("Lines with stdout taken = %v, reported %v", err, reported)
+	}
+}
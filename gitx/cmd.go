@@ -0,0 +1,156 @@
+// Package gitx runs git for git-istage: the commands it runs, and the
+// backend of a repository behind which the program asks git for changes
+// and stages them.
+package gitx
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// How long a killed git command gets to close its output, which children
+// such as diff drivers may still hold open
+const KillWaitDelay = time.Second
+
+// Set up by the program: the context commands running in a directory are
+// started with, to kill them, what's done with each command once it finished, like logging it, and
+// how long a command may run before it's killed, without limit when 0
+var (
+	Context  = func(dir string) context.Context { return context.Background() }
+	Finished = func(cmd *exec.Cmd, start time.Time, err error) {}
+	Timeout  time.Duration
+)
+
+// Returned by commands killed after running for Timeout
+type TimeoutError struct {
+	Command string // like "git fetch"
+	Timeout time.Duration
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("%s timed out after %v", e.Command, e.Timeout)
+}
+
+func IsTimeout(err error) bool {
+	var timeout *TimeoutError
+	return errors.As(err, &timeout)
+}
+
+// A git command that reports to Finished when run
+type Cmd struct {
+	*exec.Cmd
+	ctx     context.Context
+	cancel  context.CancelFunc
+	timeout time.Duration
+}
+
+// Build a git command running in dir, killed after Timeout. Its messages
+// aren't translated, failures like a held index lock are told apart by them.
+func Command(dir string, args ...string) *Cmd {
+	cmd := command(Timeout, dir, args)
+	cmd.Env = append(os.Environ(), "LANGUAGE=C")
+	return cmd
+}
+
+// Build a git command running in dir that may take as long as it wants,
+// for commands the user interacts with, like an editor opened by git
+func Interactive(dir string, args ...string) *Cmd {
+	return command(0, dir, args)
+}
+
+func command(timeout time.Duration, dir string, args []string) *Cmd {
+	ctx, cancel := Context(dir), context.CancelFunc(func() {})
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	}
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.WaitDelay = KillWaitDelay
+	return &Cmd{cmd, ctx, cancel, timeout}
+}
+
+// Report the command to Finished, with a TimeoutError if it ran too long
+func (c *Cmd) finish(start time.Time, err error) error {
+	if err != nil && errors.Is(c.ctx.Err(), context.DeadlineExceeded) {
+		err = &TimeoutError{"git " + subcommand(c.Args[1:]), c.timeout}
+	}
+	c.cancel()
+	Finished(c.Cmd, start, err)
+	return err
+}
+
+// The git command of args, after options like -c name=value
+func subcommand(args []string) string {
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "-c", args[i] == "-C":
+			i++
+		case !strings.HasPrefix(args[i], "-"):
+			return args[i]
+		}
+	}
+	return ""
+}
+
+func (c *Cmd) Run() error {
+	start := time.Now()
+	return c.finish(start, c.Cmd.Run())
+}
+
+func (c *Cmd) Output() ([]byte, error) {
+	start := time.Now()
+	output, err := c.Cmd.Output()
+	return output, c.finish(start, err)
+}
+
+func (c *Cmd) CombinedOutput() ([]byte, error) {
+	start := time.Now()
+	output, err := c.Cmd.CombinedOutput()
+	return output, c.finish(start, err)
+}
+
+// Run the command, passing each line of its output to fn as soon as it's read
+func (c *Cmd) Lines(fn func(line string)) error {
+	start := time.Now()
+	stdout, err := c.Cmd.StdoutPipe()
+	if err != nil {
+		return c.finish(start, err)
+	}
+	if err := c.Cmd.Start(); err != nil {
+		return c.finish(start, err)
+	}
+	reader := bufio.NewReader(stdout)
+	for {
+		line, readErr := reader.ReadString('\n')
+		if line != "" {
+			fn(strings.TrimSuffix(line, "\n"))
+		}
+		if readErr != nil {
+			break
+		}
+	}
+	return c.finish(start, c.Cmd.Wait())
+}
+
+// The first limit lines of the output, all of them for a negative limit, and
+// how many more there were
+func (c *Cmd) OutputLines(limit int) ([]byte, int, error) {
+	var output bytes.Buffer
+	kept, more := 0, 0
+	err := c.Lines(func(line string) {
+		if limit >= 0 && kept >= limit {
+			more++
+			return
+		}
+		output.WriteString(line + "\n")
+		kept++
+	})
+	return output.Bytes(), more, err
+}
@@ -0,0 +1,335 @@
+package gitx
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// A made up repository in memory, with a file in every state the list
+// shows. Staging moves whole files between the index and the work tree.
+type Demo struct {
+	mu    sync.Mutex
+	files []*demoFile
+}
+
+type demoFile struct {
+	path   string
+	change byte     // XY letter of the change: M, A, D, R, or U for a conflict
+	header []string // of the diff, without the hunks
+	hunks  []demoHunk
+	binary bool
+}
+
+type demoHunk struct {
+	lines  []string
+	staged bool
+}
+
+func NewDemo() *Demo {
+	return &Demo{files: []*demoFile{
+		{
+			path:   "cmd/server/main.go",
+			change: 'M',
+			header: []string{"diff --git a/cmd/server/main.go b/cmd/server/main.go", "index 3f2a9c1..8be04d2 100644", "--- a/cmd/server/main.go", "+++ b/cmd/server/main.go"},
+			hunks: []demoHunk{
+				{staged: true, lines: []string{
+					"@@ -12,7 +12,8 @@ import (",
+					" \t\"os\"",
+					" \t\"time\"",
+					" ",
+					"-\t\"github.com/example/shop/internal/config\"",
+					"+\t\"github.com/example/shop/internal/config\"",
+					"+\t\"github.com/example/shop/internal/metrics\"",
+					" \t\"github.com/example/shop/internal/server\"",
+					" )",
+					" ",
+				}},
+				{lines: []string{
+					"@@ -41,9 +42,13 @@ func main() {",
+					" \tif err != nil {",
+					" \t\tlog.Fatalf(\"loading config: %v\", err)",
+					" \t}",
+					"-\tsrv := server.New(cfg)",
+					"-\tlog.Fatal(srv.ListenAndServe())",
+					"+\tsrv := server.New(cfg, server.WithMetrics(metrics.Default()))",
+					"+\tshutdown := make(chan os.Signal, 1)",
+					"+\tgo func() {",
+					"+\t\t<-shutdown",
+					"+\t\tsrv.Shutdown(10 * time.Second)",
+					"+\t}()",
+					"+\tlog.Fatal(srv.ListenAndServe())",
+					" }",
+				}},
+			},
+		},
+		{
+			path:   "internal/server/handler.go",
+			change: 'M',
+			header: []string{"diff --git a/internal/server/handler.go b/internal/server/handler.go", "index 91c0e7a..c44d215 100644", "--- a/internal/server/handler.go", "+++ b/internal/server/handler.go"},
+			hunks: []demoHunk{{staged: true, lines: []string{
+				"@@ -27,6 +27,10 @@ func (s *Server) handleOrder(w http.ResponseWriter, r *http.Request) {",
+				" \tvar order Order",
+				" \tif err := json.NewDecoder(r.Body).Decode(&order); err != nil {",
+				" \t\thttp.Error(w, \"invalid order\", http.StatusBadRequest)",
+				" \t\treturn",
+				" \t}",
+				"+\tif len(order.Items) == 0 {",
+				"+\t\thttp.Error(w, \"an order needs at least one item\", http.StatusUnprocessableEntity)",
+				"+\t\treturn",
+				"+\t}",
+				" \ts.orders.Add(order)",
+			}}},
+		},
+		{
+			path:   "README.md",
+			change: 'M',
+			header: []string{"diff --git a/README.md b/README.md", "index 5d1e0b3..a7f93c8 100644", "--- a/README.md", "+++ b/README.md"},
+			hunks: []demoHunk{{lines: []string{
+				"@@ -1,6 +1,6 @@",
+				" # shop",
+				" ",
+				"-A tiny web shop.",
+				"+A tiny web shop with metrics and graceful shutdown.",
+				" ",
+				" ## Running",
+				" ",
+			}}},
+		},
+		{
+			path:   "config.yaml",
+			change: 'U',
+			header: []string{"diff --cc config.yaml", "index 1b7e2f0,4c9d3a1..0000000", "--- a/config.yaml", "+++ b/config.yaml"},
+			hunks: []demoHunk{{lines: []string{
+				"@@@ -1,5 -1,5 +1,9 @@@",
+				"  server:",
+				"    listen: \":8080\"",
+				"++<<<<<<< HEAD",
+				" +  timeout: 30s",
+				"++=======",
+				"+   timeout: 10s",
+				"+   max_body: 1MiB",
+				"++>>>>>>> feature/limits",
+				"  database:",
+				"    url: postgres://localhost/shop",
+			}}},
+		},
+		{
+			path:   "internal/util.go -> internal/strutil/strutil.go",
+			change: 'R',
+			header: []string{"diff --git a/internal/util.go b/internal/strutil/strutil.go", "similarity index 92%", "rename from internal/util.go", "rename to internal/strutil/strutil.go", "index 0e4b8d2..6a1f7c9 100644", "--- a/internal/util.go", "+++ b/internal/strutil/strutil.go"},
+			hunks: []demoHunk{{staged: true, lines: []string{
+				"@@ -1,4 +1,4 @@",
+				"-package internal",
+				"+package strutil",
+				" ",
+				" import \"strings\"",
+				" ",
+			}}},
+		},
+		{
+			path:   "web/static/logo.png",
+			change: 'M',
+			binary: true,
+			header: []string{"diff --git a/web/static/logo.png b/web/static/logo.png", "index 7c3e9a2..f01b6d4 100644"},
+			hunks:  []demoHunk{{lines: []string{"Binary files a/web/static/logo.png and b/web/static/logo.png differ"}}},
+		},
+		{
+			path:   "scripts/deploy.sh",
+			change: 'D',
+			header: []string{"diff --git a/scripts/deploy.sh b/scripts/deploy.sh", "deleted file mode 100755", "index 2ad8e41..0000000", "--- a/scripts/deploy.sh", "+++ /dev/null"},
+			hunks: []demoHunk{{lines: []string{
+				"@@ -1,4 +0,0 @@",
+				"-#!/bin/sh",
+				"-set -e",
+				"-go build -o shop ./cmd/server",
+				"-scp shop deploy@shop.example.com:/srv/shop/",
+			}}},
+		},
+		{
+			path:   "docs/metrics.md",
+			change: 'A',
+			header: []string{"diff --git a/docs/metrics.md b/docs/metrics.md", "new file mode 100644", "index 0000000..9e2c5b7", "--- /dev/null", "+++ b/docs/metrics.md"},
+			hunks: []demoHunk{{lines: []string{
+				"@@ -0,0 +1,6 @@",
+				"+# Metrics",
+				"+",
+				"+The server exports Prometheus metrics on `/metrics`:",
+				"+",
+				"+- `shop_orders_total`, orders taken",
+				"+- `shop_request_seconds`, request latency",
+			}}},
+		},
+	}}
+}
+
+func (f *demoFile) has(staged bool) bool {
+	return slices.ContainsFunc(f.hunks, func(h demoHunk) bool { return h.staged == staged })
+}
+
+// The XY code git status would show
+func (f *demoFile) xy() string {
+	switch {
+	case f.change == 'U':
+		return "UU"
+	case f.change == 'A' && !f.has(true):
+		return "??"
+	}
+	x, y := byte(' '), byte(' ')
+	if f.has(true) {
+		x = f.change
+	}
+	if f.has(false) {
+		y = f.change
+		if x != ' ' {
+			// Changed again after it was staged
+			y = 'M'
+		}
+	}
+	return string([]byte{x, y})
+}
+
+func (d *Demo) Status(fn func(path, xy string)) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, f := range d.files {
+		fn(f.path, f.xy())
+	}
+	return nil
+}
+
+// Understands the diffs git-istage asks for: numstat of the index or the
+// work tree, and the diff of one file, the path coming last
+func (d *Demo) Diff(limit int, args ...string) ([]byte, int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	cached := slices.Contains(args, "--cached")
+	var lines []string
+	switch {
+	case slices.Contains(args, "--numstat"):
+		for _, f := range d.files {
+			if f.change == 'U' || !f.has(cached) {
+				continue
+			}
+			added, deleted := f.numstat(cached)
+			lines = append(lines, fmt.Sprintf("%s\t%s\t%s", added, deleted, f.path))
+		}
+	case slices.Contains(args, "--summary"):
+	default:
+		if f := d.file(args[len(args)-1]); f != nil {
+			lines = f.diff(cached || f.change == 'U', !cached || f.change == 'U')
+		}
+	}
+	more := 0
+	if limit >= 0 && len(lines) > limit {
+		lines, more = lines[:limit], len(lines)-limit
+	}
+	if len(lines) == 0 {
+		return nil, more, nil
+	}
+	return []byte(strings.Join(lines, "\n") + "\n"), more, nil
+}
+
+func (d *Demo) file(path string) *demoFile {
+	for _, f := range d.files {
+		if f.path == path {
+			return f
+		}
+	}
+	return nil
+}
+
+// Lines of the staged and/or unstaged hunks with the header, none if there
+// are no such hunks
+func (f *demoFile) diff(staged, unstaged bool) []string {
+	var lines []string
+	for _, h := range f.hunks {
+		if (h.staged && staged) || (!h.staged && unstaged) {
+			lines = append(lines, h.lines...)
+		}
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	return append(slices.Clone(f.header), lines...)
+}
+
+func (f *demoFile) numstat(staged bool) (string, string) {
+	if f.binary {
+		return "-", "-"
+	}
+	added, deleted := 0, 0
+	for _, line := range f.diff(staged, !staged) {
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+		case strings.HasPrefix(line, "+"):
+			added++
+		case strings.HasPrefix(line, "-"):
+			deleted++
+		}
+	}
+	return fmt.Sprint(added), fmt.Sprint(deleted)
+}
+
+func (d *Demo) setStaged(staged bool, paths []string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, path := range paths {
+		f := d.file(path)
+		if f == nil {
+			return fmt.Errorf("pathspec '%s' did not match any files", path)
+		}
+		if f.change == 'U' {
+			if !staged {
+				continue
+			}
+			// Staged as it is, conflict markers and all, like git add would
+			f.change = 'M'
+		}
+		for i := range f.hunks {
+			f.hunks[i].staged = staged
+		}
+	}
+	return nil
+}
+
+func (d *Demo) Stage(paths ...string) error {
+	return d.setStaged(true, paths)
+}
+
+func (d *Demo) StageSparse(paths ...string) error {
+	return d.setStaged(true, paths)
+}
+
+func (d *Demo) Unstage(paths ...string) error {
+	return d.setStaged(false, paths)
+}
+
+// Forget the staged changes, as if they were now in HEAD. The options are
+// left out, since the demo keeps no authors or dates
+func (d *Demo) Commit(message string, options ...string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !slices.ContainsFunc(d.files, func(f *demoFile) bool { return f.change != 'U' && f.has(true) }) {
+		return errors.New("nothing added to commit")
+	}
+	for _, f := range d.files {
+		if f.change == 'U' {
+			continue
+		}
+		if !f.has(true) {
+			continue
+		}
+		f.hunks = slices.DeleteFunc(f.hunks, func(h demoHunk) bool { return h.staged })
+		// The rest of it is a change to a committed file now
+		f.change = 'M'
+	}
+	d.files = slices.DeleteFunc(d.files, func(f *demoFile) bool { return len(f.hunks) == 0 })
+	return nil
+}
+
+func (d *Demo) Branch() string {
+	return "main"
+}
@@ -0,0 +1,45 @@
+package gitx
+
+import "testing"
+
+func demoStatus(d *Demo) map[string]string {
+	result := make(map[string]string)
+	d.Status(func(path, xy string) { result[path] = xy })
+	return result
+}
+
+func TestDemoStagingAndCommit(t *testing.T) {
+	d := NewDemo()
+	before := demoStatus(d)
+	for path, want := range map[string]string{"cmd/server/main.go": "MM", "config.yaml": "UU", "docs/metrics.md": "??"} {
+		if before[path] != want {
+			t.Errorf("%s is %q, want %q", path, before[path], want)
+		}
+	}
+
+	if err := d.Stage("docs/metrics.md", "config.yaml"); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Unstage("internal/server/handler.go"); err != nil {
+		t.Fatal(err)
+	}
+	staged := demoStatus(d)
+	for path, want := range map[string]string{"docs/metrics.md": "A ", "config.yaml": "M ", "internal/server/handler.go": " M"} {
+		if staged[path] != want {
+			t.Errorf("%s is %q after staging, want %q", path, staged[path], want)
+		}
+	}
+
+	if err := d.Commit("Add metrics docs"); err != nil {
+		t.Fatal(err)
+	}
+	committed := demoStatus(d)
+	for path, want := range map[string]string{"docs/metrics.md": "", "cmd/server/main.go": " M", "README.md": " M"} {
+		if committed[path] != want {
+			t.Errorf("%s is %q after committing, want %q", path, committed[path], want)
+		}
+	}
+	if err := d.Commit("Again"); err == nil {
+		t.Error("committing with nothing staged succeeded")
+	}
+}
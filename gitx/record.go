@@ -0,0 +1,173 @@
+package gitx
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// A call to a backend and what it returned, as kept in a session recording
+type Call struct {
+	Method string      `json:"method"`
+	Args   []string    `json:"args,omitempty"`
+	Status [][2]string `json:"status,omitempty"` // path and XY code of each file listed
+	Output string      `json:"output,omitempty"` // of Diff, or the name Branch gave
+	More   int         `json:"more,omitempty"`
+	Err    string      `json:"err,omitempty"`
+}
+
+func (c Call) error() error {
+	switch c.Err {
+	case "":
+		return nil
+	case ErrIndexLocked.Error():
+		return ErrIndexLocked
+	default:
+		return errors.New(c.Err)
+	}
+}
+
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+// Passes calls on to a backend, keeping them with what they returned
+type Recorder struct {
+	Backend
+	mu    sync.Mutex
+	calls []Call
+}
+
+func NewRecorder(b Backend) *Recorder {
+	return &Recorder{Backend: b}
+}
+
+// The calls so far, in the order they returned
+func (r *Recorder) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.calls)
+}
+
+func (r *Recorder) record(c Call) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, c)
+}
+
+func (r *Recorder) Status(fn func(path, xy string)) error {
+	var status [][2]string
+	err := r.Backend.Status(func(path, xy string) {
+		status = append(status, [2]string{path, xy})
+		fn(path, xy)
+	})
+	r.record(Call{Method: "Status", Status: status, Err: errorString(err)})
+	return err
+}
+
+func (r *Recorder) Diff(limit int, args ...string) ([]byte, int, error) {
+	output, more, err := r.Backend.Diff(limit, args...)
+	r.record(Call{Method: "Diff", Args: append([]string{fmt.Sprint(limit)}, args...), Output: string(output), More: more, Err: errorString(err)})
+	return output, more, err
+}
+
+func (r *Recorder) Stage(paths ...string) error {
+	err := r.Backend.Stage(paths...)
+	r.record(Call{Method: "Stage", Args: paths, Err: errorString(err)})
+	return err
+}
+
+func (r *Recorder) StageSparse(paths ...string) error {
+	err := r.Backend.StageSparse(paths...)
+	r.record(Call{Method: "StageSparse", Args: paths, Err: errorString(err)})
+	return err
+}
+
+func (r *Recorder) Unstage(paths ...string) error {
+	err := r.Backend.Unstage(paths...)
+	r.record(Call{Method: "Unstage", Args: paths, Err: errorString(err)})
+	return err
+}
+
+func (r *Recorder) Commit(message string, options ...string) error {
+	err := r.Backend.Commit(message, options...)
+	r.record(Call{Method: "Commit", Args: append([]string{message}, options...), Err: errorString(err)})
+	return err
+}
+
+func (r *Recorder) Branch() string {
+	branch := r.Backend.Branch()
+	r.record(Call{Method: "Branch", Output: branch})
+	return branch
+}
+
+// Answers calls with what the recorded calls with the same method and
+// arguments returned, in the order they were recorded. Once those are used
+// up the last one answers again, since timing may make the program ask
+// more often than it did while recording. Calls never recorded fail.
+type Replay struct {
+	mu    sync.Mutex
+	calls []Call
+	used  []bool
+}
+
+func NewReplay(calls []Call) *Replay {
+	return &Replay{calls: calls, used: make([]bool, len(calls))}
+}
+
+func (r *Replay) answer(method string, args ...string) Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	last := -1
+	for i, c := range r.calls {
+		if c.Method != method || !slices.Equal(c.Args, args) {
+			continue
+		}
+		if !r.used[i] {
+			r.used[i] = true
+			return c
+		}
+		last = i
+	}
+	if last < 0 {
+		return Call{Err: fmt.Sprintf("%s %q is not in the recording", method, args)}
+	}
+	return r.calls[last]
+}
+
+func (r *Replay) Status(fn func(path, xy string)) error {
+	c := r.answer("Status")
+	for _, entry := range c.Status {
+		fn(entry[0], entry[1])
+	}
+	return c.error()
+}
+
+func (r *Replay) Diff(limit int, args ...string) ([]byte, int, error) {
+	c := r.answer("Diff", append([]string{fmt.Sprint(limit)}, args...)...)
+	return []byte(c.Output), c.More, c.error()
+}
+
+func (r *Replay) Stage(paths ...string) error {
+	return r.answer("Stage", paths...).error()
+}
+
+func (r *Replay) StageSparse(paths ...string) error {
+	return r.answer("StageSparse", paths...).error()
+}
+
+func (r *Replay) Unstage(paths ...string) error {
+	return r.answer("Unstage", paths...).error()
+}
+
+func (r *Replay) Commit(message string, options ...string) error {
+	return r.answer("Commit", append([]string{message}, options...)...).error()
+}
+
+func (r *Replay) Branch() string {
+	return r.answer("Branch").Output
+}
@@ -0,0 +1,68 @@
+package gitx
+
+import (
+	"encoding/json"
+	"errors"
+	"maps"
+	"testing"
+)
+
+func TestReplayAnswersAsRecorded(t *testing.T) {
+	rec := NewRecorder(NewDemo())
+	before := demoStatus(rec.Backend.(*Demo))
+	diff, _, _ := rec.Diff(-1, "README.md")
+	if err := rec.Stage("README.md"); err != nil {
+		t.Fatal(err)
+	}
+	status := make(map[string]string)
+	rec.Status(func(path, xy string) { status[path] = xy })
+	if err := rec.Stage("nonexistent"); err == nil {
+		t.Fatal("staging a file the demo doesn't have succeeded")
+	}
+
+	// Through JSON, as in a session file
+	data, err := json.Marshal(rec.Calls())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var calls []Call
+	if err := json.Unmarshal(data, &calls); err != nil {
+		t.Fatal(err)
+	}
+	replay := NewReplay(calls)
+
+	if got, _, _ := replay.Diff(-1, "README.md"); string(got) != string(diff) {
+		t.Errorf("replayed diff is %q, want %q", got, diff)
+	}
+	if err := replay.Stage("README.md"); err != nil {
+		t.Error(err)
+	}
+	replayed := make(map[string]string)
+	replay.Status(func(path, xy string) { replayed[path] = xy })
+	if !maps.Equal(replayed, status) {
+		t.Errorf("replayed status is %v, want %v", replayed, status)
+	}
+	if maps.Equal(replayed, before) {
+		t.Error("replayed status doesn't show README.md staged")
+	}
+	if err := replay.Stage("nonexistent"); err == nil {
+		t.Error("replayed staging didn't fail as recorded")
+	}
+
+	// Asked again, the last answer repeats
+	again := make(map[string]string)
+	replay.Status(func(path, xy string) { again[path] = xy })
+	if !maps.Equal(again, status) {
+		t.Errorf("status asked again is %v, want %v", again, status)
+	}
+	if err := replay.Unstage("README.md"); err == nil {
+		t.Error("a call that wasn't recorded succeeded")
+	}
+}
+
+func TestReplayKeepsIndexLocked(t *testing.T) {
+	replay := NewReplay([]Call{{Method: "Stage", Args: []string{"a"}, Err: ErrIndexLocked.Error()}})
+	if err := replay.Stage("a"); !errors.Is(err, ErrIndexLocked) {
+		t.Errorf("got %v, want ErrIndexLocked", err)
+	}
+}
@@ -0,0 +1,58 @@
+package gitx
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// Returned when another git process held the index lock for longer than
+// RunInput waits
+var ErrIndexLocked = errors.New("the index is locked")
+
+// Delays between attempts while another git process holds the index lock
+var lockRetryDelays = []time.Duration{
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	200 * time.Millisecond,
+	400 * time.Millisecond,
+	800 * time.Millisecond,
+}
+
+// Run a git command that writes the index, retrying for a moment if another
+// git process holds the index lock. Errors carry git's own message.
+func Run(dir string, args ...string) error {
+	return RunInput(dir, "", args...)
+}
+
+// Like Run, with input given to the command on stdin
+func RunInput(dir, input string, args ...string) error {
+	for attempt := 0; ; attempt++ {
+		cmd := Command(dir, args...)
+		if input != "" {
+			cmd.Stdin = strings.NewReader(input)
+		}
+		output, err := cmd.CombinedOutput()
+		if err == nil {
+			return nil
+		}
+		if IsTimeout(err) {
+			return err
+		}
+		if !strings.Contains(string(output), "index.lock': File exists") {
+			if msg := firstLine(string(output)); msg != "" {
+				return errors.New(strings.TrimPrefix(msg, "fatal: "))
+			}
+			return err
+		}
+		if attempt == len(lockRetryDelays) {
+			return ErrIndexLocked
+		}
+		time.Sleep(lockRetryDelays[attempt])
+	}
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(s), "\n")
+	return line
+}
@@ -0,0 +1,53 @@
+package gitx
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Returned by Installed when there's no git in PATH
+var ErrNotInstalled = errors.New("git is not installed")
+
+// A git release, by its major and minor version
+type Version struct {
+	Major, Minor int
+}
+
+// Oldest git with all the commands run: restore came with 2.23
+var MinVersion = Version{2, 23}
+
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d", v.Major, v.Minor)
+}
+
+func (v Version) Before(other Version) bool {
+	return v.Major < other.Major || (v.Major == other.Major && v.Minor < other.Minor)
+}
+
+// Parse the output of git version, such as "git version 2.39.5" or
+// "git version 2.39.3 (Apple Git-145)" or "git version 2.45.1.windows.1"
+func ParseVersion(output string) (Version, error) {
+	var v Version
+	fields := strings.Fields(output)
+	if len(fields) < 3 || fields[0] != "git" || fields[1] != "version" {
+		return v, fmt.Errorf("unexpected output of git version: %q", strings.TrimSpace(output))
+	}
+	if _, err := fmt.Sscanf(fields[2], "%d.%d", &v.Major, &v.Minor); err != nil {
+		return v, fmt.Errorf("unexpected output of git version: %q", strings.TrimSpace(output))
+	}
+	return v, nil
+}
+
+// The version of the git in PATH
+func Installed() (Version, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return Version{}, ErrNotInstalled
+	}
+	output, err := Command("", "version").Output()
+	if err != nil {
+		return Version{}, err
+	}
+	return ParseVersion(string(output))
+}
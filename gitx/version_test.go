@@ -0,0 +1,23 @@
+package gitx
+
+import "testing"
+
+func TestParseVersion(t *testing.T) {
+	for output, want := range map[string]Version{
+		"git version 2.39.5\n":                 {2, 39},
+		"git version 2.39.3 (Apple Git-145)\n": {2, 39},
+		"git version 2.45.1.windows.1\n":       {2, 45},
+		"git version 2.22.0\n":                 {2, 22},
+	} {
+		got, err := ParseVersion(output)
+		if err != nil || got != want {
+			t.Errorf("ParseVersion(%q) = %v, %v, want %v", output, got, err, want)
+		}
+	}
+	if _, err := ParseVersion("hub version 2.14.2"); err == nil {
+		t.Error("parsed a version that isn't git's")
+	}
+	if !(Version{2, 22}).Before(MinVersion) || (Version{3, 0}).Before(MinVersion) {
+		t.Errorf("wrong order around %v", MinVersion)
+	}
+}
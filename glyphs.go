@@ -0,0 +1,166 @@
+package main
+
+import (
+	"errors"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+type glyphConfig struct {
+	Cursor          string `toml:"cursor"`
+	Staged          string `toml:"staged"`
+	PartiallyStaged string `toml:"partially_staged"`
+	Unstaged        string `toml:"unstaged"`
+	Conflicted      string `toml:"conflicted"`
+	Bookmark        string `toml:"bookmark"`
+	// File type icons shown before each path, "none" or "nerd"
+	Icons string `toml:"icons"`
+	// Icons by file name or extension, over those of the icon set
+	FileIcons map[string]string `toml:"file_icons"`
+}
+
+func defaultGlyphs() glyphConfig {
+	return glyphConfig{
+		Cursor:          "> ",
+		Staged:          "[✓]",
+		PartiallyStaged: "[~]",
+		Unstaged:        "[ ]",
+		Conflicted:      "[!]",
+		Bookmark:        "★",
+		Icons:           "none",
+	}
+}
+
+// Icons of a file type set, looked up by file name, then by extension
+type iconSet struct {
+	names      map[string]string
+	extensions map[string]string
+	file       string
+	dir        string
+	symlink    string
+}
+
+// Code points of Nerd Fonts (https://www.nerdfonts.com)
+var iconSets = map[string]iconSet{
+	"none": {},
+	"nerd": {
+		names: map[string]string{
+			"Makefile":       "",
+			"Dockerfile":     "",
+			"go.mod":         "",
+			"go.sum":         "",
+			"Cargo.toml":     "",
+			".gitignore":     "",
+			".gitmodules":    "",
+			".gitattributes": "",
+			"LICENSE":        "",
+		},
+		extensions: map[string]string{
+			".go":   "",
+			".rs":   "",
+			".py":   "",
+			".js":   "",
+			".ts":   "",
+			".c":    "",
+			".h":    "",
+			".cpp":  "",
+			".java": "",
+			".rb":   "",
+			".lua":  "",
+			".sh":   "",
+			".html": "",
+			".css":  "",
+			".md":   "",
+			".json": "",
+			".toml": "",
+			".yaml": "",
+			".yml":  "",
+			".lock": "",
+			".png":  "",
+			".jpg":  "",
+			".svg":  "",
+		},
+		file:    "",
+		dir:     "",
+		symlink: "",
+	},
+}
+
+func iconSetNames() []string {
+	var names []string
+	for name := range iconSets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func checkGlyphs(g glyphConfig) error {
+	if _, ok := iconSets[g.Icons]; !ok {
+		return errors.New(tr("Unknown icon set %q, available icon sets: %v", g.Icons, iconSetNames()))
+	}
+	return nil
+}
+
+// The icon of a file, empty without an icon set
+func fileIcon(f fileEntry) string {
+	name := path.Base(f.pathFromGitRoot)
+	ext := path.Ext(name)
+	if icon, ok := cfg.Glyphs.FileIcons[name]; ok {
+		return icon
+	}
+	if icon, ok := cfg.Glyphs.FileIcons[ext]; ok && ext != "" {
+		return icon
+	}
+
+	set := iconSets[cfg.Glyphs.Icons]
+	switch {
+	case f.symlink:
+		return set.symlink
+	case strings.HasSuffix(f.pathFromGitRoot, "/"):
+		return set.dir
+	}
+	if icon, ok := set.names[name]; ok {
+		return icon
+	}
+	if icon, ok := set.extensions[ext]; ok {
+		return icon
+	}
+	return set.file
+}
+
+func (g glyphConfig) checkbox(status stagingStatus) string {
+	switch status {
+	case staged:
+		return stagedStyle.Render(g.Staged)
+	case partiallyStaged:
+		return partiallyStagedStyle.Render(g.PartiallyStaged)
+	case conflicted:
+		return conflictStyle.Render(g.Conflicted)
+	case committed:
+		return separatorStyle.Render(g.Staged)
+	default:
+		return unstagedStyle.Render(g.Unstaged)
+	}
+}
+
+// The cursor column of a row, blank but as wide as the cursor elsewhere
+func (g glyphConfig) cursor(selected bool) string {
+	if selected {
+		return cursorStyle.Render(g.Cursor)
+	}
+	return strings.Repeat(" ", ansi.StringWidth(g.Cursor))
+}
+
+// Pad glyphs of different widths to line up the columns after them
+func padGlyph(glyph string, width int) string {
+	return glyph + strings.Repeat(" ", max(width-ansi.StringWidth(glyph), 0))
+}
+
+func (g glyphConfig) checkboxWidth() int {
+	return max(ansi.StringWidth(g.Staged), ansi.StringWidth(g.PartiallyStaged),
+		ansi.StringWidth(g.Unstaged), ansi.StringWidth(g.Conflicted))
+}
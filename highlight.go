@@ -0,0 +1,179 @@
+package main
+
+import (
+	"path"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// How the tokens of a language look, enough to tell code, comments and
+// strings apart in a preview
+type syntax struct {
+	lineComment []string
+	blockStart  string // of a comment, empty without block comments
+	blockEnd    string
+	quotes      string
+	keywords    map[string]bool
+	ignoreCase  bool // of keywords
+}
+
+func keywords(words string) map[string]bool {
+	set := make(map[string]bool)
+	for _, w := range strings.Fields(words) {
+		set[w] = true
+	}
+	return set
+}
+
+var (
+	goSyntax = &syntax{lineComment: []string{"//"}, blockStart: "/*", blockEnd: "*/", quotes: "\"'`",
+		keywords: keywords(`break case chan const continue default defer else fallthrough for func go goto
+		if import interface map package range return select struct switch type var nil true false iota`)}
+	cSyntax = &syntax{lineComment: []string{"//"}, blockStart: "/*", blockEnd: "*/", quotes: "\"'",
+		keywords: keywords(`auto break case char class const continue default delete do double else enum
+		extern false final float for fn if impl import int let long match mod mut namespace new null
+		private protected pub public return self short signed sizeof static struct super switch template
+		this throw trait true try typedef union unsigned use using virtual void volatile while`)}
+	jsSyntax = &syntax{lineComment: []string{"//"}, blockStart: "/*", blockEnd: "*/", quotes: "\"'`",
+		keywords: keywords(`async await break case catch class const continue default delete do else
+		export extends false finally for from function if import in instanceof interface let new null
+		return static super switch this throw true try type typeof undefined var void while yield`)}
+	pythonSyntax = &syntax{lineComment: []string{"#"}, quotes: "\"'",
+		keywords: keywords(`and as assert async await break class continue def del elif else except
+		False finally for from global if import in is lambda None nonlocal not or pass raise return True
+		try while with yield`)}
+	shellSyntax = &syntax{lineComment: []string{"#"}, quotes: "\"'",
+		keywords: keywords(`case do done elif else esac exit export fi for function if in local return
+		then until while`)}
+	configSyntax = &syntax{lineComment: []string{"#"}, quotes: "\"'", keywords: keywords(`true false null`)}
+	// Keywords are lowercase, SQL is matched whatever its case
+	sqlSyntax = &syntax{lineComment: []string{"--"}, blockStart: "/*", blockEnd: "*/", quotes: "'\"", ignoreCase: true,
+		keywords: keywords(`select from where insert into update delete create table drop alter join left
+		right inner outer on and or not null as order by group having limit values set primary key`)}
+)
+
+// Syntaxes by file extension
+var syntaxes = map[string]*syntax{
+	".go":    goSyntax,
+	".c":     cSyntax,
+	".h":     cSyntax,
+	".cc":    cSyntax,
+	".cpp":   cSyntax,
+	".hpp":   cSyntax,
+	".java":  cSyntax,
+	".kt":    cSyntax,
+	".cs":    cSyntax,
+	".rs":    cSyntax,
+	".swift": cSyntax,
+	".js":    jsSyntax,
+	".jsx":   jsSyntax,
+	".ts":    jsSyntax,
+	".tsx":   jsSyntax,
+	".mjs":   jsSyntax,
+	".py":    pythonSyntax,
+	".rb":    shellSyntax,
+	".sh":    shellSyntax,
+	".bash":  shellSyntax,
+	".zsh":   shellSyntax,
+	".toml":  configSyntax,
+	".yaml":  configSyntax,
+	".yml":   configSyntax,
+	".sql":   sqlSyntax,
+}
+
+// Color the lines of a file by its syntax, known by its extension, the
+// lines of other files as they are
+func highlightLines(name string, lines []string) []string {
+	s, ok := syntaxes[path.Ext(name)]
+	if !ok {
+		return lines
+	}
+	var result []string
+	inComment := false
+	for _, line := range lines {
+		var highlighted string
+		highlighted, inComment = s.highlight(line, inComment)
+		result = append(result, highlighted)
+	}
+	return result
+}
+
+// Color a line, the one before having left a block comment open or not, and
+// whether this one does
+func (s *syntax) highlight(line string, inComment bool) (string, bool) {
+	var b strings.Builder
+	runes := []rune(line)
+	emit := func(style lipgloss.Style, from, to int) {
+		b.WriteString(style.Render(string(runes[from:to])))
+	}
+	for i := 0; i < len(runes); {
+		rest := string(runes[i:])
+		switch {
+		case inComment, s.blockStart != "" && strings.HasPrefix(rest, s.blockStart):
+			from := 0
+			if !inComment {
+				from = len(s.blockStart)
+			}
+			end := strings.Index(rest[from:], s.blockEnd)
+			if end < 0 {
+				emit(syntaxCommentStyle, i, len(runes))
+				return b.String(), true
+			}
+			n := len([]rune(rest[:from+end+len(s.blockEnd)]))
+			emit(syntaxCommentStyle, i, i+n)
+			i += n
+			inComment = false
+		case s.startsLineComment(rest):
+			emit(syntaxCommentStyle, i, len(runes))
+			return b.String(), false
+		case strings.ContainsRune(s.quotes, runes[i]):
+			end := i + 1
+			for end < len(runes) && runes[end] != runes[i] {
+				if runes[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			end = min(end+1, len(runes))
+			emit(syntaxStringStyle, i, end)
+			i = end
+		case unicode.IsDigit(runes[i]):
+			end := i
+			for end < len(runes) && (unicode.IsDigit(runes[end]) || unicode.IsLetter(runes[end]) || runes[end] == '.' || runes[end] == '_') {
+				end++
+			}
+			emit(syntaxNumberStyle, i, end)
+			i = end
+		case unicode.IsLetter(runes[i]) || runes[i] == '_':
+			end := i
+			for end < len(runes) && (unicode.IsLetter(runes[end]) || unicode.IsDigit(runes[end]) || runes[end] == '_') {
+				end++
+			}
+			word := string(runes[i:end])
+			if s.ignoreCase {
+				word = strings.ToLower(word)
+			}
+			if s.keywords[word] {
+				emit(syntaxKeywordStyle, i, end)
+			} else {
+				b.WriteString(string(runes[i:end]))
+			}
+			i = end
+		default:
+			b.WriteRune(runes[i])
+			i++
+		}
+	}
+	return b.String(), inComment
+}
+
+func (s *syntax) startsLineComment(rest string) bool {
+	for _, prefix := range s.lineComment {
+		if strings.HasPrefix(rest, prefix) {
+			return true
+		}
+	}
+	return false
+}
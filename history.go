@@ -0,0 +1,135 @@
+package main
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/hzqtc/git-istage/gitx"
+)
+
+// A commit in the history of a file
+type historyCommit struct {
+	hash    string
+	date    string
+	subject string
+	path    string // of the file as of the commit, it may have been renamed since
+}
+
+// The commits of a file shown in historyMode, the diff pane showing what the
+// one under the cursor changed
+type fileHistory struct {
+	path    string
+	commits []historyCommit
+	cursor  int
+}
+
+// Commits that changed a file, newest first, following it through renames
+func getFileHistory(r repo, pathFromGitRoot string) []historyCommit {
+	output, err := r.atRoot().git("log", "--follow", "--date=short", "--format=%x00%h%x00%ad%x00%s",
+		"--name-only", "--", pathFromGitRoot).Output()
+	if err != nil {
+		return nil
+	}
+	var commits []historyCommit
+	// Each commit is "\x00hash\x00date\x00subject\n\npath\n"
+	fields := strings.Split(string(output), "\x00")
+	for i := 1; i+2 < len(fields); i += 3 {
+		subject, path, _ := strings.Cut(fields[i+2], "\n")
+		path = gitx.UnquotePath(strings.TrimSpace(path))
+		if path == "" {
+			path = pathFromGitRoot
+		}
+		commits = append(commits, historyCommit{fields[i], fields[i+1], subject, path})
+	}
+	return commits
+}
+
+func (m *model) openHistory(index int) {
+	f := m.files[index]
+	commits := getFileHistory(m.repo, f.pathFromGitRoot)
+	if len(commits) == 0 {
+		m.status = tr("No commits change %s", f.pathFromGitRoot)
+		return
+	}
+	m.history = &fileHistory{path: f.pathFromGitRoot, commits: commits}
+	m.mode = historyMode
+	m.loadHistoryDiff()
+}
+
+// Load the diff of the file in the commit under the cursor into the diff pane
+func (m *model) loadHistoryDiff() {
+	m.pane.YOffset = 0
+	m.diffLines = nil
+	m.diffMore = 0
+	m.lineSelect = nil
+	m.hunk = -1
+	m.diffID++
+	m.diffLoading = true
+	h := m.history
+	r, c, id := m.repo, h.commits[h.cursor], m.diffID
+	args := []string{"show", "--format=", "--textconv", "--ext-diff", "--no-color", "-M", c.hash, "--", c.path}
+	if h.cursor+1 < len(h.commits) && h.commits[h.cursor+1].path != c.path {
+		// Renamed by this commit, shown as a rename given both names
+		args = append(args, h.commits[h.cursor+1].path)
+	}
+	limit := m.diffLimit(c.hash + ":" + c.path)
+	m.queue(func() tea.Msg {
+		_, generation := gitContext(r.root)
+		output, more, _ := r.atRoot().git(args...).OutputLines(limit)
+		f := fileEntry{pathFromGitRoot: c.path, pathFromCwd: r.relPath(c.path)}
+		lines := parseDiff(presentDiff(r, f, output))
+		return diffLoadedMsg{r.root, id, lines, more, gitCancelledSince(r.root, generation), nil, nil}
+	})
+}
+
+func (m *model) updateHistory(key string) {
+	h := m.history
+	switch {
+	case m.keys.up.matches(key) && h.cursor > 0:
+		h.cursor--
+		m.loadHistoryDiff()
+	case m.keys.down.matches(key) && h.cursor < len(h.commits)-1:
+		h.cursor++
+		m.loadHistoryDiff()
+	case m.keys.pageUp.matches(key):
+		m.scrollDiff(-m.bodyHeight())
+	case m.keys.pageDown.matches(key):
+		m.scrollDiff(m.bodyHeight())
+	case m.keys.nextHunk.matches(key):
+		m.jumpToHunk(1)
+	case m.keys.prevHunk.matches(key):
+		m.jumpToHunk(-1)
+	case m.keys.loadFullDiff.matches(key):
+		m.loadFullDiff()
+	case m.keys.focusList.matches(key), m.keys.history.matches(key):
+		m.history = nil
+		m.mode = listMode
+		m.loadDiff()
+	}
+}
+
+func (m model) historyListView(width, height int) string {
+	h := m.history
+	rows := []string{sectionStyle.Render(ansi.Truncate(tr("History of %s", h.path), width, "…"))}
+	offset := max(h.cursor+1-(height-1), 0)
+	for i := offset; i < len(h.commits) && len(rows) < height; i++ {
+		c := h.commits[i]
+		row := cfg.Glyphs.cursor(i == h.cursor) + cursorStyle.Render(c.hash) + " " + badgeStyle.Render(c.date) + " " + c.subject
+		if c.path != h.path {
+			row += " " + badgeStyle.Render(tr("as %s", c.path))
+		}
+		rows = append(rows, ansi.Truncate(row, width, "…"))
+	}
+	return strings.Join(rows, "\n")
+}
+
+func (m model) historyView(width, height int) string {
+	if m.fullScreenDiff {
+		return m.diffView(width, height)
+	}
+	if width < minSplitWidth {
+		return m.historyListView(width, height)
+	}
+	return m.sideBySide(width, height, width/2, m.historyListView)
+}
@@ -0,0 +1,592 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// A hunk of a patch: the numbers of its @@ header and the lines after it
+type hunk struct {
+	oldStart, oldCount int
+	newStart, newCount int
+	section            string // text after the closing @@, usually the enclosing function
+	lines              []string
+}
+
+// The patch of a single file as git diff prints it
+type filePatch struct {
+	header []string // from "diff --git" up to the first hunk
+	hunks  []hunk
+}
+
+func parsePatch(lines []string) (filePatch, error) {
+	var p filePatch
+	for _, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "@@ "):
+			var h hunk
+			if err := parseHunkHeader(line, &h); err != nil {
+				return p, err
+			}
+			p.hunks = append(p.hunks, h)
+		case len(p.hunks) > 0:
+			h := &p.hunks[len(p.hunks)-1]
+			h.lines = append(h.lines, line)
+		default:
+			p.header = append(p.header, line)
+		}
+	}
+	return p, nil
+}
+
+// Parse "@@ -1,5 +1,6 @@ func main() {", where a missing count means 1
+func parseHunkHeader(line string, h *hunk) error {
+	fields := strings.SplitN(line, " ", 5)
+	if len(fields) < 4 || fields[3] != "@@" {
+		return fmt.Errorf("malformed hunk header %q", line)
+	}
+	var err error
+	if h.oldStart, h.oldCount, err = parseRange(fields[1], "-"); err != nil {
+		return err
+	}
+	if h.newStart, h.newCount, err = parseRange(fields[2], "+"); err != nil {
+		return err
+	}
+	if len(fields) == 5 {
+		h.section = fields[4]
+	}
+	return nil
+}
+
+func parseRange(s, prefix string) (int, int, error) {
+	start, count := 0, 1
+	s, ok := strings.CutPrefix(s, prefix)
+	if !ok {
+		return 0, 0, fmt.Errorf("malformed hunk range %q", s)
+	}
+	var err error
+	if before, after, found := strings.Cut(s, ","); found {
+		_, err = fmt.Sscanf(before+" "+after, "%d %d", &start, &count)
+	} else {
+		_, err = fmt.Sscanf(s, "%d", &start)
+	}
+	return start, count, err
+}
+
+func (p filePatch) hasHeader(prefix string) bool {
+	for _, line := range p.header {
+		if strings.HasPrefix(line, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Path of the file from the git root, from the ---/+++ lines
+func (p filePatch) path() string {
+	for _, line := range p.header {
+		if path, ok := strings.CutPrefix(line, "+++ b/"); ok {
+			return path
+		}
+	}
+	for _, line := range p.header {
+		if path, ok := strings.CutPrefix(line, "--- a/"); ok {
+			return path
+		}
+	}
+	return ""
+}
+
+// Header of a partial patch, which leaves the file in place on both sides:
+// the file isn't created or deleted, so its mode and blob lines don't apply
+func (p filePatch) modifyingHeader() []string {
+	path := p.path()
+	var header []string
+	for _, line := range p.header {
+		switch {
+		case strings.HasPrefix(line, "new file mode "), strings.HasPrefix(line, "deleted file mode "),
+			strings.HasPrefix(line, "index "):
+			continue
+		case strings.HasPrefix(line, "--- "):
+			line = "--- a/" + path
+		case strings.HasPrefix(line, "+++ "):
+			line = "+++ b/" + path
+		}
+		header = append(header, line)
+	}
+	return header
+}
+
+// How a patch built from a hunk has to be applied to the index
+type hunkPatch struct {
+	patch   string
+	reverse bool
+}
+
+// Build the patch that stages the chosen lines of a hunk of the unstaged
+// diff, or unstages them from the staged diff when reverse is set. Changed
+// lines that aren't chosen stay as they are on the side the patch applies
+// to: a removal not being staged becomes context and an addition is left
+// out, the other way round when unstaging. intentToAdd tells that a new
+// file is already in the index, added with `git add -N`.
+func (p filePatch) partial(index int, chosen func(line int) bool, reverse, intentToAdd bool) (hunkPatch, error) {
+	if index < 0 || index >= len(p.hunks) {
+		return hunkPatch{}, errors.New(tr("No hunk to stage here"))
+	}
+	h := p.hunks[index]
+	all, some := true, false
+	for i, line := range h.lines {
+		if strings.HasPrefix(line, "+") || strings.HasPrefix(line, "-") {
+			all = all && chosen(i)
+			some = some || chosen(i)
+		}
+	}
+	if !some {
+		return hunkPatch{}, errors.New(tr("No changed lines selected"))
+	}
+	newFile := p.hasHeader("new file mode ")
+	deletedFile := p.hasHeader("deleted file mode ")
+
+	// A file deleted in the index isn't there to unstage lines into, so
+	// restoring some of its lines adds it back with just those
+	if reverse && deletedFile && !all {
+		return p.restoreLines(h, chosen), nil
+	}
+
+	header := p.header
+	switch {
+	case newFile && intentToAdd && !reverse:
+		// The index already has an empty entry for the file
+		header = p.modifyingHeader()
+	case newFile && !all && reverse, deletedFile && !all && !reverse:
+		// The file stays in the index with the lines not chosen
+		header = p.modifyingHeader()
+	}
+
+	keep, drop := "-", "+"
+	if reverse {
+		keep, drop = "+", "-"
+	}
+	var lines []string
+	oldCount, newCount := 0, 0
+	dropped := false
+	for i, line := range h.lines {
+		switch {
+		case strings.HasPrefix(line, `\`):
+			// "\ No newline at end of file" belongs to the line before it
+			if !dropped {
+				lines = append(lines, line)
+			}
+			continue
+		case (strings.HasPrefix(line, "+") || strings.HasPrefix(line, "-")) && chosen(i):
+			lines = append(lines, line)
+		case strings.HasPrefix(line, drop):
+			dropped = true
+			continue
+		case strings.HasPrefix(line, keep):
+			lines = append(lines, " "+line[1:])
+		default:
+			lines = append(lines, line)
+		}
+		dropped = false
+		switch line := lines[len(lines)-1]; {
+		case strings.HasPrefix(line, "-"):
+			oldCount++
+		case strings.HasPrefix(line, "+"):
+			newCount++
+		default:
+			oldCount++
+			newCount++
+		}
+	}
+
+	// Only the side the patch applies to keeps its lines, a range of the
+	// other one that ends up empty names the line before it
+	adjust := func(start, before, after int) int {
+		switch {
+		case before == 0 && after > 0:
+			return start + 1
+		case before > 0 && after == 0:
+			return start - 1
+		}
+		return start
+	}
+	oldStart := adjust(h.oldStart, h.oldCount, oldCount)
+	newStart := adjust(h.newStart, h.newCount, newCount)
+	hunkHeader := fmt.Sprintf("@@ -%d,%d +%d,%d @@", oldStart, oldCount, newStart, newCount)
+	if h.section != "" {
+		hunkHeader += " " + h.section
+	}
+	patch := append(append(append([]string(nil), header...), hunkHeader), endNewlines(lines)...)
+	return hunkPatch{strings.Join(patch, "\n") + "\n", reverse}, nil
+}
+
+// "\ No newline at end of file" only holds for the last line of a side.
+// Lines left out of a partial patch can put others after such a line, which
+// then ends with a newline on the side that goes on.
+func endNewlines(lines []string) []string {
+	var result []string
+	for i, line := range lines {
+		if !strings.HasPrefix(line, `\`) || len(result) == 0 {
+			result = append(result, line)
+			continue
+		}
+		oldAfter, newAfter := false, false
+		for _, l := range lines[i+1:] {
+			oldAfter = oldAfter || strings.HasPrefix(l, " ") || strings.HasPrefix(l, "-")
+			newAfter = newAfter || strings.HasPrefix(l, " ") || strings.HasPrefix(l, "+")
+		}
+		last := result[len(result)-1]
+		context := strings.HasPrefix(last, " ")
+		switch {
+		case context && oldAfter && newAfter, strings.HasPrefix(last, "-") && oldAfter, strings.HasPrefix(last, "+") && newAfter:
+			// A newline on every side the line is on
+		case context && newAfter:
+			result = append(result[:len(result)-1], "-"+last[1:], line, "+"+last[1:])
+		case context && oldAfter:
+			result = append(result[:len(result)-1], "-"+last[1:], "+"+last[1:], line)
+		default:
+			result = append(result, line)
+		}
+	}
+	return result
+}
+
+// A patch adding the file back to the index with only the chosen lines of
+// its deletion
+func (p filePatch) restoreLines(h hunk, chosen func(line int) bool) hunkPatch {
+	mode := "100644"
+	for _, line := range p.header {
+		if m, ok := strings.CutPrefix(line, "deleted file mode "); ok {
+			mode = m
+		}
+	}
+	path := p.path()
+	var lines []string
+	added, dropped := 0, false
+	for i, line := range h.lines {
+		switch {
+		case strings.HasPrefix(line, `\`):
+			if !dropped {
+				lines = append(lines, line)
+			}
+		case strings.HasPrefix(line, "-") && chosen(i):
+			lines = append(lines, "+"+line[1:])
+			added++
+			dropped = false
+		default:
+			dropped = true
+		}
+	}
+	patch := []string{
+		"diff --git a/" + path + " b/" + path,
+		"new file mode " + mode,
+		"--- /dev/null",
+		"+++ b/" + path,
+		fmt.Sprintf("@@ -0,0 +1,%d @@", added),
+	}
+	return hunkPatch{strings.Join(append(patch, lines...), "\n") + "\n", false}
+}
+
+// The patch git would apply for a file, without textconv or external diff
+// drivers, run from the root so paths match the index
+func rawPatch(r repo, f fileEntry, cached bool) ([]string, error) {
+	args := []string{"diff", "--no-color", "--no-ext-diff", "--no-textconv", "--no-relative",
+		"--src-prefix=a/", "--dst-prefix=b/"}
+	if r.functionContext {
+		// The same hunks as the diff pane
+		args = append(args, "--function-context")
+	}
+	switch {
+	case cached:
+		args = append(args, "--cached", "--", f.pathFromGitRoot)
+	case f.untracked:
+		args = append(args, "--no-index", "--", "/dev/null", f.pathFromGitRoot)
+	default:
+		args = append(args, "--", f.pathFromGitRoot)
+	}
+	output, err := r.atRoot().git(args...).Output()
+	// --no-index exits with 1 when the files differ
+	if err != nil && len(output) == 0 {
+		return nil, err
+	}
+	return splitDiffLines(string(output)), nil
+}
+
+func applyPatch(r repo, p hunkPatch) error {
+	args := []string{"apply", "--cached", "--whitespace=nowarn"}
+	if p.reverse {
+		args = append(args, "--reverse")
+	}
+	return r.atRoot().runInput(p.patch, append(args, "-")...)
+}
+
+// Lines of a hunk in the diff pane picked to stage or unstage, as indices
+// into diffLines. The cursor line alone is picked until the range is
+// anchored, then the lines from the anchor to the cursor.
+type lineSelection struct {
+	hunk     int // the hunk header
+	anchor   int
+	cursor   int
+	anchored bool
+}
+
+func isHunkHeader(l diffLine) bool {
+	return l.columns == 0 && l.preview == "" && strings.HasPrefix(l.text, "@@")
+}
+
+// Index of the header of the hunk the hunk keys act on, -1 without hunks:
+// the one jumped to while it's on screen, else the first one on screen or
+// the one running through it
+func (m model) currentHunk() int {
+	if m.lineSelect != nil {
+		return m.lineSelect.hunk
+	}
+	top, bottom := m.pane.YOffset, m.pane.YOffset+m.bodyHeight()
+	if h := m.hunk; h >= 0 && h < len(m.diffLines) && h < bottom && m.hunkEnd(h) > top {
+		return h
+	}
+	for i := top; i < min(bottom, len(m.diffLines)); i++ {
+		if isHunkHeader(m.diffLines[i]) {
+			return i
+		}
+	}
+	for i := min(top, len(m.diffLines)) - 1; i >= 0; i-- {
+		if isHunkHeader(m.diffLines[i]) {
+			if m.hunkEnd(i) > top {
+				return i
+			}
+			break
+		}
+	}
+	return -1
+}
+
+// Index after the last line of the hunk with the header at index h
+func (m model) hunkEnd(h int) int {
+	end := h + 1
+	for end < len(m.diffLines) && m.diffLines[end].columns > 0 {
+		end++
+	}
+	return end
+}
+
+func (m *model) startLineSelection() {
+	h := m.currentHunk()
+	if h < 0 {
+		m.status = tr("No hunk to stage here")
+		return
+	}
+	m.unfold(h, m.hunkEnd(h))
+	first := h + 1
+	for i := h + 1; i < m.hunkEnd(h); i++ {
+		if text := m.diffLines[i].text; strings.HasPrefix(text, "+") || strings.HasPrefix(text, "-") {
+			first = i
+			break
+		}
+	}
+	m.lineSelect = &lineSelection{hunk: h, anchor: first, cursor: first, anchored: true}
+	m.showSelection()
+}
+
+func (m *model) updateLineSelection(key string) {
+	s := m.lineSelect
+	switch {
+	case m.keys.scrollUp.matches(key):
+		s.cursor = max(s.cursor-1, s.hunk+1)
+	case m.keys.scrollDown.matches(key):
+		s.cursor = min(s.cursor+1, m.hunkEnd(s.hunk)-1)
+	case m.keys.selectLines.matches(key):
+		s.anchored = !s.anchored
+	case m.keys.toggleHunk.matches(key):
+		m.applyHunk(s.hunk, min(s.anchor, s.cursor), max(s.anchor, s.cursor))
+		return
+	case m.keys.cancel.matches(key):
+		m.lineSelect = nil
+		return
+	}
+	if !s.anchored {
+		s.anchor = s.cursor
+	}
+	m.showSelection()
+}
+
+// Scroll to keep the cursor of the line selection on screen
+func (m *model) showSelection() {
+	cursor := m.lineSelect.cursor
+	if cursor < m.pane.YOffset {
+		m.pane.SetYOffset(cursor)
+	} else if height := m.bodyHeight(); cursor >= m.pane.YOffset+height {
+		m.pane.SetYOffset(cursor - height + 1)
+	}
+}
+
+func (m model) selectedLine(i int) bool {
+	s := m.lineSelect
+	return s != nil && i >= min(s.anchor, s.cursor) && i <= max(s.anchor, s.cursor)
+}
+
+// Stage the changed lines from first to last of the hunk with the header at
+// index h, or unstage them if the diff shows staged changes
+func (m *model) applyHunk(h, first, last int) {
+	row, ok := m.selectedRow()
+	if !ok || row.file < 0 || h < 0 {
+		m.status = tr("No hunk to stage here")
+		return
+	}
+	f := m.files[row.file]
+	switch {
+	case m.review != nil:
+		// The diff is against the merge base rather than the index
+		m.status = tr("Leave the branch review with %s to stage hunks", m.keys.review.Help().Key)
+		return
+	case f.status == conflicted:
+		m.status = tr("Resolve the conflict before staging parts of %s", f.pathFromCwd)
+		return
+	case f.modeChange.typeChange() != "":
+		// The diff deletes the old file and adds the new one, neither of which
+		// applies to the index alone
+		m.status = tr("Type changes can only be staged as a whole")
+		return
+	case f.symlink:
+		m.status = tr("Symlinks can only be staged as a whole")
+		return
+	case m.repo.ignoreCR:
+		// The hunks shown would leave out the line endings of the ones staged
+		m.status = tr("Show the line endings again with %s to stage hunks", m.keys.ignoreCR.Help().Key)
+		return
+	case len(m.diffLines) > 0 && strings.HasPrefix(m.diffLines[0].text, "diff driver: "):
+		// The hunks shown aren't the ones of the file's content
+		m.status = tr("Files with a diff driver can only be staged as a whole")
+		return
+	case m.diffMore > 0 && m.hunkEnd(h) == len(m.diffLines):
+		m.status = tr("The hunk is cut off, press %s to load the whole diff first", m.keys.loadFullDiff.Help().Key)
+		return
+	}
+
+	// Which hunk of its file diff in the pane the hunk is
+	index := 0
+	for i := 0; i < h; i++ {
+		switch {
+		case strings.HasPrefix(m.diffLines[i].text, "diff "):
+			index = 0
+		case isHunkHeader(m.diffLines[i]):
+			index++
+		}
+	}
+	cached := hunkInIndex(m.diffLines, h, f, m.diffSection(row))
+
+	lines, err := rawPatch(m.repo, f, cached)
+	if err != nil {
+		m.status = tr("Failed to stage the hunk: %v", err)
+		return
+	}
+	p, err := parsePatch(lines)
+	if err != nil {
+		m.status = tr("Failed to stage the hunk: %v", err)
+		return
+	}
+	end := m.hunkEnd(h)
+	if index >= len(p.hunks) || len(p.hunks[index].lines) != end-h-1 {
+		m.status = tr("The diff changed, try again")
+		m.loadDiff()
+		return
+	}
+	chosen := func(line int) bool {
+		return h+1+line >= first && h+1+line <= last
+	}
+	patch, err := p.partial(index, chosen, cached, f.xy == " A")
+	if err != nil {
+		m.status = tr("Failed to stage the hunk: %v", err)
+		return
+	}
+	if !patch.reverse {
+		if secrets := findSecretsInDiff(compileSecretPatterns(), []byte(patch.patch)); len(secrets) > 0 {
+			m.askAboutSecrets(secrets, func(m *model) {
+				m.applyHunkPatch(patch)
+			})
+			return
+		}
+	}
+	m.applyHunkPatch(patch)
+}
+
+func (m *model) applyHunkPatch(patch hunkPatch) {
+	if err := applyPatch(m.repo, patch); err != nil {
+		if !m.handleIndexLock(err) {
+			m.status = tr("Failed to stage the hunk: %v", err)
+		}
+		return
+	}
+	offset := m.pane.YOffset
+	next := m.nextHunkToApply()
+	m.reload()
+	m.loadDiff()
+	// Stay around the next hunk, which moved up into the place of this one
+	m.pane.YOffset = offset
+	if cfg.Diff.AdvanceHunks {
+		m.advance = next
+	}
+}
+
+// The hunk to scroll to once the diff of the file at path loads, the
+// index-th of those on its side: the one to go on with after staging or
+// unstaging one, or one a search of all the changes found
+type hunkAdvance struct {
+	path   string
+	cached bool
+	index  int
+}
+
+func (m model) nextHunkToApply() *hunkAdvance {
+	row, ok := m.selectedRow()
+	h := m.currentHunk()
+	if !ok || row.file < 0 || h < 0 {
+		return nil
+	}
+	f, s := m.files[row.file], m.diffSection(row)
+	next := &hunkAdvance{path: f.pathFromGitRoot, cached: hunkInIndex(m.diffLines, h, f, s)}
+	for i := 0; i < h; i++ {
+		if isHunkHeader(m.diffLines[i]) && hunkInIndex(m.diffLines, i, f, s) == next.cached {
+			next.index++
+		}
+	}
+	return next
+}
+
+// Scroll to the hunk a staged or unstaged one left to do next, the last on
+// its side when it was the last one. A file that left the selection, staged
+// or unstaged as a whole, leaves the diff at its top.
+func (m *model) advanceToHunk(a hunkAdvance) {
+	row, ok := m.selectedRow()
+	if !ok || row.file < 0 || m.files[row.file].pathFromGitRoot != a.path {
+		return
+	}
+	f, s := m.files[row.file], m.diffSection(row)
+	last := -1
+	for i, l := range m.diffLines {
+		if !isHunkHeader(l) || hunkInIndex(m.diffLines, i, f, s) != a.cached {
+			continue
+		}
+		if a.index == 0 {
+			m.showHunk(i)
+			return
+		}
+		a.index--
+		last = i
+	}
+	if last >= 0 {
+		m.showHunk(last)
+	}
+}
+
+// Stage or unstage the hunk at the top of the diff pane
+func (m *model) toggleHunk() {
+	h := m.currentHunk()
+	if h >= 0 {
+		// The patch is made of every line of the hunk
+		m.unfold(h, m.hunkEnd(h))
+	}
+	m.applyHunk(h, h+1, m.hunkEnd(h)-1)
+}
@@ -0,0 +1,109 @@
+package main
+
+import "strings"
+
+// Whether a hunk of a partially staged file is in the index, shown after its
+// header
+type hunkState int
+
+const (
+	hunkUnmarked hunkState = iota
+	hunkStaged
+	hunkUnstaged
+	// Staged with unstaged changes to its lines, or the other way round
+	hunkPartlyStaged
+)
+
+func (s hunkState) String() string {
+	switch s {
+	case hunkStaged:
+		return tr("staged")
+	case hunkUnstaged:
+		return tr("unstaged")
+	case hunkPartlyStaged:
+		return tr("partly staged")
+	default:
+		return ""
+	}
+}
+
+// Whether the hunk with the header at index h of the diff of f in section s
+// is a staged one, the flat list showing the staged diff of a partially
+// staged file before the unstaged one
+func hunkInIndex(lines []diffLine, h int, f fileEntry, s section) bool {
+	switch {
+	case s == stagedSection, s == noSection && f.status == staged:
+		return true
+	case s != noSection || f.status != partiallyStaged:
+		return false
+	}
+	block := -1
+	for _, l := range lines[:h] {
+		if strings.HasPrefix(l.text, "diff --git ") {
+			block++
+		}
+	}
+	return block == 0
+}
+
+// Lines of the index from first to last. Those of a hunk that only adds
+// lines are the two it adds them between.
+type lineRange struct {
+	first, last int
+}
+
+func (a lineRange) overlaps(b lineRange) bool {
+	return a.first <= b.last && b.first <= a.last
+}
+
+func indexRange(start, count int) lineRange {
+	if count == 0 {
+		return lineRange{start, start + 1}
+	}
+	return lineRange{start, start + count - 1}
+}
+
+// The lines of the index the hunks of a diff change, by the new side of the
+// staged diff or the old side of the unstaged one
+func changedIndexRanges(output []byte, staged bool) []lineRange {
+	var ranges []lineRange
+	for _, line := range splitDiffLines(string(output)) {
+		var h hunk
+		if !strings.HasPrefix(line, "@@ ") || parseHunkHeader(line, &h) != nil {
+			continue
+		}
+		if staged {
+			ranges = append(ranges, indexRange(h.newStart, h.newCount))
+		} else {
+			ranges = append(ranges, indexRange(h.oldStart, h.oldCount))
+		}
+	}
+	return ranges
+}
+
+// Mark the hunks of the diff of a partially staged file as staged or not,
+// and as partly staged where the staged and unstaged changes meet in the
+// index
+func markHunkStates(r repo, f fileEntry, s section, lines []diffLine) {
+	if f.status != partiallyStaged {
+		return
+	}
+	staged := changedIndexRanges(r.diff("-U0", "--cached", "--", f.pathFromCwd), true)
+	unstaged := changedIndexRanges(r.diff("-U0", "--", f.pathFromCwd), false)
+	for i, l := range lines {
+		var h hunk
+		if !isHunkHeader(l) || parseHunkHeader(l.text, &h) != nil {
+			continue
+		}
+		state, own, others := hunkUnstaged, indexRange(h.oldStart, h.oldCount), staged
+		if hunkInIndex(lines, i, f, s) {
+			state, own, others = hunkStaged, indexRange(h.newStart, h.newCount), unstaged
+		}
+		for _, other := range others {
+			if own.overlaps(other) {
+				state = hunkPartlyStaged
+			}
+		}
+		lines[i].state = state
+	}
+}
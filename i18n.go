@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Translations of user-facing strings, keyed by the English text which is
+// shown as is when a catalog has no translation for it
+type catalog map[string]string
+
+var catalogs = map[string]catalog{
+	"de": catalogDE,
+	"fr": catalogFR,
+}
+
+var activeCatalog catalog
+
+func languageNames() []string {
+	names := []string{"en"}
+	for name := range catalogs {
+		names = append(names, name)
+	}
+	sort.Strings(names[1:])
+	return names
+}
+
+// The language of the locale environment variables, e.g. "de" for LANG=de_DE.UTF-8
+func localeLanguage() string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if value := os.Getenv(name); value != "" {
+			lang, _, _ := strings.Cut(value, "_")
+			lang, _, _ = strings.Cut(lang, ".")
+			return strings.ToLower(lang)
+		}
+	}
+	return ""
+}
+
+// Pick the catalog for a language, or from the locale if lang is empty.
+// Unknown locale languages fall back to English, unknown configured ones are
+// an error.
+func setLanguage(lang string) error {
+	c, err := findCatalog(lang)
+	if err != nil {
+		return err
+	}
+	activeCatalog = c
+	return nil
+}
+
+// The catalog of a language, nil for English
+func findCatalog(lang string) (catalog, error) {
+	if lang == "" {
+		return catalogs[localeLanguage()], nil
+	}
+	if lang == "en" {
+		return nil, nil
+	}
+	c, ok := catalogs[lang]
+	if !ok {
+		return nil, fmt.Errorf(tr("Unknown language %q, available languages: %v"), lang, languageNames())
+	}
+	return c, nil
+}
+
+// Translate a message, formatting it with args like fmt.Sprintf if there are any
+func tr(msg string, args ...any) string {
+	if t, ok := activeCatalog[msg]; ok {
+		msg = t
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
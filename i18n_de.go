@@ -0,0 +1,479 @@
+package main
+
+var catalogDE = catalog{
+	// Footer hints
+	"quit":                "beenden",
+	"up":                  "hoch",
+	"down":                "runter",
+	"next column":         "nächste Spalte",
+	"previous column":     "vorige Spalte",
+	"go to file":          "zur Datei",
+	"next directory":      "nächstes Verzeichnis",
+	"previous directory":  "voriges Verzeichnis",
+	"toggle":              "umschalten",
+	"toggle all":          "alle umschalten",
+	"stage matching":      "Passende vormerken",
+	"toggle and next":     "umschalten und weiter",
+	"toggle and previous": "umschalten und zurück",
+	"view diff":           "Diff ansehen",
+	"back":                "zurück",
+	"scroll up":           "hochscrollen",
+	"scroll down":         "runterscrollen",
+	"page up":             "Seite hoch",
+	"page down":           "Seite runter",
+	"full-screen diff":    "Diff im Vollbild",
+	"use ours":            "unsere nehmen",
+	"use theirs":          "ihre nehmen",
+	"mergetool":           "Mergetool",
+	"discard":             "verwerfen",
+	"format":              "formatieren",
+	"discard all":         "alle verwerfen",
+	"clean":               "aufräumen",
+	"ignored files":       "ignorierte Dateien",
+	"delete selected":     "Auswahl löschen",
+	"toggle executable":   "ausführbar umschalten",
+	"move":                "verschieben",
+	"branches":            "Branches",
+	"switch":              "wechseln",
+	"new branch":          "neuer Branch",
+	"fetch":               "fetchen",
+	"range-diff":          "Range-Diff",
+	"recent commits":      "letzte Commits",
+	"settings":            "Einstellungen",
+	"change":              "ändern",
+	"pop stash":           "Stash anwenden",
+	"continue":            "fortsetzen",
+	"abort":               "abbrechen",
+	"skip":                "überspringen",
+	"hidden files":        "versteckte Dateien",
+	"file info":           "Dateiinfo",
+	"path display":        "Pfadanzeige",
+	"assume-unchanged":    "assume-unchanged",
+	"skip-worktree":       "skip-worktree",
+	"stage mode only":     "nur Modus vormerken",
+	"stage content only":  "nur Inhalt vormerken",
+	"next repo":           "nächstes Repo",
+	"previous repo":       "voriges Repo",
+	"yes":                 "ja",
+	"no":                  "nein",
+	"cancel":              "abbrechen",
+	"command log":         "Befehlsprotokoll",
+	"top":                 "Anfang",
+	"bottom":              "Ende",
+	"next hunk":           "nächster Abschnitt",
+	"previous hunk":       "voriger Abschnitt",
+	"bookmark":            "Lesezeichen",
+	"bookmarked only":     "nur Lesezeichen",
+	"note":                "Notiz",
+	"export summary":      "Zusammenfassung exportieren",
+	"commit":              "committen",
+	"commit in editor":    "im Editor committen",
+	"function context":    "Funktionskontext",
+	"dual diff":           "Doppel-Diff",
+	"preview untracked":   "Vorschau unversionierter",
+	"switch side":         "Seite wechseln",
+	"ignore CR at EOL":    "CR am Zeilenende ignorieren",
+	"generate message":    "Nachricht erzeugen",
+	"co-author":           "Co-Autor",
+	"trailers":            "Trailer",
+	"gitmoji":             "Gitmoji",
+	"author/date":         "Autor/Datum",
+	"toggle hunk":         "Hunk umschalten",
+	"select lines":        "Zeilen wählen",
+	"search":              "suchen",
+	"next match":          "nächster Treffer",
+	"previous match":      "voriger Treffer",
+	"fold context":        "Kontext falten",
+	"unfold":              "aufklappen",
+	"pager":               "Pager",
+	"search changes":      "Änderungen durchsuchen",
+	"all changes":         "alle Änderungen",
+	"next file":           "nächste Datei",
+	"previous file":       "vorige Datei",
+	"go to hunk":          "zum Hunk",
+	"review branch":       "Branch prüfen",
+	"file history":        "Dateiverlauf",
+	"recover":             "wiederherstellen",
+	"restore index":       "Index zurücksetzen",
+	"restore":             "zurückholen",
+	"load whole diff":     "ganzen Diff laden",
+	"Keys":                "Tasten",
+	"help":                "Hilfe",
+
+	// File list and header
+	"Unmerged paths":           "Nicht zusammengeführte Pfade",
+	"Staged changes":           "Vorgemerkte Änderungen",
+	"Changes not staged":       "Nicht vorgemerkte Änderungen",
+	"Untracked":                "Unversioniert",
+	"Committed on the branch":  "Auf dem Branch committet",
+	"History of %s":            "Verlauf von %s",
+	"as %s":                    "als %s",
+	"Files to restore from %s": "Dateien zum Zurückholen aus %s",
+	"Recover from the trash, a safety stash or the reflog": "Aus dem Papierkorb, einem Sicherungs-Stash oder dem Reflog wiederherstellen",
+	"mode %s":                       "Modus %s",
+	"type %s":                       "Typ %s",
+	"symlink":                       "Symlink",
+	"sparse":                        "sparse",
+	"line endings":                  "Zeilenenden",
+	"ignored":                       "ignoriert",
+	"staged":                        "vorgemerkt",
+	"unstaged":                      "nicht vorgemerkt",
+	"partly staged":                 "teils vorgemerkt",
+	"modified":                      "geändert",
+	"%d staged":                     "%d vorgemerkt",
+	"%d unstaged":                   "%d nicht vorgemerkt",
+	"%d untracked":                  "%d unversioniert",
+	"%d on the branch":              "%d auf dem Branch",
+	"reviewing against %s (%s)":     "Prüfung gegen %s (%s)",
+	"1 conflict":                    "1 Konflikt",
+	"%d conflicts":                  "%d Konflikte",
+	"showing %d bookmarked":         "%d mit Lesezeichen angezeigt",
+	"%d stash(es), %s to pop":       "%d Stash(es), %s zum Anwenden",
+	"safety stash %s":               "Sicherungs-Stash %s",
+	"Files hidden from git status:": "Vor git status versteckte Dateien:",
+	"No files have skip-worktree or assume-unchanged set":                      "Keine Dateien mit skip-worktree oder assume-unchanged",
+	"%d file(s) hidden by skip-worktree or assume-unchanged, press %s to show": "%d Datei(en) durch skip-worktree oder assume-unchanged versteckt, %s zeigt sie",
+	"No git commands run yet":                                                  "Noch keine git-Befehle ausgeführt",
+	"Nothing to stage, watching for changes…":                                  "Nichts vorzumerken, warte auf Änderungen…",
+	"Loading diff, %s to cancel":                                               "Diff wird geladen, %s zum Abbrechen",
+	"… %d more lines, %s in the focused diff to load them":                     "… %d weitere Zeilen, %s im fokussierten Diff lädt sie",
+	"Loading changes…":                                                         "Änderungen werden geladen…",
+	"Loading changes, %d so far…":                                              "Änderungen werden geladen, %d bisher…",
+	"Cancelled loading the diff":                                               "Laden des Diffs abgebrochen",
+
+	// Operations in progress
+	"Rebase":         "Rebase",
+	"Merge":          "Merge",
+	"Cherry-pick":    "Cherry-pick",
+	"Revert":         "Revert",
+	"Operation":      "Vorgang",
+	"%s in progress": "%s läuft",
+	"%s finished":    "%s abgeschlossen",
+	"%s --%s failed, see the output above or run it from the shell": "%s --%s fehlgeschlagen, siehe Ausgabe oben oder in der Shell ausführen",
+
+	// Background jobs
+	"Toggling":                            "Umschalten",
+	"Staging":                             "Vormerken",
+	"Unstaging":                           "Entfernen aus dem Index",
+	"Discarding":                          "Verwerfen",
+	"Deleting":                            "Löschen",
+	"%s [%s] %d/%d file(s)":               "%s [%s] %d/%d Datei(en)",
+	", cancelling…":                       ", wird abgebrochen…",
+	", %s to cancel":                      ", %s zum Abbrechen",
+	"%s failed: %v":                       "%s fehlgeschlagen: %v",
+	"%s cancelled after %d of %d file(s)": "%s nach %d von %d Datei(en) abgebrochen",
+	"Wait for %s to finish or press %s to cancel": "Warten, bis „%s“ fertig ist, oder %s zum Abbrechen",
+
+	// Prompts and messages
+	"%d file(s) outside the sparse-checkout cone will be added to the index, continue?": "%d Datei(en) außerhalb des Sparse-Checkout-Kegels werden in den Index aufgenommen, fortfahren?",
+	"Mark %s %s? Its changes will be hidden from git":                                   "%s als %s markieren? Die Änderungen werden vor git versteckt",
+	"Only tracked files can be marked %s":                                               "Nur versionierte Dateien können als %s markiert werden",
+	"Discard unstaged changes to %s?":                                                   "Nicht vorgemerkte Änderungen an %s verwerfen?",
+	"No unstaged changes to discard in %s":                                              "Keine nicht vorgemerkten Änderungen in %s",
+	"Failed to discard %s: %v":                                                          "%s konnte nicht verworfen werden: %v",
+	"No executable bit change to stage separately":                                      "Keine Änderung des Ausführbar-Bits zum separaten Vormerken",
+	"Failed to stage the mode of %s":                                                    "Der Modus von %s konnte nicht vorgemerkt werden",
+	"Symlinks have no executable bit":                                                   "Symlinks haben kein Ausführbar-Bit",
+	"Can't change the mode of %s":                                                       "Der Modus von %s kann nicht geändert werden",
+	"Failed to change the mode of %s: %v":                                               "Der Modus von %s konnte nicht geändert werden: %v",
+	"Changed the mode of %s, stage it to record the mode":                               "Modus von %s geändert, zum Festhalten vormerken",
+	"Changed the mode of %s but failed to stage it":                                     "Modus von %s geändert, aber nicht vorgemerkt",
+	"Made %s executable":                                                                "%s ist jetzt ausführbar",
+	"Made %s non-executable":                                                            "%s ist nicht mehr ausführbar",
+	"%s has no conflict to resolve":                                                     "%s hat keinen Konflikt",
+	"Resolve %s using %s?":                                                              "%s mit %s auflösen?",
+	"Failed to check out %s version of %s":                                              "Version %s von %s konnte nicht ausgecheckt werden",
+	"Resolved %s using %s":                                                              "%s mit %s aufgelöst",
+	"%s is still conflicted":                                                            "%s hat weiterhin Konflikte",
+	"git mergetool failed: %v":                                                          "git mergetool fehlgeschlagen: %v",
+	"Resolved %s":                                                                       "%s aufgelöst",
+	"No stashes to pop":                                                                 "Keine Stashes vorhanden",
+	"Pop stash@{0} (%s)?":                                                               "stash@{0} (%s) anwenden und entfernen?",
+	"Popped stash@{0}":                                                                  "stash@{0} angewendet",
+	"Stash applied with conflicts in %s, the stash was kept":                            "Stash mit Konflikten in %s angewendet, der Stash bleibt erhalten",
+	"Failed to pop stash: %s":                                                           "Stash konnte nicht angewendet werden: %s",
+	"Aborted, failed to create a safety stash: %v":                                      "Abgebrochen, Sicherungs-Stash konnte nicht erstellt werden: %v",
+	"Done, recover with `git stash apply %s`":                                           "Fertig, wiederherstellen mit `git stash apply %s`",
+	"No unstaged changes to discard":                                                    "Keine nicht vorgemerkten Änderungen zum Verwerfen",
+	"Discard unstaged changes in %d file(s)?":                                           "Nicht vorgemerkte Änderungen in %d Datei(en) verwerfen?",
+	"Delete %d untracked file(s)?":                                                      "%d unversionierte Datei(en) löschen?",
+	"No files are bookmarked, press %s to bookmark one":                                 "Keine Dateien mit Lesezeichen, %s setzt eines",
+	"Repository changed externally — reload?":                                           "Repository wurde von außen geändert — neu laden?",
+	"Note for %s: ":                                                                     "Notiz zu %s: ",
+	"Stage or unstage matching: ":                                                       "Passende vormerken oder entfernen: ",
+	"Nothing to stage or unstage matches %s":                                            "Auf %s passt nichts zum Vormerken oder Entfernen",
+	"Files matching %s":                                                                 "Auf %s passende Dateien",
+	"Files git clean deletes":                                                           "Dateien, die git clean löscht",
+	"Select the files to delete with %s":                                                "Die zu löschenden Dateien mit %s auswählen",
+	"Untracked files":                                                                   "Unversionierte Dateien",
+	"Untracked and ignored files":                                                       "Unversionierte und ignorierte Dateien",
+	"Nothing to clean":                                                                  "Nichts aufzuräumen",
+	"Not in the work tree":                                                              "Nicht im Arbeitsverzeichnis",
+	"Size: %s":                                                                          "Größe: %s",
+	"Modified: %s, %s":                                                                  "Geändert: %s, %s",
+	"Permissions: %s":                                                                   "Rechte: %s",
+	"Mode in the index: %s":                                                             "Modus im Index: %s",
+	"%s · %s · binary":                                                                  "%s · %s · binär",
+	"%s · %s · %d line(s)":                                                              "%s · %s · %d Zeile(n)",
+	"%s · %s · about %d line(s)":                                                        "%s · %s · etwa %d Zeile(n)",
+	"Untracked files show their content":                                                "Unversionierte Dateien zeigen ihren Inhalt",
+	"Untracked files show as a diff":                                                    "Unversionierte Dateien zeigen sich als Diff",
+	"Stage %d file(s) matching %s?":                                                     "%d auf %s passende Datei(en) vormerken?",
+	"Unstage %d file(s) matching %s?":                                                   "%d auf %s passende Datei(en) aus dem Index entfernen?",
+	"Commit message":                                                                    "Commit-Nachricht",
+	"Nothing staged to commit":                                                          "Nichts zum Committen vorgemerkt",
+	"Add the review notes of %d staged file(s) to the message?":                         "Die Review-Notizen von %d vorgemerkten Datei(en) in die Nachricht übernehmen?",
+	"Aborting commit due to empty commit message":                                       "Commit wegen leerer Nachricht abgebrochen",
+	"Commit failed: %v":                                                                 "Commit fehlgeschlagen: %v",
+	"Committed %s, %s to attach a git note":                                             "Committet: %s, %s um eine Git-Notiz anzuhängen",
+	"No commits yet":                                                                    "Noch keine Commits",
+	"Git note for %s: ":                                                                 "Git-Notiz für %s: ",
+	"Failed to attach the note to %s: %v":                                               "Notiz konnte nicht an %s angehängt werden: %v",
+	"Removed the note of %s":                                                            "Notiz von %s entfernt",
+	"Attached the note to %s":                                                           "Notiz an %s angehängt",
+	"Recent commits and their notes":                                                    "Letzte Commits und ihre Notizen",
+	"Settings, saved to %s":                                                             "Einstellungen, gespeichert in %s",
+	"Unknown setting %q, git-istage config list shows them all":                         "Unbekannte Einstellung %q, git-istage config list zeigt alle",
+	"%s takes true or false":                                                            "%s nimmt true oder false",
+	"%s takes a whole number":                                                           "%s nimmt eine ganze Zahl",
+	"No user config directory to keep the config file in":                               "Kein Konfigurationsverzeichnis für die Konfigurationsdatei",
+	"Set %s = %s in %s":                                                                 "%s = %s in %s gesetzt",
+	"Saved %s = %s to %s":                                                               "%s = %s in %s gespeichert",
+	"Failed to save %s: %v":                                                             "%s konnte nicht gespeichert werden: %v",
+	"Failed to apply %s: %v":                                                            "%s konnte nicht angewendet werden: %v",
+	"Welcome to git-istage":                                                             "Willkommen bei git-istage",
+	"%s and %s move between files, and the diff of the one":                             "%s und %s wechseln zwischen Dateien, und der Diff der Datei",
+	"under the cursor shows next to the list.":                                          "unter dem Cursor steht neben der Liste.",
+	"%s stages or unstages it, %s all of them.":                                         "%s merkt sie vor oder nimmt sie zurück, %s alle.",
+	"%s commits what is staged.":                                                        "%s committet das Vorgemerkte.",
+	"Hunks and lines":                                                                   "Hunks und Zeilen",
+	"%s focuses the diff, %s and %s jump between its hunks.":                            "%s fokussiert den Diff, %s und %s springen zwischen den Hunks.",
+	"%s stages or unstages the hunk under the cursor,":                                  "%s merkt den Hunk unter dem Cursor vor oder nimmt ihn zurück,",
+	"%s selects lines of it to stage on their own.":                                     "%s wählt einzelne Zeilen daraus zum Vormerken.",
+	"%s goes back to the list.":                                                         "%s geht zurück zur Liste.",
+	"Finding your way":                                                                  "Zurechtfinden",
+	"%s shows every key, the footer the most useful ones.":                              "%s zeigt alle Tasten, die Fußzeile die nützlichsten.",
+	"%s searches the changes, %s recovers what was discarded.":                          "%s durchsucht die Änderungen, %s stellt Verworfenes wieder her.",
+	"%s lists the settings to change.":                                                  "%s listet die Einstellungen zum Ändern.",
+	"Write a default config file to %s?":                                                "Eine Standardkonfiguration nach %s schreiben?",
+	"A config file":                                                                     "Eine Konfigurationsdatei",
+	"Settings are kept in %s,":                                                          "Einstellungen stehen in %s,",
+	"changed with %s or git-istage config set.":                                         "geändert mit %s oder git-istage config set.",
+	"Without one this tour shows again on the next start.":                              "Ohne sie erscheint diese Tour beim nächsten Start wieder.",
+	"Failed to write the config file: %v":                                               "Konfigurationsdatei konnte nicht geschrieben werden: %v",
+	"Wrote the default config to %s":                                                    "Standardkonfiguration nach %s geschrieben",
+	"The index had conflicts at the start, there's no snapshot of it":                   "Der Index hatte beim Start Konflikte, es gibt keinen Schnappschuss davon",
+	"The index has conflicts, resolve them before restoring it":                         "Der Index hat Konflikte, vor dem Zurücksetzen auflösen",
+	"The index is as it was at the start":                                               "Der Index ist wie beim Start",
+	"Files staged or unstaged since the start":                                          "Seit dem Start vorgemerkte oder zurückgenommene Dateien",
+	"Put the index of %d file(s) back as it was at the start?":                          "Index von %d Datei(en) auf den Stand beim Start zurücksetzen?",
+	"Failed to restore the index: %v":                                                   "Index konnte nicht zurückgesetzt werden: %v",
+	"The index is back as it was at the start":                                          "Der Index ist wieder wie beim Start",
+	"any key for more, %s to skip":                                                      "beliebige Taste für mehr, %s zum Überspringen",
+	"any key to finish":                                                                 "beliebige Taste zum Beenden",
+	"Commit %d staged file(s)":                                                          "%d vorgemerkte Datei(en) committen",
+	"Committing…":                                                                       "Commit läuft…",
+	"Set generator in the [commit] config to generate messages":                         "Zum Erzeugen von Nachrichten generator in [commit] konfigurieren",
+	"Cancelled generating the message":                                                  "Erzeugen der Nachricht abgebrochen",
+	"Message generator failed: %v":                                                      "Nachrichtengenerator fehlgeschlagen: %v",
+	"The message generator printed nothing":                                             "Der Nachrichtengenerator hat nichts ausgegeben",
+	"Replace the message with the generated one?":                                       "Nachricht durch die erzeugte ersetzen?",
+	"Generating the message, %s to cancel":                                              "Nachricht wird erzeugt, %s zum Abbrechen",
+	"No co-authors to pick, add some to co_authors in the [commit] config":              "Keine Co-Autoren zur Auswahl, in co_authors in [commit] eintragen",
+	"Co-author: ":                                                                       "Co-Autor: ",
+	"No hunk to stage here":                                                             "Hier ist kein Hunk zum Vormerken",
+	"No changed lines selected":                                                         "Keine geänderten Zeilen gewählt",
+	"Resolve the conflict before staging parts of %s":                                   "Erst den Konflikt auflösen, dann Teile von %s vormerken",
+	"Symlinks can only be staged as a whole":                                            "Symlinks können nur als Ganzes vorgemerkt werden",
+	"Type changes can only be staged as a whole":                                        "Typänderungen können nur als Ganzes vorgemerkt werden",
+	"Search cleared":                                                                    "Suche gelöscht",
+	"Search the diff with %s first":                                                     "Erst mit %s im Diff suchen",
+	"No match for %q":                                                                   "Kein Treffer für %q",
+	"Match %d of %d for %q":                                                             "Treffer %d von %d für %q",
+	"No change matches %q":                                                              "Keine Änderung enthält %q",
+	", %d diff(s) searched only in their first %d lines":                                ", %d Diff(s) nur in den ersten %d Zeilen durchsucht",
+	"Cancelled the search":                                                              "Suche abgebrochen",
+	"Searching the changes for %q…":                                                     "Änderungen werden nach %q durchsucht…",
+	"… %d unchanged lines":                                                              "… %d unveränderte Zeilen",
+	"Paging is turned off, set core.pager to read diffs in a pager":                     "Pager ist abgeschaltet, core.pager setzen, um Diffs im Pager zu lesen",
+	"No folded lines on screen":                                                         "Keine gefalteten Zeilen sichtbar",
+	"Unchanged lines further than %d from a change are folded, %s in the diff unfolds them": "Unveränderte Zeilen weiter als %d von einer Änderung sind gefaltet, %s im Diff klappt sie auf",
+	"Diffs show every unchanged line":                                    "Diffs zeigen jede unveränderte Zeile",
+	"%d hunk(s) matching %q":                                             "%d Hunk(s) mit %q",
+	"All changes, %d file(s)":                                            "Alle Änderungen, %d Datei(en)",
+	"… %d more file(s) left out":                                         "… %d weitere Datei(en) ausgelassen",
+	"Show the line endings again with %s to stage hunks":                 "Zum Vormerken von Hunks die Zeilenenden mit %s wieder anzeigen",
+	"Files with a diff driver can only be staged as a whole":             "Dateien mit Diff-Treiber können nur als Ganzes vorgemerkt werden",
+	"The hunk is cut off, press %s to load the whole diff first":         "Der Hunk ist abgeschnitten, zuerst mit %s den ganzen Diff laden",
+	"Failed to stage the hunk: %v":                                       "Hunk konnte nicht vorgemerkt werden: %v",
+	"The diff changed, try again":                                        "Der Diff hat sich geändert, bitte erneut versuchen",
+	"No upstream branch to review against, set one with `git branch -u`": "Kein Upstream-Branch zum Vergleichen, mit `git branch -u` einen setzen",
+	"No common ancestor of HEAD and %s":                                  "Kein gemeinsamer Vorfahre von HEAD und %s",
+	"Changes committed on the branch can't be staged or unstaged":        "Auf dem Branch committete Änderungen können nicht vorgemerkt werden",
+	"Leave the branch review with %s to stage hunks":                     "Zum Vormerken von Hunks die Branch-Prüfung mit %s verlassen",
+	"Leave the branch review with %s to search the changes":              "Zum Durchsuchen der Änderungen die Branch-Prüfung mit %s verlassen",
+	"Leave the branch review with %s to read all the changes":            "Zum Lesen aller Änderungen die Branch-Prüfung mit %s verlassen",
+	"No commits change %s":                                               "Keine Commits ändern %s",
+	"No safety stashes or reflog entries to recover from":                "Keine Sicherungs-Stashes oder Reflog-Einträge zum Wiederherstellen",
+	"The work tree has the same content as %s":                           "Das Arbeitsverzeichnis hat denselben Inhalt wie %s",
+	"Overwrite %s in the work tree with its content from %s?":            "%s im Arbeitsverzeichnis mit dem Inhalt aus %s überschreiben?",
+	"Failed to restore %s: %v":                                           "%s konnte nicht zurückgeholt werden: %v",
+	"Restored %s from %s":                                                "%s aus %s zurückgeholt",
+	"Aborted, failed to copy the files to the trash: %v":                 "Abgebrochen, die Dateien konnten nicht in den Papierkorb kopiert werden: %v",
+	"failed to copy the files to the trash: %v":                          "die Dateien konnten nicht in den Papierkorb kopiert werden: %v",
+	"Discarded, the old content is in the trash, %s to recover it":       "Verworfen, der alte Inhalt liegt im Papierkorb, %s holt ihn zurück",
+	"Done, the old content is in the trash, %s to recover it":            "Fertig, der alte Inhalt liegt im Papierkorb, %s holt ihn zurück",
+	"%s ago":                     "vor %s",
+	"discarded or deleted files": "verworfene oder gelöschte Dateien",
+
+	// Index lock
+	"Remove the index lock?":     "Index-Sperre entfernen?",
+	"The index is locked":        "Der Index ist gesperrt",
+	"%s exists, created %s ago.": "%s existiert, erstellt vor %s.",
+	"unknown":                    "unbekannter Zeit",
+	"Another git process is probably running, e.g. an editor":      "Vermutlich läuft ein anderer git-Prozess, etwa eine Editor-",
+	"integration or a commit waiting for its message.":             "Integration oder ein Commit, der auf seine Nachricht wartet.",
+	"If no git process is running, the lock is stale, left behind": "Läuft kein git-Prozess, ist die Sperre veraltet, von einem",
+	"by a crashed git, and can be removed safely.":                 "abgestürzten git übrig, und kann gefahrlos entfernt werden.",
+	"Failed to remove %s: %v":                                      "%s konnte nicht entfernt werden: %v",
+	"Removed the index lock, try again":                            "Index-Sperre entfernt, bitte erneut versuchen",
+
+	// Command line and config errors
+	"Usage: %s [flags] [repository...]":                                          "Aufruf: %s [Optionen] [Repository...]",
+	"   or: %s completion %s":                                                    "   oder: %s completion %s",
+	"   or: %s config list|get <name>|set <name> <value>":                        "   oder: %s config list|get <Name>|set <Name> <Wert>",
+	"color theme, one of %v":                                                     "Farbschema, eines von %v",
+	"file listing repositories to open as tabs, one per line":                    "Datei mit Repositories, die als Tabs geöffnet werden, eines pro Zeile",
+	"review everything the branch changes since it forked from its upstream":     "alles prüfen, was der Branch seit der Abzweigung vom Upstream ändert",
+	"try the interface on a made up repository kept in memory":                   "die Oberfläche an einem erfundenen Repository im Speicher ausprobieren",
+	"record the keys pressed and what git answered into a file, for bug reports": "die gedrückten Tasten und die Antworten von git für Fehlerberichte in eine Datei aufzeichnen",
+	"replay a session recorded with --record":                                    "eine mit --record aufgezeichnete Sitzung abspielen",
+	"print the version and exit":                                                 "die Version ausgeben und beenden",
+	"stay open when there's nothing to stage and list changes as they appear":    "offen bleiben, wenn es nichts vorzumerken gibt, und Änderungen auflisten, sobald sie auftauchen",
+	"Hunks show their whole function":                                            "Hunks zeigen ihre ganze Funktion",
+	"Hunks show the usual context":                                               "Hunks zeigen den üblichen Kontext",
+	"The diff shows the changes of the selected section":                         "Der Diff zeigt die Änderungen des gewählten Abschnitts",
+	"Staged and unstaged changes side by side, %s in the diff to switch sides":   "Vorgemerkte und nicht vorgemerkte Änderungen nebeneinander, %s im Diff wechselt die Seite",
+	"No changes":                             "Keine Änderungen",
+	"Diffs leave out CR at the end of lines": "Diffs lassen CR am Zeilenende aus",
+	"Diffs show changes of line endings":     "Diffs zeigen Änderungen der Zeilenenden",
+	"how similar in percent a deleted and an added file must be to show as a rename": "wie ähnlich in Prozent eine gelöschte und eine hinzugefügte Datei sein müssen, um als Umbenennung zu gelten",
+	"find_renames must be a percentage from 1 to 100":                                "find_renames muss ein Prozentwert von 1 bis 100 sein",
+	"A launcher needs a name, a key and a command":                                   "Ein Starter braucht einen Namen, eine Taste und einen Befehl",
+	"Unknown shell %q, completion is available for %v":                               "Unbekannte Shell %q, Vervollständigung gibt es für %v",
+	"Unknown paths %q, available paths: %v":                                          "Unbekannte paths %q, verfügbare paths: %v",
+	"Paths from the current directory":                                               "Pfade ab dem aktuellen Verzeichnis",
+	"Absolute paths":                                                                 "Absolute Pfade",
+	"Paths from the repository root":                                                 "Pfade ab der Wurzel des Repositorys",
+	"write a profile on exit and time git commands, one of %v":                       "beim Beenden ein Profil schreiben und Git-Befehle messen, eines von %v",
+	"Error:":                                     "Fehler:",
+	"Error reading config:":                      "Fehler beim Lesen der Konfiguration:",
+	"Error opening log file:":                    "Fehler beim Öffnen der Protokolldatei:",
+	"Error reading workspace:":                   "Fehler beim Lesen des Arbeitsbereichs:",
+	"Error reading session:":                     "Fehler beim Lesen der Sitzung:",
+	"Error writing session:":                     "Fehler beim Schreiben der Sitzung:",
+	"Recorded the session to %s":                 "Sitzung in %s aufgezeichnet",
+	"git-istage crashed: %v":                     "git-istage ist abgestürzt: %v",
+	"Last git commands:":                         "Letzte git-Befehle:",
+	"Unsupported session version %d":             "Nicht unterstützte Sitzungsversion %d",
+	"Error writing profile:":                     "Fehler beim Schreiben des Profils:",
+	"Wrote the %s profile to %s":                 "%s-Profil nach %s geschrieben",
+	"%s: %d run(s), %s in total, %s at most":     "%s: %d Aufruf(e), insgesamt %s, höchstens %s",
+	"Unknown profile %q, available profiles: %v": "Unbekanntes Profil %q, verfügbare Profile: %v",
+	"Error running program:":                     "Fehler beim Ausführen:",
+	"No changes to stage or unstage.":            "Keine Änderungen zum Vormerken oder Entfernen.",
+	"Not inside a git repository: %s":            "Kein git-Repository: %s",
+	"git isn't installed, or isn't in PATH. Install it from https://git-scm.com/downloads and try again.": "git ist nicht installiert oder nicht im PATH. Bitte von https://git-scm.com/downloads installieren und erneut versuchen.",
+	"git %v is too old, git-istage needs %v or later. Upgrade it from https://git-scm.com/downloads.":     "git %v ist zu alt, git-istage braucht %v oder neuer. Bitte von https://git-scm.com/downloads aktualisieren.",
+	"Unknown theme %q, available themes: %v":                                                              "Unbekanntes Farbschema %q, verfügbar: %v",
+	"Unknown language %q, available languages: %v":                                                        "Unbekannte Sprache %q, verfügbar: %v",
+	"Unknown icon set %q, available icon sets: %v":                                                        "Unbekannter Symbolsatz %q, verfügbar: %v",
+	"Invalid branch_pattern in [commit]: %v":                                                              "Ungültiges branch_pattern in [commit]: %v",
+	"Invalid pattern in [secrets]: %v":                                                                    "Ungültiges Muster in [secrets]: %v",
+	"Invalid pattern %q in [formatters]: %v":                                                              "Ungültiges Muster %q in [formatters]: %v",
+	"No formatter for %s, add one to [formatters] in the config":                                          "Kein Formatierer für %s, bitte einen unter [formatters] in der Konfiguration eintragen",
+	"%s was deleted, there's nothing to format":                                                           "%s wurde gelöscht, es gibt nichts zu formatieren",
+	"Formatting %s…":               "Formatiere %s…",
+	"Formatting %s failed: %v":     "Formatieren von %s fehlgeschlagen: %v",
+	"Formatted %s":                 "%s formatiert",
+	"Move %s to: ":                 "%s verschieben nach: ",
+	"%s is outside the repository": "%s liegt außerhalb des Repositorys",
+	"Failed to move %s: %v":        "%s konnte nicht verschoben werden: %v",
+	"Moved %s to %s":               "%s nach %s verschoben",
+	"New branch from HEAD: ":       "Neuer Branch von HEAD: ",
+	"Failed to create %s: %v":      "%s konnte nicht erstellt werden: %v",
+	"Created and switched to %s":   "%s erstellt und dorthin gewechselt",
+	"Already on %s":                "Bereits auf %s",
+	"Finish or abort the %s in progress before switching branches":          "Vor dem Branch-Wechsel erst %s abschließen oder abbrechen",
+	"Your changes conflict with %s. Stash them, switch and pop them there?": "Die Änderungen kollidieren mit %s. Stashen, wechseln und dort wieder anwenden?",
+	"Failed to switch to %s: %v":                                            "Wechsel zu %s fehlgeschlagen: %v",
+	"Switched to %s, the changes came along":                                "Zu %s gewechselt, die Änderungen wurden mitgenommen",
+	"Switched to %s":                                                        "Zu %s gewechselt",
+	"Failed to stash the changes: %s":                                       "Stashen der Änderungen fehlgeschlagen: %s",
+	"Switched to %s and popped the changes":                                 "Zu %s gewechselt und die Änderungen wieder angewendet",
+	"No local branches yet, press %s to create one":                         "Noch keine lokalen Branches, mit %s einen erstellen",
+	"Local branches":                                                        "Lokale Branches",
+	"no upstream":                                                           "kein Upstream",
+	"up to date":                                                            "aktuell",
+	"Fetching…":                                                             "Fetchen…",
+	"Failed to fetch: %v":                                                   "Fetchen fehlgeschlagen: %v",
+	"Fetching needs credentials, enter them in the terminal?":               "Das Fetchen braucht Zugangsdaten, im Terminal eingeben?",
+	"Push %s and run %s?":                                                   "%s pushen und %s ausführen?",
+	"Pushing…":                                                              "Pushen…",
+	"Pushing needs credentials, enter them in the terminal?":                "Das Pushen braucht Zugangsdaten, im Terminal eingeben?",
+	"Failed to push: %v":                                                    "Pushen fehlgeschlagen: %v",
+	"Pushed %s":                                                             "%s gepusht",
+	"Staged changes on %s":                                                  "Vorgemerkte Änderungen auf %s",
+	"(renamed from `%s`)":                                                   "(umbenannt von `%s`)",
+	"(new)":                                                                 "(neu)",
+	"(deleted)":                                                             "(gelöscht)",
+	"%d file(s) changed, +%d -%d":                                           "%d Datei(en) geändert, +%d -%d",
+	"Nothing staged to summarize":                                           "Nichts vorgemerkt zum Zusammenfassen",
+	"Export the summary to a file, or to the clipboard if empty: ":                "Zusammenfassung in Datei exportieren, leer für die Zwischenablage: ",
+	"Failed to copy the summary: %v":                                              "Kopieren der Zusammenfassung fehlgeschlagen: %v",
+	"Copied the summary to the clipboard":                                         "Zusammenfassung in die Zwischenablage kopiert",
+	"Failed to export the summary: %v":                                            "Exportieren der Zusammenfassung fehlgeschlagen: %v",
+	"Exported the summary to %s":                                                  "Zusammenfassung nach %s exportiert",
+	"Overwrite %s?":                                                               "%s überschreiben?",
+	"The content of %s isn't available locally, fetch it from %s?":                "Der Inhalt von %s ist lokal nicht vorhanden, von %s fetchen?",
+	"Fetching the content from %s…":                                               "Inhalt wird von %s gefetcht…",
+	"Failed to fetch the content: %v":                                             "Inhalt konnte nicht gefetcht werden: %v",
+	"Fetched the content from %s":                                                 "Inhalt von %s gefetcht",
+	"Invalid git_timeout %q, write it like \"30s\" or \"2m\", or \"0\" for none":  "Ungültiges git_timeout %q, bitte wie \"30s\" oder \"2m\" schreiben, oder \"0\" für keins",
+	"%v, raise git_timeout in the config if it needs longer":                      "%v, git_timeout in der Konfiguration erhöhen, falls es länger dauern darf",
+	"Not available locally in this partial clone":                                 "In diesem Partial Clone lokal nicht vorhanden",
+	"Fetched, the branch has no upstream to compare with":                         "Gefetcht, der Branch hat keinen Upstream zum Vergleichen",
+	"Fetched, %d commit(s) behind %s, pull before committing":                     "Gefetcht, %d Commit(s) hinter %s, vor dem Committen pullen",
+	"Fetched, nothing new on %s":                                                  "Gefetcht, nichts Neues auf %s",
+	"Range-diff of: ":                                                             "Range-Diff von: ",
+	"Search the changes for: ":                                                    "Änderungen durchsuchen nach: ",
+	"Failed to range-diff %s: %v":                                                 "Range-Diff von %s fehlgeschlagen: %v",
+	"No commits to compare in %s":                                                 "Keine Commits zum Vergleichen in %s",
+	"Range-diff of %s":                                                            "Range-Diff von %s",
+	"Invalid pattern %q in [commit.default_trailers]: %v":                         "Ungültiges Muster %q in [commit.default_trailers]: %v",
+	"Invalid trailer %q in [commit.default_trailers], write it as \"Key: value\"": "Ungültiger Trailer %q in [commit.default_trailers], als \"Schlüssel: Wert\" schreiben",
+	"No trailers to fill in, add some to trailers in the [commit] config":         "Keine Trailer zum Ausfüllen, in trailers in [commit] eintragen",
+	"Failed to add the trailers: %v":                                              "Trailer konnten nicht hinzugefügt werden: %v",
+	"Trailers, empty ones are left out":                                           "Trailer, leere werden weggelassen",
+	"Loading issues…":                                                             "Issues werden geladen…",
+	"Issue: #":                                                                    "Issue: #",
+	"No open issues to complete with":                                             "Keine offenen Issues zum Vervollständigen",
+	"Failed to load issues: %v":                                                   "Issues konnten nicht geladen werden: %v",
+	"Unknown gitmoji %q in [commit], use %q or %q":                                "Unbekanntes gitmoji %q in [commit], %q oder %q verwenden",
+	"Set gitmoji in the [commit] config to pick one":                              "Zum Auswählen eines Gitmojis gitmoji in [commit] konfigurieren",
+	"Gitmoji: ":                       "Gitmoji: ",
+	"Author: ":                        "Autor: ",
+	"Date: ":                          "Datum: ",
+	"Name <email>, empty for yours":   "Name <E-Mail>, leer für den eigenen",
+	"2024-05-01 14:30, empty for now": "2024-05-01 14:30, leer für jetzt",
+	"Author and date of the commit":   "Autor und Datum des Commits",
+	"Invalid author %q, write it as \"Name <email>\"": "Ungültiger Autor %q, bitte als \"Name <E-Mail>\" schreiben",
+	"Invalid date %q, write it like 2024-05-01 14:30": "Ungültiges Datum %q, bitte wie 2024-05-01 14:30 schreiben",
+	"The date %s is in the future":                    "Das Datum %s liegt in der Zukunft",
+	"Possible secrets in the changes to stage":        "Mögliche Geheimnisse in den vorzumerkenden Änderungen",
+	"and %d more": "und %d weitere",
+	"Once committed, they stay in the history even if removed later.": "Einmal committet, bleiben sie in der Historie, auch wenn sie später entfernt werden.",
+	"Stage %d possible secret(s) anyway?":                             "%d mögliche(s) Geheimnis(se) trotzdem vormerken?",
+	"Unknown action %q in [keys]":                                     "Unbekannte Aktion %q in [keys]",
+	"No keys given for %q in [keys]":                                  "Keine Tasten für %q in [keys] angegeben",
+}
@@ -0,0 +1,479 @@
+package main
+
+var catalogFR = catalog{
+	// Footer hints
+	"quit":                "quitter",
+	"up":                  "haut",
+	"down":                "bas",
+	"next column":         "colonne suivante",
+	"previous column":     "colonne précédente",
+	"go to file":          "aller au fichier",
+	"next directory":      "répertoire suivant",
+	"previous directory":  "répertoire précédent",
+	"toggle":              "basculer",
+	"toggle all":          "tout basculer",
+	"stage matching":      "indexer par motif",
+	"toggle and next":     "basculer et suivant",
+	"toggle and previous": "basculer et précédent",
+	"view diff":           "voir le diff",
+	"back":                "retour",
+	"scroll up":           "défiler vers le haut",
+	"scroll down":         "défiler vers le bas",
+	"page up":             "page précédente",
+	"page down":           "page suivante",
+	"full-screen diff":    "diff en plein écran",
+	"use ours":            "garder la nôtre",
+	"use theirs":          "garder la leur",
+	"mergetool":           "mergetool",
+	"discard":             "annuler",
+	"format":              "formater",
+	"discard all":         "tout annuler",
+	"clean":               "nettoyer",
+	"ignored files":       "fichiers ignorés",
+	"delete selected":     "supprimer la sélection",
+	"toggle executable":   "basculer exécutable",
+	"move":                "déplacer",
+	"branches":            "branches",
+	"switch":              "basculer",
+	"new branch":          "nouvelle branche",
+	"fetch":               "récupérer",
+	"range-diff":          "range-diff",
+	"recent commits":      "commits récents",
+	"settings":            "réglages",
+	"change":              "modifier",
+	"pop stash":           "appliquer le remisage",
+	"continue":            "continuer",
+	"abort":               "abandonner",
+	"skip":                "passer",
+	"hidden files":        "fichiers masqués",
+	"file info":           "infos du fichier",
+	"path display":        "affichage des chemins",
+	"assume-unchanged":    "assume-unchanged",
+	"skip-worktree":       "skip-worktree",
+	"stage mode only":     "indexer le mode seul",
+	"stage content only":  "indexer le contenu seul",
+	"next repo":           "dépôt suivant",
+	"previous repo":       "dépôt précédent",
+	"yes":                 "oui",
+	"no":                  "non",
+	"cancel":              "annuler",
+	"command log":         "journal des commandes",
+	"top":                 "début",
+	"bottom":              "fin",
+	"next hunk":           "bloc suivant",
+	"previous hunk":       "bloc précédent",
+	"bookmark":            "favori",
+	"bookmarked only":     "favoris seuls",
+	"note":                "note",
+	"export summary":      "exporter le résumé",
+	"commit":              "commiter",
+	"commit in editor":    "valider dans l'éditeur",
+	"function context":    "contexte de fonction",
+	"dual diff":           "diff double",
+	"preview untracked":   "aperçu des non suivis",
+	"switch side":         "changer de côté",
+	"ignore CR at EOL":    "ignorer CR en fin de ligne",
+	"generate message":    "générer le message",
+	"co-author":           "co-auteur",
+	"trailers":            "trailers",
+	"gitmoji":             "gitmoji",
+	"author/date":         "auteur/date",
+	"toggle hunk":         "basculer le hunk",
+	"select lines":        "choisir des lignes",
+	"search":              "rechercher",
+	"next match":          "occurrence suivante",
+	"previous match":      "occurrence précédente",
+	"fold context":        "replier le contexte",
+	"unfold":              "déplier",
+	"pager":               "pager",
+	"search changes":      "chercher dans les changements",
+	"all changes":         "tous les changements",
+	"next file":           "fichier suivant",
+	"previous file":       "fichier précédent",
+	"go to hunk":          "aller au hunk",
+	"review branch":       "revue de branche",
+	"file history":        "historique du fichier",
+	"recover":             "récupérer",
+	"restore index":       "restaurer l'index",
+	"restore":             "restaurer",
+	"load whole diff":     "charger tout le diff",
+	"Keys":                "Touches",
+	"help":                "aide",
+
+	// File list and header
+	"Unmerged paths":           "Chemins non fusionnés",
+	"Staged changes":           "Modifications indexées",
+	"Changes not staged":       "Modifications non indexées",
+	"Untracked":                "Non suivis",
+	"Committed on the branch":  "Commité sur la branche",
+	"History of %s":            "Historique de %s",
+	"as %s":                    "sous %s",
+	"Files to restore from %s": "Fichiers à restaurer depuis %s",
+	"Recover from the trash, a safety stash or the reflog": "Récupérer depuis la corbeille, un stash de sécurité ou le reflog",
+	"mode %s":                       "mode %s",
+	"type %s":                       "type %s",
+	"symlink":                       "lien",
+	"sparse":                        "sparse",
+	"line endings":                  "fins de ligne",
+	"ignored":                       "ignoré",
+	"staged":                        "indexé",
+	"unstaged":                      "non indexé",
+	"partly staged":                 "en partie indexé",
+	"modified":                      "modifié",
+	"%d staged":                     "%d indexé(s)",
+	"%d unstaged":                   "%d non indexé(s)",
+	"%d untracked":                  "%d non suivi(s)",
+	"%d on the branch":              "%d sur la branche",
+	"reviewing against %s (%s)":     "revue par rapport à %s (%s)",
+	"1 conflict":                    "1 conflit",
+	"%d conflicts":                  "%d conflits",
+	"showing %d bookmarked":         "%d favori(s) affiché(s)",
+	"%d stash(es), %s to pop":       "%d remisage(s), %s pour appliquer",
+	"safety stash %s":               "remisage de sécurité %s",
+	"Files hidden from git status:": "Fichiers masqués de git status :",
+	"No files have skip-worktree or assume-unchanged set":                      "Aucun fichier avec skip-worktree ou assume-unchanged",
+	"%d file(s) hidden by skip-worktree or assume-unchanged, press %s to show": "%d fichier(s) masqué(s) par skip-worktree ou assume-unchanged, %s pour les voir",
+	"No git commands run yet":                                                  "Aucune commande git lancée pour l'instant",
+	"Nothing to stage, watching for changes…":                                  "Rien à indexer, en attente de modifications…",
+	"Loading diff, %s to cancel":                                               "Chargement du diff, %s pour annuler",
+	"… %d more lines, %s in the focused diff to load them":                     "… %d lignes de plus, %s dans le diff actif pour les charger",
+	"Loading changes…":                                                         "Chargement des modifications…",
+	"Loading changes, %d so far…":                                              "Chargement des modifications, %d pour l'instant…",
+	"Cancelled loading the diff":                                               "Chargement du diff annulé",
+
+	// Operations in progress
+	"Rebase":         "Rebase",
+	"Merge":          "Fusion",
+	"Cherry-pick":    "Cherry-pick",
+	"Revert":         "Revert",
+	"Operation":      "Opération",
+	"%s in progress": "%s en cours",
+	"%s finished":    "%s terminé",
+	"%s --%s failed, see the output above or run it from the shell": "%s --%s a échoué, voir la sortie ci-dessus ou lancer depuis le shell",
+
+	// Background jobs
+	"Toggling":                            "Bascule",
+	"Staging":                             "Indexation",
+	"Unstaging":                           "Désindexation",
+	"Discarding":                          "Annulation",
+	"Deleting":                            "Suppression",
+	"%s [%s] %d/%d file(s)":               "%s [%s] %d/%d fichier(s)",
+	", cancelling…":                       ", annulation…",
+	", %s to cancel":                      ", %s pour annuler",
+	"%s failed: %v":                       "%s a échoué : %v",
+	"%s cancelled after %d of %d file(s)": "%s annulée après %d fichier(s) sur %d",
+	"Wait for %s to finish or press %s to cancel": "Attendre la fin de « %s » ou %s pour annuler",
+
+	// Prompts and messages
+	"%d file(s) outside the sparse-checkout cone will be added to the index, continue?": "%d fichier(s) hors du cône sparse-checkout seront ajoutés à l'index, continuer ?",
+	"Mark %s %s? Its changes will be hidden from git":                                   "Marquer %s %s ? Ses modifications seront masquées de git",
+	"Only tracked files can be marked %s":                                               "Seuls les fichiers suivis peuvent être marqués %s",
+	"Discard unstaged changes to %s?":                                                   "Annuler les modifications non indexées de %s ?",
+	"No unstaged changes to discard in %s":                                              "Aucune modification non indexée dans %s",
+	"Failed to discard %s: %v":                                                          "Impossible d'annuler %s : %v",
+	"No executable bit change to stage separately":                                      "Aucun changement du bit exécutable à indexer séparément",
+	"Failed to stage the mode of %s":                                                    "Impossible d'indexer le mode de %s",
+	"Symlinks have no executable bit":                                                   "Les liens symboliques n'ont pas de bit exécutable",
+	"Can't change the mode of %s":                                                       "Impossible de changer le mode de %s",
+	"Failed to change the mode of %s: %v":                                               "Impossible de changer le mode de %s : %v",
+	"Changed the mode of %s, stage it to record the mode":                               "Mode de %s changé, indexez-le pour l'enregistrer",
+	"Changed the mode of %s but failed to stage it":                                     "Mode de %s changé mais non indexé",
+	"Made %s executable":                                                                "%s est maintenant exécutable",
+	"Made %s non-executable":                                                            "%s n'est plus exécutable",
+	"%s has no conflict to resolve":                                                     "%s n'a aucun conflit à résoudre",
+	"Resolve %s using %s?":                                                              "Résoudre %s avec %s ?",
+	"Failed to check out %s version of %s":                                              "Impossible d'extraire la version %s de %s",
+	"Resolved %s using %s":                                                              "%s résolu avec %s",
+	"%s is still conflicted":                                                            "%s est toujours en conflit",
+	"git mergetool failed: %v":                                                          "git mergetool a échoué : %v",
+	"Resolved %s":                                                                       "%s résolu",
+	"No stashes to pop":                                                                 "Aucun remisage à appliquer",
+	"Pop stash@{0} (%s)?":                                                               "Appliquer et retirer stash@{0} (%s) ?",
+	"Popped stash@{0}":                                                                  "stash@{0} appliqué",
+	"Stash applied with conflicts in %s, the stash was kept":                            "Remisage appliqué avec des conflits dans %s, il a été conservé",
+	"Failed to pop stash: %s":                                                           "Impossible d'appliquer le remisage : %s",
+	"Aborted, failed to create a safety stash: %v":                                      "Abandon, impossible de créer un remisage de sécurité : %v",
+	"Done, recover with `git stash apply %s`":                                           "Terminé, récupérable avec `git stash apply %s`",
+	"No unstaged changes to discard":                                                    "Aucune modification non indexée à annuler",
+	"Discard unstaged changes in %d file(s)?":                                           "Annuler les modifications non indexées de %d fichier(s) ?",
+	"Delete %d untracked file(s)?":                                                      "Supprimer %d fichier(s) non suivi(s) ?",
+	"No files are bookmarked, press %s to bookmark one":                                 "Aucun fichier en favori, %s pour en ajouter un",
+	"Repository changed externally — reload?":                                           "Le dépôt a été modifié par ailleurs — recharger ?",
+	"Note for %s: ":                                                                     "Note pour %s : ",
+	"Stage or unstage matching: ":                                                       "Indexer ou désindexer selon le motif : ",
+	"Nothing to stage or unstage matches %s":                                            "Rien à indexer ou désindexer ne correspond à %s",
+	"Files matching %s":                                                                 "Fichiers correspondant à %s",
+	"Files git clean deletes":                                                           "Fichiers que git clean supprime",
+	"Select the files to delete with %s":                                                "Sélectionnez les fichiers à supprimer avec %s",
+	"Untracked files":                                                                   "Fichiers non suivis",
+	"Untracked and ignored files":                                                       "Fichiers non suivis et ignorés",
+	"Nothing to clean":                                                                  "Rien à nettoyer",
+	"Not in the work tree":                                                              "Absent de l'arbre de travail",
+	"Size: %s":                                                                          "Taille : %s",
+	"Modified: %s, %s":                                                                  "Modifié : %s, %s",
+	"Permissions: %s":                                                                   "Permissions : %s",
+	"Mode in the index: %s":                                                             "Mode dans l'index : %s",
+	"%s · %s · binary":                                                                  "%s · %s · binaire",
+	"%s · %s · %d line(s)":                                                              "%s · %s · %d ligne(s)",
+	"%s · %s · about %d line(s)":                                                        "%s · %s · environ %d ligne(s)",
+	"Untracked files show their content":                                                "Les fichiers non suivis montrent leur contenu",
+	"Untracked files show as a diff":                                                    "Les fichiers non suivis s'affichent en diff",
+	"Stage %d file(s) matching %s?":                                                     "Indexer %d fichier(s) correspondant à %s ?",
+	"Unstage %d file(s) matching %s?":                                                   "Désindexer %d fichier(s) correspondant à %s ?",
+	"Commit message":                                                                    "Message de commit",
+	"Nothing staged to commit":                                                          "Rien d'indexé à commiter",
+	"Add the review notes of %d staged file(s) to the message?":                         "Ajouter au message les notes de relecture de %d fichier(s) indexé(s) ?",
+	"Aborting commit due to empty commit message":                                       "Commit abandonné, le message est vide",
+	"Commit failed: %v":                                                                 "Le commit a échoué : %v",
+	"Committed %s, %s to attach a git note":                                             "Commit créé : %s, %s pour y attacher une note git",
+	"No commits yet":                                                                    "Aucun commit pour l'instant",
+	"Git note for %s: ":                                                                 "Note git pour %s : ",
+	"Failed to attach the note to %s: %v":                                               "Impossible d'attacher la note à %s : %v",
+	"Removed the note of %s":                                                            "Note de %s supprimée",
+	"Attached the note to %s":                                                           "Note attachée à %s",
+	"Recent commits and their notes":                                                    "Commits récents et leurs notes",
+	"Settings, saved to %s":                                                             "Réglages, enregistrés dans %s",
+	"Unknown setting %q, git-istage config list shows them all":                         "Réglage %q inconnu, git-istage config list les montre tous",
+	"%s takes true or false":                                                            "%s prend true ou false",
+	"%s takes a whole number":                                                           "%s prend un nombre entier",
+	"No user config directory to keep the config file in":                               "Aucun répertoire de configuration pour le fichier de configuration",
+	"Set %s = %s in %s":                                                                 "%s = %s défini dans %s",
+	"Saved %s = %s to %s":                                                               "%s = %s enregistré dans %s",
+	"Failed to save %s: %v":                                                             "Échec de l'enregistrement de %s : %v",
+	"Failed to apply %s: %v":                                                            "Échec de l'application de %s : %v",
+	"Welcome to git-istage":                                                             "Bienvenue dans git-istage",
+	"%s and %s move between files, and the diff of the one":                             "%s et %s passent d'un fichier à l'autre, et le diff de celui",
+	"under the cursor shows next to the list.":                                          "sous le curseur s'affiche à côté de la liste.",
+	"%s stages or unstages it, %s all of them.":                                         "%s l'indexe ou le désindexe, %s tous les fichiers.",
+	"%s commits what is staged.":                                                        "%s commite ce qui est indexé.",
+	"Hunks and lines":                                                                   "Hunks et lignes",
+	"%s focuses the diff, %s and %s jump between its hunks.":                            "%s active le diff, %s et %s passent d'un hunk à l'autre.",
+	"%s stages or unstages the hunk under the cursor,":                                  "%s indexe ou désindexe le hunk sous le curseur,",
+	"%s selects lines of it to stage on their own.":                                     "%s en sélectionne des lignes à indexer seules.",
+	"%s goes back to the list.":                                                         "%s revient à la liste.",
+	"Finding your way":                                                                  "S'y retrouver",
+	"%s shows every key, the footer the most useful ones.":                              "%s montre toutes les touches, le pied de page les plus utiles.",
+	"%s searches the changes, %s recovers what was discarded.":                          "%s cherche dans les modifications, %s récupère ce qui a été abandonné.",
+	"%s lists the settings to change.":                                                  "%s liste les réglages à modifier.",
+	"Write a default config file to %s?":                                                "Écrire un fichier de configuration par défaut dans %s ?",
+	"A config file":                                                                     "Un fichier de configuration",
+	"Settings are kept in %s,":                                                          "Les réglages sont gardés dans %s,",
+	"changed with %s or git-istage config set.":                                         "modifiés avec %s ou git-istage config set.",
+	"Without one this tour shows again on the next start.":                              "Sans lui, cette visite revient au prochain démarrage.",
+	"Failed to write the config file: %v":                                               "Échec de l'écriture du fichier de configuration : %v",
+	"Wrote the default config to %s":                                                    "Configuration par défaut écrite dans %s",
+	"The index had conflicts at the start, there's no snapshot of it":                   "L'index avait des conflits au démarrage, il n'en existe pas d'instantané",
+	"The index has conflicts, resolve them before restoring it":                         "L'index a des conflits, résolvez-les avant de le restaurer",
+	"The index is as it was at the start":                                               "L'index est tel qu'au démarrage",
+	"Files staged or unstaged since the start":                                          "Fichiers indexés ou désindexés depuis le démarrage",
+	"Put the index of %d file(s) back as it was at the start?":                          "Remettre l'index de %d fichier(s) tel qu'au démarrage ?",
+	"Failed to restore the index: %v":                                                   "Échec de la restauration de l'index : %v",
+	"The index is back as it was at the start":                                          "L'index est de nouveau tel qu'au démarrage",
+	"any key for more, %s to skip":                                                      "une touche pour la suite, %s pour passer",
+	"any key to finish":                                                                 "une touche pour terminer",
+	"Commit %d staged file(s)":                                                          "Commiter %d fichier(s) indexé(s)",
+	"Committing…":                                                                       "Commit en cours…",
+	"Set generator in the [commit] config to generate messages":                         "Définir generator dans [commit] pour générer des messages",
+	"Cancelled generating the message":                                                  "Génération du message annulée",
+	"Message generator failed: %v":                                                      "Le générateur de message a échoué : %v",
+	"The message generator printed nothing":                                             "Le générateur de message n'a rien affiché",
+	"Replace the message with the generated one?":                                       "Remplacer le message par celui généré ?",
+	"Generating the message, %s to cancel":                                              "Génération du message, %s pour annuler",
+	"No co-authors to pick, add some to co_authors in the [commit] config":              "Aucun co-auteur à choisir, en ajouter dans co_authors de [commit]",
+	"Co-author: ":                                                                       "Co-auteur : ",
+	"No hunk to stage here":                                                             "Aucun hunk à indexer ici",
+	"No changed lines selected":                                                         "Aucune ligne modifiée choisie",
+	"Resolve the conflict before staging parts of %s":                                   "Résolvez le conflit avant d'indexer des parties de %s",
+	"Symlinks can only be staged as a whole":                                            "Les liens symboliques ne s'indexent qu'en entier",
+	"Type changes can only be staged as a whole":                                        "Les changements de type ne s'indexent qu'en entier",
+	"Search cleared":                                                                    "Recherche effacée",
+	"Search the diff with %s first":                                                     "Cherchez d'abord dans le diff avec %s",
+	"No match for %q":                                                                   "Aucune occurrence de %q",
+	"Match %d of %d for %q":                                                             "Occurrence %d sur %d de %q",
+	"No change matches %q":                                                              "Aucun changement ne contient %q",
+	", %d diff(s) searched only in their first %d lines":                                ", %d diff(s) cherchés seulement dans leurs %d premières lignes",
+	"Cancelled the search":                                                              "Recherche annulée",
+	"Searching the changes for %q…":                                                     "Recherche de %q dans les modifications…",
+	"… %d unchanged lines":                                                              "… %d lignes inchangées",
+	"Paging is turned off, set core.pager to read diffs in a pager":                     "La pagination est désactivée, définissez core.pager pour lire les diffs dans un pager",
+	"No folded lines on screen":                                                         "Aucune ligne repliée à l'écran",
+	"Unchanged lines further than %d from a change are folded, %s in the diff unfolds them": "Les lignes inchangées à plus de %d d'un changement sont repliées, %s dans le diff les déplie",
+	"Diffs show every unchanged line":                                    "Les diffs montrent toutes les lignes inchangées",
+	"%d hunk(s) matching %q":                                             "%d hunk(s) contenant %q",
+	"All changes, %d file(s)":                                            "Tous les changements, %d fichier(s)",
+	"… %d more file(s) left out":                                         "… %d fichier(s) de plus omis",
+	"Show the line endings again with %s to stage hunks":                 "Réafficher les fins de ligne avec %s pour indexer des hunks",
+	"Files with a diff driver can only be staged as a whole":             "Les fichiers avec un pilote de diff ne s'indexent qu'en entier",
+	"The hunk is cut off, press %s to load the whole diff first":         "Le bloc est coupé, chargez d'abord tout le diff avec %s",
+	"Failed to stage the hunk: %v":                                       "Échec de l'indexation du hunk : %v",
+	"The diff changed, try again":                                        "Le diff a changé, réessayez",
+	"No upstream branch to review against, set one with `git branch -u`": "Aucune branche amont pour la revue, définissez-en une avec `git branch -u`",
+	"No common ancestor of HEAD and %s":                                  "Aucun ancêtre commun entre HEAD et %s",
+	"Changes committed on the branch can't be staged or unstaged":        "Les modifications commitées sur la branche ne s'indexent pas",
+	"Leave the branch review with %s to stage hunks":                     "Quittez la revue de branche avec %s pour indexer des hunks",
+	"Leave the branch review with %s to search the changes":              "Quittez la revue de branche avec %s pour chercher dans les changements",
+	"Leave the branch review with %s to read all the changes":            "Quittez la revue de branche avec %s pour lire tous les changements",
+	"No commits change %s":                                               "Aucun commit ne modifie %s",
+	"No safety stashes or reflog entries to recover from":                "Aucun stash de sécurité ni entrée de reflog à récupérer",
+	"The work tree has the same content as %s":                           "L'arbre de travail a le même contenu que %s",
+	"Overwrite %s in the work tree with its content from %s?":            "Écraser %s dans l'arbre de travail avec son contenu de %s ?",
+	"Failed to restore %s: %v":                                           "Échec de la restauration de %s : %v",
+	"Restored %s from %s":                                                "%s restauré depuis %s",
+	"Aborted, failed to copy the files to the trash: %v":                 "Abandon, échec de la copie des fichiers dans la corbeille : %v",
+	"failed to copy the files to the trash: %v":                          "échec de la copie des fichiers dans la corbeille : %v",
+	"Discarded, the old content is in the trash, %s to recover it":       "Annulé, l'ancien contenu est dans la corbeille, %s pour le récupérer",
+	"Done, the old content is in the trash, %s to recover it":            "Terminé, l'ancien contenu est dans la corbeille, %s pour le récupérer",
+	"%s ago":                     "il y a %s",
+	"discarded or deleted files": "fichiers annulés ou supprimés",
+
+	// Index lock
+	"Remove the index lock?":     "Supprimer le verrou de l'index ?",
+	"The index is locked":        "L'index est verrouillé",
+	"%s exists, created %s ago.": "%s existe, créé il y a %s.",
+	"unknown":                    "un temps inconnu",
+	"Another git process is probably running, e.g. an editor":      "Un autre processus git tourne sans doute, par exemple une",
+	"integration or a commit waiting for its message.":             "intégration d'éditeur ou un commit qui attend son message.",
+	"If no git process is running, the lock is stale, left behind": "Si aucun processus git ne tourne, le verrou est obsolète,",
+	"by a crashed git, and can be removed safely.":                 "laissé par un git planté, et peut être supprimé sans risque.",
+	"Failed to remove %s: %v":                                      "Impossible de supprimer %s : %v",
+	"Removed the index lock, try again":                            "Verrou de l'index supprimé, réessayez",
+
+	// Command line and config errors
+	"Usage: %s [flags] [repository...]":                                          "Usage : %s [options] [dépôt...]",
+	"   or: %s completion %s":                                                    "   ou : %s completion %s",
+	"   or: %s config list|get <name>|set <name> <value>":                        "   ou : %s config list|get <nom>|set <nom> <valeur>",
+	"color theme, one of %v":                                                     "thème de couleurs, parmi %v",
+	"file listing repositories to open as tabs, one per line":                    "fichier listant les dépôts à ouvrir en onglets, un par ligne",
+	"review everything the branch changes since it forked from its upstream":     "passer en revue tout ce que la branche change depuis sa divergence de l'amont",
+	"try the interface on a made up repository kept in memory":                   "essayer l'interface sur un dépôt inventé gardé en mémoire",
+	"record the keys pressed and what git answered into a file, for bug reports": "enregistrer dans un fichier les touches pressées et les réponses de git, pour les rapports de bogue",
+	"replay a session recorded with --record":                                    "rejouer une session enregistrée avec --record",
+	"print the version and exit":                                                 "afficher la version et quitter",
+	"stay open when there's nothing to stage and list changes as they appear":    "rester ouvert quand il n'y a rien à indexer et lister les modifications dès qu'elles apparaissent",
+	"Hunks show their whole function":                                            "Les hunks montrent leur fonction entière",
+	"Hunks show the usual context":                                               "Les hunks montrent le contexte habituel",
+	"The diff shows the changes of the selected section":                         "Le diff montre les changements de la section choisie",
+	"Staged and unstaged changes side by side, %s in the diff to switch sides":   "Changements indexés et non indexés côte à côte, %s dans le diff pour changer de côté",
+	"No changes":                             "Aucun changement",
+	"Diffs leave out CR at the end of lines": "Les diffs ignorent CR en fin de ligne",
+	"Diffs show changes of line endings":     "Les diffs montrent les changements de fin de ligne",
+	"how similar in percent a deleted and an added file must be to show as a rename": "à quel point en pourcentage un fichier supprimé et un fichier ajouté doivent se ressembler pour apparaître comme un renommage",
+	"find_renames must be a percentage from 1 to 100":                                "find_renames doit être un pourcentage de 1 à 100",
+	"A launcher needs a name, a key and a command":                                   "Un lanceur a besoin d'un nom, d'une touche et d'une commande",
+	"Unknown shell %q, completion is available for %v":                               "Shell %q inconnu, la complétion est disponible pour %v",
+	"Unknown paths %q, available paths: %v":                                          "paths %q inconnu, paths disponibles : %v",
+	"Paths from the current directory":                                               "Chemins depuis le répertoire courant",
+	"Absolute paths":                                                                 "Chemins absolus",
+	"Paths from the repository root":                                                 "Chemins depuis la racine du dépôt",
+	"write a profile on exit and time git commands, one of %v":                       "écrire un profil en quittant et chronométrer les commandes git, parmi %v",
+	"Error:":                                     "Erreur :",
+	"Error reading config:":                      "Erreur de lecture de la configuration :",
+	"Error opening log file:":                    "Erreur d'ouverture du journal :",
+	"Error reading workspace:":                   "Erreur de lecture de l'espace de travail :",
+	"Error reading session:":                     "Erreur de lecture de la session :",
+	"Error writing session:":                     "Erreur d'écriture de la session :",
+	"Recorded the session to %s":                 "Session enregistrée dans %s",
+	"git-istage crashed: %v":                     "git-istage a planté : %v",
+	"Last git commands:":                         "Dernières commandes git :",
+	"Unsupported session version %d":             "Version de session non prise en charge : %d",
+	"Error writing profile:":                     "Erreur d'écriture du profil :",
+	"Wrote the %s profile to %s":                 "Profil %s écrit dans %s",
+	"%s: %d run(s), %s in total, %s at most":     "%s : %d exécution(s), %s au total, %s au plus",
+	"Unknown profile %q, available profiles: %v": "Profil %q inconnu, profils disponibles : %v",
+	"Error running program:":                     "Erreur d'exécution :",
+	"No changes to stage or unstage.":            "Aucune modification à indexer ou désindexer.",
+	"Not inside a git repository: %s":            "Pas dans un dépôt git : %s",
+	"git isn't installed, or isn't in PATH. Install it from https://git-scm.com/downloads and try again.": "git n'est pas installé, ou pas dans le PATH. Installez-le depuis https://git-scm.com/downloads et réessayez.",
+	"git %v is too old, git-istage needs %v or later. Upgrade it from https://git-scm.com/downloads.":     "git %v est trop ancien, git-istage a besoin de %v ou plus récent. Mettez-le à jour depuis https://git-scm.com/downloads.",
+	"Unknown theme %q, available themes: %v":                                                              "Thème %q inconnu, thèmes disponibles : %v",
+	"Unknown language %q, available languages: %v":                                                        "Langue %q inconnue, langues disponibles : %v",
+	"Unknown icon set %q, available icon sets: %v":                                                        "Jeu d'icônes %q inconnu, jeux disponibles : %v",
+	"Invalid branch_pattern in [commit]: %v":                                                              "branch_pattern invalide dans [commit] : %v",
+	"Invalid pattern in [secrets]: %v":                                                                    "Motif invalide dans [secrets] : %v",
+	"Invalid pattern %q in [formatters]: %v":                                                              "Motif %q invalide dans [formatters] : %v",
+	"No formatter for %s, add one to [formatters] in the config":                                          "Aucun formateur pour %s, en ajouter un dans [formatters] de la configuration",
+	"%s was deleted, there's nothing to format":                                                           "%s a été supprimé, il n'y a rien à formater",
+	"Formatting %s…":               "Formatage de %s…",
+	"Formatting %s failed: %v":     "Échec du formatage de %s : %v",
+	"Formatted %s":                 "%s formaté",
+	"Move %s to: ":                 "Déplacer %s vers : ",
+	"%s is outside the repository": "%s est en dehors du dépôt",
+	"Failed to move %s: %v":        "Impossible de déplacer %s : %v",
+	"Moved %s to %s":               "%s déplacé vers %s",
+	"New branch from HEAD: ":       "Nouvelle branche depuis HEAD : ",
+	"Failed to create %s: %v":      "Impossible de créer %s : %v",
+	"Created and switched to %s":   "%s créée, basculé dessus",
+	"Already on %s":                "Déjà sur %s",
+	"Finish or abort the %s in progress before switching branches":          "Terminez ou annulez %s en cours avant de changer de branche",
+	"Your changes conflict with %s. Stash them, switch and pop them there?": "Vos modifications entrent en conflit avec %s. Les remiser, basculer et les réappliquer là-bas ?",
+	"Failed to switch to %s: %v":                                            "Impossible de basculer sur %s : %v",
+	"Switched to %s, the changes came along":                                "Basculé sur %s, les modifications ont suivi",
+	"Switched to %s":                                                        "Basculé sur %s",
+	"Failed to stash the changes: %s":                                       "Impossible de remiser les modifications : %s",
+	"Switched to %s and popped the changes":                                 "Basculé sur %s et modifications réappliquées",
+	"No local branches yet, press %s to create one":                         "Aucune branche locale, appuyez sur %s pour en créer une",
+	"Local branches":                                                        "Branches locales",
+	"no upstream":                                                           "pas de branche amont",
+	"up to date":                                                            "à jour",
+	"Fetching…":                                                             "Récupération…",
+	"Failed to fetch: %v":                                                   "Échec de la récupération : %v",
+	"Fetching needs credentials, enter them in the terminal?":               "La récupération demande des identifiants, les saisir dans le terminal ?",
+	"Push %s and run %s?":                                                   "Pousser %s et lancer %s ?",
+	"Pushing…":                                                              "Envoi…",
+	"Pushing needs credentials, enter them in the terminal?":                "L'envoi demande des identifiants, les saisir dans le terminal ?",
+	"Failed to push: %v":                                                    "Échec de l'envoi : %v",
+	"Pushed %s":                                                             "%s envoyé",
+	"Staged changes on %s":                                                  "Changements indexés sur %s",
+	"(renamed from `%s`)":                                                   "(renommé depuis `%s`)",
+	"(new)":                                                                 "(nouveau)",
+	"(deleted)":                                                             "(supprimé)",
+	"%d file(s) changed, +%d -%d":                                           "%d fichier(s) modifié(s), +%d -%d",
+	"Nothing staged to summarize":                                           "Rien d'indexé à résumer",
+	"Export the summary to a file, or to the clipboard if empty: ":                "Exporter le résumé vers un fichier, ou le presse-papiers si vide : ",
+	"Failed to copy the summary: %v":                                              "Échec de la copie du résumé : %v",
+	"Copied the summary to the clipboard":                                         "Résumé copié dans le presse-papiers",
+	"Failed to export the summary: %v":                                            "Échec de l'export du résumé : %v",
+	"Exported the summary to %s":                                                  "Résumé exporté vers %s",
+	"Overwrite %s?":                                                               "Écraser %s ?",
+	"The content of %s isn't available locally, fetch it from %s?":                "Le contenu de %s n'est pas disponible localement, le récupérer depuis %s ?",
+	"Fetching the content from %s…":                                               "Récupération du contenu depuis %s…",
+	"Failed to fetch the content: %v":                                             "Échec de la récupération du contenu : %v",
+	"Fetched the content from %s":                                                 "Contenu récupéré depuis %s",
+	"Invalid git_timeout %q, write it like \"30s\" or \"2m\", or \"0\" for none":  "git_timeout %q invalide, écrivez-le comme \"30s\" ou \"2m\", ou \"0\" pour aucun",
+	"%v, raise git_timeout in the config if it needs longer":                      "%v, augmentez git_timeout dans la configuration s'il faut plus de temps",
+	"Not available locally in this partial clone":                                 "Non disponible localement dans ce clone partiel",
+	"Fetched, the branch has no upstream to compare with":                         "Récupéré, la branche n'a pas de branche amont à comparer",
+	"Fetched, %d commit(s) behind %s, pull before committing":                     "Récupéré, %d commit(s) de retard sur %s, faites un pull avant de commiter",
+	"Fetched, nothing new on %s":                                                  "Récupéré, rien de nouveau sur %s",
+	"Range-diff of: ":                                                             "Range-diff de : ",
+	"Search the changes for: ":                                                    "Chercher dans les changements : ",
+	"Failed to range-diff %s: %v":                                                 "Échec du range-diff de %s : %v",
+	"No commits to compare in %s":                                                 "Aucun commit à comparer dans %s",
+	"Range-diff of %s":                                                            "Range-diff de %s",
+	"Invalid pattern %q in [commit.default_trailers]: %v":                         "Motif %q invalide dans [commit.default_trailers] : %v",
+	"Invalid trailer %q in [commit.default_trailers], write it as \"Key: value\"": "Trailer %q invalide dans [commit.default_trailers], écrivez-le sous la forme \"Clé: valeur\"",
+	"No trailers to fill in, add some to trailers in the [commit] config":         "Aucun trailer à remplir, en ajouter dans trailers de [commit]",
+	"Failed to add the trailers: %v":                                              "Impossible d'ajouter les trailers : %v",
+	"Trailers, empty ones are left out":                                           "Trailers, les vides sont ignorés",
+	"Loading issues…":                                                             "Chargement des tickets…",
+	"Issue: #":                                                                    "Ticket : #",
+	"No open issues to complete with":                                             "Aucun ticket ouvert pour compléter",
+	"Failed to load issues: %v":                                                   "Impossible de charger les tickets : %v",
+	"Unknown gitmoji %q in [commit], use %q or %q":                                "gitmoji %q inconnu dans [commit], utilisez %q ou %q",
+	"Set gitmoji in the [commit] config to pick one":                              "Définir gitmoji dans [commit] pour en choisir un",
+	"Gitmoji: ":                       "Gitmoji : ",
+	"Author: ":                        "Auteur : ",
+	"Date: ":                          "Date : ",
+	"Name <email>, empty for yours":   "Nom <e-mail>, vide pour le vôtre",
+	"2024-05-01 14:30, empty for now": "2024-05-01 14:30, vide pour maintenant",
+	"Author and date of the commit":   "Auteur et date du commit",
+	"Invalid author %q, write it as \"Name <email>\"": "Auteur %q invalide, écrivez-le sous la forme \"Nom <e-mail>\"",
+	"Invalid date %q, write it like 2024-05-01 14:30": "Date %q invalide, écrivez-la comme 2024-05-01 14:30",
+	"The date %s is in the future":                    "La date %s est dans le futur",
+	"Possible secrets in the changes to stage":        "Secrets possibles dans les modifications à indexer",
+	"and %d more": "et %d de plus",
+	"Once committed, they stay in the history even if removed later.": "Une fois commités, ils restent dans l'historique même s'ils sont supprimés ensuite.",
+	"Stage %d possible secret(s) anyway?":                             "Indexer quand même %d secret(s) possible(s) ?",
+	"Unknown action %q in [keys]":                                     "Action %q inconnue dans [keys]",
+	"No keys given for %q in [keys]":                                  "Aucune touche pour %q dans [keys]",
+}
@@ -0,0 +1,61 @@
+package main
+
+import "strings"
+
+// The index written as a tree, empty if it can't be, like with conflicts
+func writeIndexTree(r repo) string {
+	return trimmedOutput(r.git("write-tree").Output())
+}
+
+// Paths whose entry in the index differs from the tree, from the root
+func indexChangesSince(r repo, tree string) []string {
+	output, err := r.atRoot().git("diff-index", "--cached", "-z", "--name-only", "--no-renames", tree, "--").Output()
+	if err != nil {
+		return nil
+	}
+	return strings.FieldsFunc(string(output), func(c rune) bool { return c == 0 })
+}
+
+// Ask to put the index back to the tree it was written as when the session
+// started, undoing all the staging and unstaging since. Only the paths that
+// changed are restored, so the flags of the others, like skip-worktree, stay.
+func (m *model) askToRestoreIndex() {
+	tree := m.sessionIndex
+	current := writeIndexTree(m.repo)
+	switch {
+	case tree == "":
+		m.status = tr("The index had conflicts at the start, there's no snapshot of it")
+		return
+	case current == "":
+		m.status = tr("The index has conflicts, resolve them before restoring it")
+		return
+	case current == tree:
+		m.status = tr("The index is as it was at the start")
+		return
+	}
+	paths := indexChangesSince(m.repo, tree)
+	details := []string{tr("Files staged or unstaged since the start"), ""}
+	for _, path := range paths[:min(len(paths), pathspecShown)] {
+		details = append(details, m.repo.relPath(path))
+	}
+	if len(paths) > pathspecShown {
+		details = append(details, tr("and %d more", len(paths)-pathspecShown))
+	}
+	m.confirm = &confirmation{
+		message: tr("Put the index of %d file(s) back as it was at the start?", len(paths)),
+		details: details,
+		onYes: func(m *model) {
+			err := m.repo.atRoot().runInput(strings.Join(paths, "\x00"),
+				"restore", "--staged", "--source="+tree, "--pathspec-from-file=-", "--pathspec-file-nul")
+			if m.handleIndexLock(err) {
+				return
+			} else if err != nil {
+				m.status = tr("Failed to restore the index: %v", err)
+				return
+			}
+			m.reload()
+			m.loadDiff()
+			m.status = tr("The index is back as it was at the start")
+		},
+	}
+}
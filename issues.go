@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os/exec"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/hzqtc/git-istage/gitx"
+)
+
+// An issue offered when typing # in the commit message
+type issue struct {
+	number string
+	title  string
+}
+
+func (i issue) String() string {
+	return i.number + " " + i.title
+}
+
+type issuesLoadedMsg struct {
+	root   string
+	issues []issue
+	err    error
+}
+
+// Issues from the output of the issues command: a JSON array of objects with
+// a number, like `gh issue list --json number,title` prints, or an iid like
+// glab's, or lines of a number and a title
+func parseIssues(output []byte) ([]issue, error) {
+	output = bytes.TrimSpace(output)
+	if !bytes.HasPrefix(output, []byte("[")) {
+		var issues []issue
+		for _, line := range splitDiffLines(string(output)) {
+			number, title, _ := strings.Cut(strings.TrimSpace(line), " ")
+			if number = strings.TrimPrefix(number, "#"); number != "" {
+				issues = append(issues, issue{number, strings.TrimSpace(title)})
+			}
+		}
+		return issues, nil
+	}
+	var objects []struct {
+		Number json.Number `json:"number"`
+		IID    json.Number `json:"iid"`
+		Title  string      `json:"title"`
+	}
+	if err := json.Unmarshal(output, &objects); err != nil {
+		return nil, err
+	}
+	var issues []issue
+	for _, o := range objects {
+		number := o.Number.String()
+		if number == "" {
+			number = o.IID.String()
+		}
+		issues = append(issues, issue{number, o.Title})
+	}
+	return issues, nil
+}
+
+// Run the issues command with sh in the repository root, in the background
+// since it usually asks a server
+func loadIssues(r repo) tea.Cmd {
+	return func() tea.Msg {
+		cmd := exec.Command("sh", "-c", cfg.Commit.Issues)
+		cmd.Dir = r.root
+		cmd.WaitDelay = gitx.KillWaitDelay
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		output, err := cmd.Output()
+		if err != nil {
+			if msg := firstLine(stderr.String()); msg != "" {
+				err = errors.New(msg)
+			}
+			return issuesLoadedMsg{r.root, nil, err}
+		}
+		issues, err := parseIssues(output)
+		return issuesLoadedMsg{r.root, issues, err}
+	}
+}
+
+// After typing # in the message, pick an issue to complete it with, once
+// the issues are loaded the first time
+func (m *model) completeIssue() tea.Cmd {
+	if m.issues == nil {
+		if !m.loadingIssues {
+			m.loadingIssues = true
+			m.issueTyped = m.editor.Value()
+			m.status = tr("Loading issues…")
+			return loadIssues(m.repo)
+		}
+		return nil
+	}
+	if len(m.issues) == 0 {
+		m.status = tr("No open issues to complete with")
+		return nil
+	}
+	var candidates []string
+	for _, i := range m.issues {
+		candidates = append(candidates, i.String())
+	}
+	m.picker = newPicker(tr("Issue: #"), candidates, func(m *model, choice string) {
+		m.editor.InsertString(choice)
+	})
+	return nil
+}
+
+func (m *model) issuesLoaded(msg issuesLoadedMsg) {
+	m.loadingIssues = false
+	if msg.err != nil {
+		m.status = tr("Failed to load issues: %v", msg.err)
+		return
+	}
+	m.issues = msg.issues
+	if m.issues == nil {
+		m.issues = []issue{}
+	}
+	// Unless something was typed after the # meanwhile
+	if m.mode == commitMode && m.picker == nil && m.editor != nil && m.editor.Value() == m.issueTyped {
+		m.completeIssue()
+	}
+}
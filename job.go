@@ -0,0 +1,127 @@
+package main
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Number of paths handed to git at once by bulk operations, small enough
+// for the progress to move and cancelling to take effect quickly
+const jobBatchSize = 20
+
+// A bulk operation run in the background one batch of files at a time,
+// with its progress in the status line
+type job struct {
+	id        int
+	title     string // e.g. "Staging", followed by the progress
+	steps     []jobStep
+	next      int
+	done      int // files processed so far
+	total     int
+	cancelled bool
+	err       error
+	onDone    func(m *model) // called once the job stops, before the reload
+	rollback  []fileEntry    // the list before it was updated ahead of the job, if it was
+}
+
+type jobStep struct {
+	files int
+	run   func() error
+}
+
+type jobStepMsg struct {
+	root string // repository the job runs in, to route the message to its tab
+	id   int
+	err  error
+}
+
+var lastJobID int
+
+// Split paths into steps that each run a git command on one batch
+func batchSteps(paths []string, run func(paths []string) error) []jobStep {
+	var steps []jobStep
+	for len(paths) > 0 {
+		batch := paths[:min(jobBatchSize, len(paths))]
+		paths = paths[len(batch):]
+		steps = append(steps, jobStep{len(batch), func() error { return run(batch) }})
+	}
+	return steps
+}
+
+func (m *model) startJob(title string, steps []jobStep, onDone func(m *model)) tea.Cmd {
+	lastJobID++
+	m.job = &job{id: lastJobID, title: title, steps: steps, onDone: onDone}
+	for _, s := range steps {
+		m.job.total += s.files
+	}
+	return m.runJobStep()
+}
+
+func (m *model) runJobStep() tea.Cmd {
+	if m.job.next >= len(m.job.steps) || m.job.cancelled {
+		m.finishJob()
+		return nil
+	}
+	root, id, step := m.repo.root, m.job.id, m.job.steps[m.job.next]
+	return func() tea.Msg {
+		return jobStepMsg{root, id, step.run()}
+	}
+}
+
+func (m *model) jobStepFinished(msg jobStepMsg) tea.Cmd {
+	if m.job == nil || m.job.id != msg.id {
+		return nil
+	}
+	m.job.next++
+	if msg.err != nil && m.job.cancelled {
+		// Killed while running, that batch may be half done
+		return m.runJobStep()
+	}
+	m.job.done += m.job.steps[m.job.next-1].files
+	if msg.err != nil && m.job.err == nil {
+		m.job.err = msg.err
+	}
+	return m.runJobStep()
+}
+
+// Stop the job, killing the git command of the current batch
+func (m *model) cancelJob() {
+	m.job.cancelled = true
+	cancelGitCommands(m.repo.root)
+}
+
+func (m *model) finishJob() {
+	j := m.job
+	m.job = nil
+	if j.rollback != nil && (j.err != nil || j.cancelled) {
+		m.keepingCursor(func() { m.files = j.rollback })
+	}
+	if j.onDone != nil {
+		j.onDone(m)
+	}
+	switch {
+	case m.handleIndexLock(j.err):
+	case j.err != nil:
+		m.status = tr("%s failed: %v", j.title, j.err)
+	case j.cancelled:
+		m.status = tr("%s cancelled after %d of %d file(s)", j.title, j.done, j.total)
+	}
+	m.reload()
+	m.loadDiff()
+}
+
+// Progress line like "Staging [######----] 12/20 file(s)"
+func (m model) jobProgress() string {
+	const barWidth = 20
+	filled := 0
+	if m.job.total > 0 {
+		filled = barWidth * m.job.done / m.job.total
+	}
+	bar := stagedStyle.Render(strings.Repeat("#", filled)) + unstagedStyle.Render(strings.Repeat("-", barWidth-filled))
+	line := tr("%s [%s] %d/%d file(s)", m.job.title, bar, m.job.done, m.job.total)
+	if m.job.cancelled {
+		return line + tr(", cancelling…")
+	}
+	return line + tr(", %s to cancel", m.keys.cancel.Help().Key)
+}
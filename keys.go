@@ -0,0 +1,533 @@
+package main
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+type viewMode int
+
+const (
+	listMode viewMode = iota
+	diffMode
+	flagsMode
+	logMode
+	commitMode
+	historyMode
+	recoveryMode
+	branchesMode
+	rangeDiffMode
+	recentMode
+	cleanMode
+	searchMode
+	changeSetMode
+	settingsMode
+)
+
+// Keys are named as bubbletea reports them, a sequence separated by spaces.
+// The help is how the keys are shown in the footer and the help view.
+type keyBinding struct {
+	key.Binding
+}
+
+func (b keyBinding) matches(key string) bool {
+	return slices.Contains(b.Keys(), key)
+}
+
+type keyMap struct {
+	quit            keyBinding
+	up              keyBinding
+	down            keyBinding
+	nextColumn      keyBinding
+	prevColumn      keyBinding
+	goToFile        keyBinding
+	nextDir         keyBinding
+	prevDir         keyBinding
+	toggle          keyBinding
+	toggleAll       keyBinding
+	stageMatching   keyBinding
+	toggleNext      keyBinding
+	togglePrev      keyBinding
+	focusDiff       keyBinding
+	focusList       keyBinding
+	scrollUp        keyBinding
+	scrollDown      keyBinding
+	pageUp          keyBinding
+	pageDown        keyBinding
+	fullScreen      keyBinding
+	functionContext keyBinding
+	dualDiff        keyBinding
+	switchSide      keyBinding
+	preview         keyBinding
+	ignoreCR        keyBinding
+	format          keyBinding
+	useOurs         keyBinding
+	useTheirs       keyBinding
+	mergetool       keyBinding
+	discard         keyBinding
+	discardAll      keyBinding
+	clean           keyBinding
+	cleanIgnored    keyBinding
+	deleteSelected  keyBinding
+	toggleExec      keyBinding
+	move            keyBinding
+	popStash        keyBinding
+	continueOp      keyBinding
+	abortOp         keyBinding
+	skipOp          keyBinding
+	showFlags       keyBinding
+	fileInfo        keyBinding
+	pathDisplay     keyBinding
+	assumeUnchanged keyBinding
+	skipWorktree    keyBinding
+	stageMode       keyBinding
+	stageContent    keyBinding
+	nextTab         keyBinding
+	prevTab         keyBinding
+	confirmYes      keyBinding
+	confirmNo       keyBinding
+	cancel          keyBinding
+	showLog         keyBinding
+	top             keyBinding
+	bottom          keyBinding
+	nextHunk        keyBinding
+	prevHunk        keyBinding
+	bookmark        keyBinding
+	bookmarkedOnly  keyBinding
+	note            keyBinding
+	commit          keyBinding
+	commitSubmit    keyBinding
+	commitInEditor  keyBinding
+	generateMessage keyBinding
+	coAuthor        keyBinding
+	trailers        keyBinding
+	gitmoji         keyBinding
+	authorDate      keyBinding
+	toggleHunk      keyBinding
+	selectLines     keyBinding
+	search          keyBinding
+	nextMatch       keyBinding
+	prevMatch       keyBinding
+	searchAll       keyBinding
+	goToHunk        keyBinding
+	fold            keyBinding
+	unfold          keyBinding
+	changeSet       keyBinding
+	nextFile        keyBinding
+	prevFile        keyBinding
+	export          keyBinding
+	pager           keyBinding
+	review          keyBinding
+	history         keyBinding
+	recover         keyBinding
+	restoreIndex    keyBinding
+	branches        keyBinding
+	switchBranch    keyBinding
+	newBranch       keyBinding
+	fetch           keyBinding
+	rangeDiff       keyBinding
+	recentCommits   keyBinding
+	settings        keyBinding
+	editSetting     keyBinding
+	restore         keyBinding
+	loadFullDiff    keyBinding
+	help            keyBinding
+	launchers       []launcher
+}
+
+var defaultKeyMap = keyMap{
+	quit:            keyBinding{key.NewBinding(key.WithKeys("ctrl+c", "q"), key.WithHelp("q", "quit"))},
+	up:              keyBinding{key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("k/↑", "up"))},
+	down:            keyBinding{key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("j/↓", "down"))},
+	nextColumn:      keyBinding{key.NewBinding(key.WithKeys("right"), key.WithHelp("→", "next column"))},
+	prevColumn:      keyBinding{key.NewBinding(key.WithKeys("left"), key.WithHelp("←", "previous column"))},
+	goToFile:        keyBinding{key.NewBinding(key.WithKeys("1", "2", "3", "4", "5", "6", "7", "8", "9", "0"), key.WithHelp("1-9", "go to file"))},
+	nextDir:         keyBinding{key.NewBinding(key.WithKeys("}"), key.WithHelp("}", "next directory"))},
+	prevDir:         keyBinding{key.NewBinding(key.WithKeys("{"), key.WithHelp("{", "previous directory"))},
+	toggle:          keyBinding{key.NewBinding(key.WithKeys("space"), key.WithHelp("space", "toggle"))},
+	toggleAll:       keyBinding{key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "toggle all"))},
+	stageMatching:   keyBinding{key.NewBinding(key.WithKeys("+"), key.WithHelp("+", "stage matching"))},
+	toggleNext:      keyBinding{key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", "toggle and next"))},
+	togglePrev:      keyBinding{key.NewBinding(key.WithKeys("shift+tab"), key.WithHelp("shift+tab", "toggle and previous"))},
+	focusDiff:       keyBinding{key.NewBinding(key.WithKeys("enter", "l", "right"), key.WithHelp("enter", "view diff"))},
+	focusList:       keyBinding{key.NewBinding(key.WithKeys("esc", "h", "left"), key.WithHelp("esc", "back"))},
+	scrollUp:        keyBinding{key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("k/↑", "scroll up"))},
+	scrollDown:      keyBinding{key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("j/↓", "scroll down"))},
+	pageUp:          keyBinding{key.NewBinding(key.WithKeys("pgup", "ctrl+u"), key.WithHelp("ctrl+u", "page up"))},
+	pageDown:        keyBinding{key.NewBinding(key.WithKeys("pgdown", "ctrl+d"), key.WithHelp("ctrl+d", "page down"))},
+	fullScreen:      keyBinding{key.NewBinding(key.WithKeys("f"), key.WithHelp("f", "full-screen diff"))},
+	functionContext: keyBinding{key.NewBinding(key.WithKeys("F"), key.WithHelp("F", "function context"))},
+	dualDiff:        keyBinding{key.NewBinding(key.WithKeys("|"), key.WithHelp("|", "dual diff"))},
+	preview:         keyBinding{key.NewBinding(key.WithKeys("V"), key.WithHelp("V", "preview untracked"))},
+	switchSide:      keyBinding{key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", "switch side"))},
+	ignoreCR:        keyBinding{key.NewBinding(key.WithKeys("e"), key.WithHelp("e", "ignore CR at EOL"))},
+	format:          keyBinding{key.NewBinding(key.WithKeys("="), key.WithHelp("=", "format"))},
+	useOurs:         keyBinding{key.NewBinding(key.WithKeys("o"), key.WithHelp("o", "use ours"))},
+	useTheirs:       keyBinding{key.NewBinding(key.WithKeys("t"), key.WithHelp("t", "use theirs"))},
+	mergetool:       keyBinding{key.NewBinding(key.WithKeys("T"), key.WithHelp("T", "mergetool"))},
+	discard:         keyBinding{key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "discard"))},
+	discardAll:      keyBinding{key.NewBinding(key.WithKeys("D"), key.WithHelp("D", "discard all"))},
+	clean:           keyBinding{key.NewBinding(key.WithKeys("X"), key.WithHelp("X", "clean"))},
+	cleanIgnored:    keyBinding{key.NewBinding(key.WithKeys("i"), key.WithHelp("i", "ignored files"))},
+	deleteSelected:  keyBinding{key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "delete selected"))},
+	toggleExec:      keyBinding{key.NewBinding(key.WithKeys("x"), key.WithHelp("x", "toggle executable"))},
+	move:            keyBinding{key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "move"))},
+	popStash:        keyBinding{key.NewBinding(key.WithKeys("P"), key.WithHelp("P", "pop stash"))},
+	continueOp:      keyBinding{key.NewBinding(key.WithKeys("alt+c"), key.WithHelp("alt+c", "continue"))},
+	abortOp:         keyBinding{key.NewBinding(key.WithKeys("alt+a"), key.WithHelp("alt+a", "abort"))},
+	skipOp:          keyBinding{key.NewBinding(key.WithKeys("alt+s"), key.WithHelp("alt+s", "skip"))},
+	showFlags:       keyBinding{key.NewBinding(key.WithKeys("I"), key.WithHelp("I", "hidden files"))},
+	fileInfo:        keyBinding{key.NewBinding(key.WithKeys("i"), key.WithHelp("i", "file info"))},
+	pathDisplay:     keyBinding{key.NewBinding(key.WithKeys("p"), key.WithHelp("p", "path display"))},
+	assumeUnchanged: keyBinding{key.NewBinding(key.WithKeys("A"), key.WithHelp("A", "assume-unchanged"))},
+	skipWorktree:    keyBinding{key.NewBinding(key.WithKeys("W"), key.WithHelp("W", "skip-worktree"))},
+	stageMode:       keyBinding{key.NewBinding(key.WithKeys("m"), key.WithHelp("m", "stage mode only"))},
+	stageContent:    keyBinding{key.NewBinding(key.WithKeys("M"), key.WithHelp("M", "stage content only"))},
+	nextTab:         keyBinding{key.NewBinding(key.WithKeys("ctrl+n", "ctrl+right"), key.WithHelp("ctrl+n", "next repo"))},
+	prevTab:         keyBinding{key.NewBinding(key.WithKeys("ctrl+p", "ctrl+left"), key.WithHelp("ctrl+p", "previous repo"))},
+	confirmYes:      keyBinding{key.NewBinding(key.WithKeys("y", "Y"), key.WithHelp("y", "yes"))},
+	confirmNo:       keyBinding{key.NewBinding(key.WithKeys("n", "N", "esc"), key.WithHelp("n", "no"))},
+	cancel:          keyBinding{key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "cancel"))},
+	showLog:         keyBinding{key.NewBinding(key.WithKeys("L"), key.WithHelp("L", "command log"))},
+	top:             keyBinding{key.NewBinding(key.WithKeys("g g", "home"), key.WithHelp("gg", "top"))},
+	bottom:          keyBinding{key.NewBinding(key.WithKeys("G", "end"), key.WithHelp("G", "bottom"))},
+	nextHunk:        keyBinding{key.NewBinding(key.WithKeys("]"), key.WithHelp("]", "next hunk"))},
+	prevHunk:        keyBinding{key.NewBinding(key.WithKeys("["), key.WithHelp("[", "previous hunk"))},
+	bookmark:        keyBinding{key.NewBinding(key.WithKeys("b"), key.WithHelp("b", "bookmark"))},
+	bookmarkedOnly:  keyBinding{key.NewBinding(key.WithKeys("B"), key.WithHelp("B", "bookmarked only"))},
+	note:            keyBinding{key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "note"))},
+	commit:          keyBinding{key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "commit"))},
+	commitSubmit:    keyBinding{key.NewBinding(key.WithKeys("ctrl+s"), key.WithHelp("ctrl+s", "commit"))},
+	commitInEditor:  keyBinding{key.NewBinding(key.WithKeys("C"), key.WithHelp("C", "commit in editor"))},
+	generateMessage: keyBinding{key.NewBinding(key.WithKeys("ctrl+g"), key.WithHelp("ctrl+g", "generate message"))},
+	coAuthor:        keyBinding{key.NewBinding(key.WithKeys("ctrl+o"), key.WithHelp("ctrl+o", "co-author"))},
+	trailers:        keyBinding{key.NewBinding(key.WithKeys("ctrl+t"), key.WithHelp("ctrl+t", "trailers"))},
+	gitmoji:         keyBinding{key.NewBinding(key.WithKeys("alt+e"), key.WithHelp("alt+e", "gitmoji"))},
+	authorDate:      keyBinding{key.NewBinding(key.WithKeys("alt+a"), key.WithHelp("alt+a", "author/date"))},
+	toggleHunk:      keyBinding{key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "toggle hunk"))},
+	selectLines:     keyBinding{key.NewBinding(key.WithKeys("v"), key.WithHelp("v", "select lines"))},
+	search:          keyBinding{key.NewBinding(key.WithKeys("/"), key.WithHelp("/", "search"))},
+	nextMatch:       keyBinding{key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "next match"))},
+	prevMatch:       keyBinding{key.NewBinding(key.WithKeys("N"), key.WithHelp("N", "previous match"))},
+	searchAll:       keyBinding{key.NewBinding(key.WithKeys("/"), key.WithHelp("/", "search changes"))},
+	goToHunk:        keyBinding{key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "go to hunk"))},
+	fold:            keyBinding{key.NewBinding(key.WithKeys("z"), key.WithHelp("z", "fold context"))},
+	unfold:          keyBinding{key.NewBinding(key.WithKeys("o"), key.WithHelp("o", "unfold"))},
+	changeSet:       keyBinding{key.NewBinding(key.WithKeys("w"), key.WithHelp("w", "all changes"))},
+	nextFile:        keyBinding{key.NewBinding(key.WithKeys("}"), key.WithHelp("}", "next file"))},
+	prevFile:        keyBinding{key.NewBinding(key.WithKeys("{"), key.WithHelp("{", "previous file"))},
+	export:          keyBinding{key.NewBinding(key.WithKeys("E"), key.WithHelp("E", "export summary"))},
+	pager:           keyBinding{key.NewBinding(key.WithKeys("O"), key.WithHelp("O", "pager"))},
+	review:          keyBinding{key.NewBinding(key.WithKeys("R"), key.WithHelp("R", "review branch"))},
+	history:         keyBinding{key.NewBinding(key.WithKeys("H"), key.WithHelp("H", "file history"))},
+	recover:         keyBinding{key.NewBinding(key.WithKeys("U"), key.WithHelp("U", "recover"))},
+	restoreIndex:    keyBinding{key.NewBinding(key.WithKeys("ctrl+z"), key.WithHelp("ctrl+z", "restore index"))},
+	restore:         keyBinding{key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "restore"))},
+	branches:        keyBinding{key.NewBinding(key.WithKeys("S"), key.WithHelp("S", "branches"))},
+	switchBranch:    keyBinding{key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "switch"))},
+	newBranch:       keyBinding{key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "new branch"))},
+	fetch:           keyBinding{key.NewBinding(key.WithKeys("ctrl+f"), key.WithHelp("ctrl+f", "fetch"))},
+	rangeDiff:       keyBinding{key.NewBinding(key.WithKeys("ctrl+r"), key.WithHelp("ctrl+r", "range-diff"))},
+	recentCommits:   keyBinding{key.NewBinding(key.WithKeys("N"), key.WithHelp("N", "recent commits"))},
+	settings:        keyBinding{key.NewBinding(key.WithKeys("alt+o"), key.WithHelp("alt+o", "settings"))},
+	editSetting:     keyBinding{key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "change"))},
+	loadFullDiff:    keyBinding{key.NewBinding(key.WithKeys("L"), key.WithHelp("L", "load whole diff"))},
+	help:            keyBinding{key.NewBinding(key.WithKeys("?"), key.WithHelp("?", "help"))},
+}
+
+// Bindings by the name they're configured with in the [keys] table
+func (k *keyMap) byName() map[string]*keyBinding {
+	return map[string]*keyBinding{
+		"quit":             &k.quit,
+		"up":               &k.up,
+		"down":             &k.down,
+		"next_column":      &k.nextColumn,
+		"prev_column":      &k.prevColumn,
+		"go_to_file":       &k.goToFile,
+		"next_dir":         &k.nextDir,
+		"prev_dir":         &k.prevDir,
+		"toggle":           &k.toggle,
+		"toggle_all":       &k.toggleAll,
+		"stage_matching":   &k.stageMatching,
+		"toggle_next":      &k.toggleNext,
+		"toggle_prev":      &k.togglePrev,
+		"focus_diff":       &k.focusDiff,
+		"focus_list":       &k.focusList,
+		"scroll_up":        &k.scrollUp,
+		"scroll_down":      &k.scrollDown,
+		"page_up":          &k.pageUp,
+		"page_down":        &k.pageDown,
+		"full_screen":      &k.fullScreen,
+		"function_context": &k.functionContext,
+		"dual_diff":        &k.dualDiff,
+		"switch_side":      &k.switchSide,
+		"preview":          &k.preview,
+		"ignore_cr":        &k.ignoreCR,
+		"format":           &k.format,
+		"use_ours":         &k.useOurs,
+		"use_theirs":       &k.useTheirs,
+		"mergetool":        &k.mergetool,
+		"discard":          &k.discard,
+		"discard_all":      &k.discardAll,
+		"clean":            &k.clean,
+		"clean_ignored":    &k.cleanIgnored,
+		"delete_selected":  &k.deleteSelected,
+		"toggle_exec":      &k.toggleExec,
+		"move":             &k.move,
+		"pop_stash":        &k.popStash,
+		"continue":         &k.continueOp,
+		"abort":            &k.abortOp,
+		"skip":             &k.skipOp,
+		"show_flags":       &k.showFlags,
+		"file_info":        &k.fileInfo,
+		"path_display":     &k.pathDisplay,
+		"assume_unchanged": &k.assumeUnchanged,
+		"skip_worktree":    &k.skipWorktree,
+		"stage_mode":       &k.stageMode,
+		"stage_content":    &k.stageContent,
+		"next_tab":         &k.nextTab,
+		"prev_tab":         &k.prevTab,
+		"confirm_yes":      &k.confirmYes,
+		"confirm_no":       &k.confirmNo,
+		"cancel":           &k.cancel,
+		"show_log":         &k.showLog,
+		"top":              &k.top,
+		"bottom":           &k.bottom,
+		"next_hunk":        &k.nextHunk,
+		"prev_hunk":        &k.prevHunk,
+		"bookmark":         &k.bookmark,
+		"bookmarked_only":  &k.bookmarkedOnly,
+		"note":             &k.note,
+		"commit":           &k.commit,
+		"commit_submit":    &k.commitSubmit,
+		"commit_in_editor": &k.commitInEditor,
+		"generate_message": &k.generateMessage,
+		"co_author":        &k.coAuthor,
+		"trailers":         &k.trailers,
+		"gitmoji":          &k.gitmoji,
+		"author_date":      &k.authorDate,
+		"toggle_hunk":      &k.toggleHunk,
+		"select_lines":     &k.selectLines,
+		"search":           &k.search,
+		"next_match":       &k.nextMatch,
+		"prev_match":       &k.prevMatch,
+		"search_all":       &k.searchAll,
+		"go_to_hunk":       &k.goToHunk,
+		"fold":             &k.fold,
+		"unfold":           &k.unfold,
+		"change_set":       &k.changeSet,
+		"next_file":        &k.nextFile,
+		"prev_file":        &k.prevFile,
+		"export":           &k.export,
+		"pager":            &k.pager,
+		"review":           &k.review,
+		"history":          &k.history,
+		"recover":          &k.recover,
+		"restore_index":    &k.restoreIndex,
+		"restore":          &k.restore,
+		"branches":         &k.branches,
+		"switch_branch":    &k.switchBranch,
+		"new_branch":       &k.newBranch,
+		"fetch":            &k.fetch,
+		"range_diff":       &k.rangeDiff,
+		"recent_commits":   &k.recentCommits,
+		"settings":         &k.settings,
+		"edit_setting":     &k.editSetting,
+		"load_full_diff":   &k.loadFullDiff,
+		"help":             &k.help,
+	}
+}
+
+// Set keys from the [keys] config table, where each action maps to key
+// sequences like "ctrl+s", "g g" or "<leader> s"
+func (k *keyMap) configure(leader string, keys map[string][]string) error {
+	bindings := k.byName()
+	for name, sequences := range keys {
+		b, ok := bindings[name]
+		if !ok {
+			return errors.New(tr("Unknown action %q in [keys]", name))
+		}
+		if len(sequences) == 0 {
+			return errors.New(tr("No keys given for %q in [keys]", name))
+		}
+		var keys []string
+		for _, seq := range sequences {
+			keys = append(keys, strings.Join(strings.Fields(seq), " "))
+		}
+		b.SetKeys(keys...)
+		b.SetHelp(keys[0], b.Help().Desc)
+	}
+	for _, b := range bindings {
+		var keys []string
+		for _, seq := range b.Keys() {
+			keys = append(keys, strings.ReplaceAll(seq, "<leader>", leader))
+		}
+		b.SetKeys(keys...)
+		b.SetHelp(strings.ReplaceAll(b.Help().Key, "<leader>", leader), b.Help().Desc)
+	}
+	return nil
+}
+
+// The name of a key press as bindings use it. Bubbletea reports the space
+// bar as " ", which can't be told apart from the separator in a sequence.
+func keyName(msg tea.KeyMsg) string {
+	if msg.String() == " " {
+		return "space"
+	}
+	return msg.String()
+}
+
+// Combine a key press with the keys of an unfinished sequence pressed before
+// it. Returns the sequence to act on, or "" and the keys so far while they
+// are the start of a longer binding. A key that doesn't continue the
+// sequence starts over on its own.
+func (k keyMap) sequence(pending []string, key string) (string, []string) {
+	keys := append(slices.Clone(pending), key)
+	seq := strings.Join(keys, " ")
+	matched := false
+	bindings := slices.Collect(maps.Values(k.byName()))
+	for _, l := range k.launchers {
+		bindings = append(bindings, &l.keyBinding)
+	}
+	for _, b := range bindings {
+		for _, bk := range b.Keys() {
+			if strings.HasPrefix(bk, seq+" ") {
+				return "", keys
+			}
+			matched = matched || bk == seq
+		}
+	}
+	if !matched && len(pending) > 0 {
+		return k.sequence(nil, key)
+	}
+	return seq, nil
+}
+
+// What the footer hints depend on besides the keymap
+type footerContext struct {
+	mode      viewMode
+	tabbed    bool
+	conflict  bool // the selected file has a merge conflict
+	generator bool // a commit message generator is configured
+	gitmoji   bool // the gitmoji picker is turned on
+	selecting bool // lines of a hunk are being selected
+	truncated bool // the diff pane left out lines after diffLineLimit
+	columns   bool // the list is laid out in columns
+	dual      bool // the dual diff is shown
+	searching bool // the diff is searched for something
+	folding   bool // unchanged lines of the diff are folded
+}
+
+// Bindings worth hinting at in the footer, most important first
+func (k keyMap) footerBindings(ctx footerContext) []keyBinding {
+	var bindings []keyBinding
+	switch ctx.mode {
+	case diffMode:
+		if ctx.selecting {
+			return []keyBinding{k.scrollDown, k.scrollUp, k.selectLines, k.toggleHunk, k.cancel}
+		}
+		bindings = []keyBinding{k.scrollDown, k.scrollUp, k.nextHunk, k.prevHunk, k.toggleHunk, k.selectLines, k.search}
+		if ctx.searching {
+			bindings = append(bindings, k.nextMatch, k.prevMatch)
+		}
+		if ctx.dual {
+			bindings = append(bindings, k.switchSide)
+		}
+		if ctx.folding {
+			bindings = append(bindings, k.unfold)
+		}
+		bindings = append(bindings, k.pageDown, k.pageUp, k.fullScreen, k.dualDiff, k.fold, k.pager, k.functionContext, k.ignoreCR, k.focusList)
+	case flagsMode:
+		bindings = []keyBinding{k.down, k.up, k.assumeUnchanged, k.skipWorktree, k.focusList}
+	case logMode:
+		bindings = []keyBinding{k.scrollUp, k.scrollDown, k.focusList}
+	case recoveryMode:
+		bindings = []keyBinding{k.down, k.up, k.restore, k.pageDown, k.pageUp, k.focusList}
+	case branchesMode:
+		bindings = []keyBinding{k.down, k.up, k.switchBranch, k.newBranch, k.focusList}
+	case rangeDiffMode:
+		bindings = []keyBinding{k.scrollDown, k.scrollUp, k.pageDown, k.pageUp, k.top, k.bottom, k.focusList}
+	case recentMode:
+		bindings = []keyBinding{k.down, k.up, k.note, k.focusList}
+	case changeSetMode:
+		bindings = []keyBinding{k.scrollDown, k.scrollUp, k.nextFile, k.prevFile, k.nextHunk, k.prevHunk, k.toggle, k.pageDown, k.pageUp, k.focusList}
+	case searchMode:
+		bindings = []keyBinding{k.down, k.up, k.goToHunk, k.focusList}
+	case settingsMode:
+		bindings = []keyBinding{k.down, k.up, k.editSetting, k.focusList}
+	case cleanMode:
+		bindings = []keyBinding{k.down, k.up, k.toggle, k.toggleAll, k.deleteSelected, k.cleanIgnored, k.focusList}
+	case historyMode:
+		bindings = []keyBinding{k.down, k.up, k.pageDown, k.pageUp, k.nextHunk, k.prevHunk, k.fullScreen, k.focusList}
+	case commitMode:
+		// Other keys are typed into the message
+		bindings = []keyBinding{k.commitSubmit, k.coAuthor, k.trailers, k.authorDate}
+		if ctx.generator {
+			bindings = append(bindings, k.generateMessage)
+		}
+		if ctx.gitmoji {
+			bindings = append(bindings, k.gitmoji)
+		}
+		return append(bindings, k.cancel)
+	default:
+		if ctx.conflict {
+			bindings = []keyBinding{k.useOurs, k.useTheirs, k.mergetool}
+		}
+		bindings = append(bindings, k.down, k.up)
+		if ctx.columns {
+			bindings = append(bindings, k.nextColumn, k.prevColumn)
+		}
+		bindings = append(bindings, k.toggle, k.toggleAll, k.commit, k.commitInEditor, k.focusDiff, k.fullScreen, k.dualDiff, k.preview, k.fold, k.pager, k.functionContext, k.ignoreCR, k.discard, k.format, k.move, k.toggleExec, k.toggleNext, k.stageMatching, k.goToFile, k.nextDir, k.prevDir, k.searchAll, k.changeSet, k.stageMode, k.stageContent, k.discardAll, k.clean, k.bookmark, k.bookmarkedOnly, k.note, k.export, k.history, k.review, k.branches, k.fetch, k.rangeDiff, k.recentCommits, k.settings, k.showFlags, k.fileInfo, k.pathDisplay, k.popStash, k.recover, k.restoreIndex, k.showLog)
+		for _, l := range k.launchers {
+			bindings = append(bindings, l.keyBinding)
+		}
+	}
+	if ctx.truncated && (ctx.mode == diffMode || ctx.mode == historyMode || ctx.mode == recoveryMode) {
+		bindings = append([]keyBinding{k.loadFullDiff}, bindings...)
+	}
+	// First, so it's there however narrow the terminal is
+	bindings = append([]keyBinding{k.help}, bindings...)
+	if ctx.tabbed {
+		bindings = append(bindings, k.nextTab)
+	}
+	return append(bindings, k.quit)
+}
+
+// Bindings with their descriptions in the interface language, for the
+// help component
+func translatedBindings(bindings []keyBinding) []key.Binding {
+	var result []key.Binding
+	for _, kb := range bindings {
+		b := kb.Binding
+		b.SetHelp(kb.Help().Key, tr(kb.Help().Desc))
+		result = append(result, b)
+	}
+	return result
+}
+
+func newHelp(width int) help.Model {
+	h := help.New()
+	h.Width = width
+	h.ShortSeparator = " | "
+	h.Styles = helpStyles
+	return h
+}
+
+// Render footer hints, dropping trailing hints that don't fit in width
+func renderFooter(bindings []keyBinding, width int) string {
+	h, keys := newHelp(width), translatedBindings(bindings)
+	footer := h.ShortHelpView(keys)
+	// The help component goes on past width when not even its ellipsis fits
+	for width > 0 && len(keys) > 0 && ansi.StringWidth(footer) > width {
+		keys = keys[:len(keys)-1]
+		footer = h.ShortHelpView(keys)
+	}
+	return footer
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"errors"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// An external tool bound to a key with [[launchers]] in the config
+type launcherConfig struct {
+	Name string `toml:"name"`
+	Key  string `toml:"key"`
+	// Run with sh in the repository root, with the selected file in $FILE,
+	// the repository in $REPO and the file's page on the forge in $URL
+	Command string `toml:"command"`
+}
+
+type launcher struct {
+	keyBinding
+	command string
+}
+
+// Bind the launchers of the config, after the keys they may shadow are set
+func (k *keyMap) addLaunchers(leader string, configs []launcherConfig) error {
+	for _, c := range configs {
+		if c.Name == "" || c.Key == "" || c.Command == "" {
+			return errors.New(tr("A launcher needs a name, a key and a command"))
+		}
+		seq := strings.ReplaceAll(strings.Join(strings.Fields(c.Key), " "), "<leader>", leader)
+		k.launchers = append(k.launchers, launcher{keyBinding{key.NewBinding(key.WithKeys(seq), key.WithHelp(seq, c.Name))}, c.Command})
+	}
+	return nil
+}
+
+func (k keyMap) launcher(key string) (launcher, bool) {
+	for _, l := range k.launchers {
+		if l.matches(key) {
+			return l, true
+		}
+	}
+	return launcher{}, false
+}
+
+func (k keyMap) launches(key string) bool {
+	_, ok := k.launcher(key)
+	return ok
+}
+
+type launcherFinishedMsg struct {
+	name string
+	err  error
+}
+
+// Suspend the UI and run the tool on the selected file, or on the repository
+// when no file is selected
+func (m *model) launch(l launcher) tea.Cmd {
+	file := ""
+	if i := m.selected(); i >= 0 {
+		file = m.files[i].pathFromGitRoot
+	}
+	cmd := exec.Command("sh", "-c", l.command)
+	cmd.Dir = m.repo.root
+	cmd.Env = append(os.Environ(), "FILE="+file, "REPO="+m.repo.root, "URL="+m.repo.forgeURL(file))
+	name := l.Help().Desc
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return launcherFinishedMsg{name, err}
+	})
+}
+
+// The tool may have changed anything, like staging or committing
+func (m *model) launcherFinished(msg launcherFinishedMsg) {
+	m.reload()
+	m.loadDiff()
+	if msg.err != nil {
+		m.status = tr("%s failed: %v", msg.name, msg.err)
+	}
+}
+
+// The page of path on the forge the origin remote is on, at the current
+// branch, or of the repository for no path. Empty if there's no such remote.
+func (r repo) forgeURL(path string) string {
+	remote, err := r.git("config", "remote.origin.url").Output()
+	if err != nil {
+		return ""
+	}
+	ref := trimmedOutput(r.git("symbolic-ref", "--short", "-q", "HEAD").Output())
+	if ref == "" {
+		ref = trimmedOutput(r.git("rev-parse", "HEAD").Output())
+	}
+	return forgeURL(strings.TrimSpace(string(remote)), ref, path)
+}
+
+// The trimmed output of a command, empty if it failed
+func trimmedOutput(output []byte, err error) string {
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// Turn a remote like git@github.com:owner/repo.git or
+// https://gitlab.com/owner/repo into the web page of path at ref. Bitbucket
+// has its own layout, GitHub, GitLab, Gitea and most others share one.
+func forgeURL(remote, ref, path string) string {
+	remote = strings.TrimSuffix(strings.TrimSuffix(remote, "/"), ".git")
+	var host, repoPath string
+	if u, err := url.Parse(remote); err == nil && u.Host != "" {
+		host, repoPath = u.Hostname(), u.Path
+	} else if h, p, ok := strings.Cut(remote, ":"); ok && !strings.Contains(h, "/") {
+		// scp-like syntax, the user before @
+		_, host, _ = strings.Cut(h, "@")
+		if host == "" {
+			host = h
+		}
+		repoPath = "/" + p
+	} else {
+		return ""
+	}
+	page := "https://" + host + "/" + strings.TrimPrefix(repoPath, "/")
+	if path == "" || ref == "" {
+		return page
+	}
+	path = (&url.URL{Path: path}).EscapedPath()
+	if strings.Contains(host, "bitbucket") {
+		return page + "/src/" + ref + "/" + path
+	}
+	return page + "/blob/" + ref + "/" + path
+}
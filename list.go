@@ -0,0 +1,435 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// A group of the file list, in the order git status shows them
+type section int
+
+const (
+	noSection section = iota // the flat list when grouping by status is off
+	conflictsSection
+	stagedSection
+	unstagedSection
+	untrackedSection
+	branchSection // committed on the branch, when reviewing it
+)
+
+func (s section) title() string {
+	switch s {
+	case conflictsSection:
+		return tr("Unmerged paths")
+	case stagedSection:
+		return tr("Staged changes")
+	case unstagedSection:
+		return tr("Changes not staged")
+	case untrackedSection:
+		return tr("Untracked")
+	case branchSection:
+		return tr("Committed on the branch")
+	default:
+		return ""
+	}
+}
+
+// The sections a file is listed in, partially staged files show in two
+func (f fileEntry) sections() []section {
+	switch {
+	case f.status == conflicted:
+		return []section{conflictsSection}
+	case f.untracked:
+		return []section{untrackedSection}
+	case f.status == committed:
+		return []section{branchSection}
+	case f.status == staged:
+		return []section{stagedSection}
+	case f.status == partiallyStaged:
+		return []section{stagedSection, unstagedSection}
+	default:
+		return []section{unstagedSection}
+	}
+}
+
+// A line of the file list, either a section header or a file within a section
+type listRow struct {
+	section section
+	file    int // index into files, -1 for a section header
+}
+
+func (m model) rows() []listRow {
+	var rows []listRow
+	if !cfg.GroupByStatus {
+		for i, f := range m.files {
+			if m.listed(f) {
+				rows = append(rows, listRow{noSection, i})
+			}
+		}
+		return rows
+	}
+
+	bySection := make(map[section][]int)
+	for i, f := range m.files {
+		if !m.listed(f) {
+			continue
+		}
+		for _, s := range f.sections() {
+			bySection[s] = append(bySection[s], i)
+		}
+	}
+	for _, s := range []section{conflictsSection, stagedSection, unstagedSection, untrackedSection, branchSection} {
+		if len(bySection[s]) == 0 {
+			continue
+		}
+		rows = append(rows, listRow{s, -1})
+		for _, i := range bySection[s] {
+			rows = append(rows, listRow{s, i})
+		}
+	}
+	return rows
+}
+
+// Index of the file under the cursor, -1 on a section header or an empty list
+func (m model) selected() int {
+	rows := m.rows()
+	if m.cursor >= len(rows) {
+		return -1
+	}
+	return rows[m.cursor].file
+}
+
+func (m model) selectedRow() (listRow, bool) {
+	rows := m.rows()
+	if m.cursor >= len(rows) {
+		return listRow{}, false
+	}
+	return rows[m.cursor], true
+}
+
+// Move the cursor to the first file of the next group of files in the same
+// top-level directory, or of the previous one, sections starting new groups
+func (m *model) cursorDir(direction int) {
+	rows := m.rows()
+	group := func(i int) string {
+		if i < 0 || i >= len(rows) || rows[i].file < 0 {
+			return ""
+		}
+		dir, _, _ := strings.Cut(m.files[rows[i].file].pathFromGitRoot, "/")
+		return fmt.Sprint(rows[i].section, "/", dir)
+	}
+	start := func(i int) int {
+		for i > 0 && group(i-1) != "" && group(i-1) == group(i) {
+			i--
+		}
+		return i
+	}
+	if direction > 0 {
+		for i := m.cursor + 1; i < len(rows); i++ {
+			if group(i) != "" && group(i) != group(m.cursor) {
+				m.cursor = i
+				return
+			}
+		}
+		return
+	}
+	for i := start(m.cursor) - 1; i >= 0; i-- {
+		if group(i) != "" {
+			m.cursor = start(i)
+			return
+		}
+	}
+}
+
+// Move the cursor to the file numbered by the digits typed, counting the
+// file rows of the list from 1. A digit that makes a number past the last
+// file starts a new number.
+func (m *model) typeFileNumber(digits string) {
+	var files []int
+	for i, row := range m.rows() {
+		if row.file >= 0 {
+			files = append(files, i)
+		}
+	}
+	n, _ := strconv.Atoi(digits)
+	if n > len(files) {
+		digits = digits[len(digits)-1:]
+		n, _ = strconv.Atoi(digits)
+	}
+	if n < 1 || n > len(files) {
+		return
+	}
+	m.cursor = files[n-1]
+	if n*10 <= len(files) {
+		// Another digit may follow
+		m.fileNumber = digits
+	}
+}
+
+func (m model) rowPath(row listRow) string {
+	if row.file < 0 {
+		return ""
+	}
+	return m.files[row.file].pathFromGitRoot
+}
+
+// Find the row of a path in a section, or of the path in any section if
+// it moved. An empty path finds the section header.
+func (m model) findRow(s section, path string) int {
+	rows := m.rows()
+	for i, row := range rows {
+		if row.section == s && m.rowPath(row) == path {
+			return i
+		}
+	}
+	if path == "" {
+		return -1
+	}
+	for i, row := range rows {
+		if m.rowPath(row) == path {
+			return i
+		}
+	}
+	return -1
+}
+
+// Change the file list, keeping the cursor on the same row if it's still
+// there, or on the same file if it moved to another section
+func (m *model) keepingCursor(update func()) {
+	current, ok := m.selectedRow()
+	var path string
+	if ok {
+		path = m.rowPath(current)
+	}
+	update()
+	m.cursor = min(m.cursor, max(len(m.rows())-1, 0))
+	if ok {
+		if i := m.findRow(current.section, path); i >= 0 {
+			m.cursor = i
+		}
+	}
+}
+
+// Files a row covers, all files of the section for a header
+func (m model) rowFiles(row listRow) []int {
+	if row.file >= 0 {
+		return []int{row.file}
+	}
+	var files []int
+	for _, r := range m.rows() {
+		if r.section == row.section && r.file >= 0 {
+			files = append(files, r.file)
+		}
+	}
+	return files
+}
+
+// Stats of the changes a row shows: only the staged or unstaged part of a
+// partially staged file
+func (m model) rowDiff(row listRow) diffStat {
+	f := m.files[row.file]
+	switch row.section {
+	case stagedSection:
+		return f.stagedDiff
+	case unstagedSection:
+		return f.unstagedDiff
+	default:
+		return f.diff
+	}
+}
+
+func (m model) listRows() []string {
+	rows := m.rows()
+	maxFilenameLen := 0
+	maxAddedLen := 0
+	iconWidth := 0
+	anyBookmarked := false
+	fileRows := 0
+	for _, row := range rows {
+		if row.file < 0 {
+			continue
+		}
+		fileRows++
+		maxFilenameLen = max(maxFilenameLen, ansi.StringWidth(m.repo.displayPath(m.files[row.file].pathFromGitRoot, m.paths)))
+		maxAddedLen = max(maxAddedLen, len(strconv.Itoa(m.rowDiff(row).added)))
+		iconWidth = max(iconWidth, ansi.StringWidth(fileIcon(m.files[row.file])))
+		anyBookmarked = anyBookmarked || m.bookmarked(m.files[row.file])
+	}
+	glyphs := cfg.Glyphs
+	checkboxWidth := glyphs.checkboxWidth()
+	numberWidth := 0
+	if cfg.FileNumbers {
+		numberWidth = len(strconv.Itoa(fileRows))
+	}
+
+	var lines []string
+	number := 0
+	for i, row := range rows {
+		cursor := glyphs.cursor(i == m.cursor)
+		if row.file >= 0 && numberWidth > 0 {
+			number++
+			cursor += separatorStyle.Render(fmt.Sprintf("%*d", numberWidth, number)) + " "
+		}
+		if row.file < 0 {
+			title := fmt.Sprintf("%s (%d)", row.section.title(), len(m.rowFiles(row)))
+			lines = append(lines, cursor+sectionStyle.Render(title))
+			continue
+		}
+
+		f := m.files[row.file]
+		checkbox := padGlyph(glyphs.checkbox(f.status), checkboxWidth)
+		var star string
+		if m.bookmarked(f) {
+			star = promptStyle.Render(glyphs.Bookmark) + " "
+		} else if anyBookmarked {
+			star = strings.Repeat(" ", ansi.StringWidth(glyphs.Bookmark)+1)
+		}
+		var icon string
+		if iconWidth > 0 {
+			icon = padGlyph(fileIcon(f), iconWidth) + " "
+		}
+		d := m.rowDiff(row)
+		path := m.repo.displayPath(f.pathFromGitRoot, m.paths)
+		lines = append(lines, fmt.Sprintf(
+			"%s%s %s%s%s%s %s+%d/-%d%s",
+			cursor,
+			checkbox,
+			star,
+			icon,
+			path,
+			strings.Repeat(" ", maxFilenameLen-ansi.StringWidth(path)),
+			strings.Repeat(" ", maxAddedLen-len(strconv.Itoa(d.added))),
+			d.added,
+			d.deleted,
+			badges(f)+m.noteBadge(f),
+		))
+	}
+	return lines
+}
+
+// Short markers for notable file properties, shown after the diff stats
+func badges(f fileEntry) string {
+	var b strings.Builder
+	if c := f.modeChange.typeChange(); c != "" {
+		b.WriteString(" " + badgeStyle.Render(tr("type %s", c)))
+	} else if c := f.modeChange.String(); c != "" {
+		b.WriteString(" " + badgeStyle.Render(tr("mode %s", c)))
+	}
+	if f.symlink && f.modeChange.typeChange() == "" {
+		b.WriteString(" " + badgeStyle.Render(tr("symlink")))
+	}
+	if f.outsideSparse {
+		b.WriteString(" " + badgeStyle.Render(tr("sparse")))
+	}
+	if f.lineEndings {
+		b.WriteString(" " + badgeStyle.Render(tr("line endings")))
+	}
+	return b.String()
+}
+
+// Width the list needs to show every row untruncated
+func (m model) listWidth() int {
+	width := 0
+	for _, row := range m.listRows() {
+		width = max(width, ansi.StringWidth(row))
+	}
+	return width
+}
+
+// Spaces between the columns of the list
+const listColumnGap = 2
+
+// Columns the list takes in width and height with list_columns set, as many
+// as its rows need as far as they fit
+func (m model) listColumns(width, height int) int {
+	n := len(m.rows())
+	if !cfg.ListColumns || height <= 0 || n <= height {
+		return 1
+	}
+	fit := (width + listColumnGap) / (m.listWidth() + listColumnGap)
+	return max(min((n+height-1)/height, fit), 1)
+}
+
+// Width of the list next to the diff pane, at most half of the screen
+func (m model) listPaneWidth() int {
+	half := m.width / 2
+	if columns := m.listColumns(half, m.bodyHeight()); columns > 1 {
+		return columns*m.listWidth() + (columns-1)*listColumnGap
+	}
+	return min(m.listWidth(), half)
+}
+
+// Columns of the list in the current layout, see View
+func (m model) shownListColumns() int {
+	switch {
+	case m.height == 0, m.fullScreenDiff:
+		return 1
+	case m.width < minSplitWidth:
+		return m.listColumns(m.width, m.bodyHeight())
+	default:
+		return m.listColumns(m.listPaneWidth(), m.bodyHeight())
+	}
+}
+
+// Move the cursor to the same row of the next or previous column, or to the
+// end of a last column that is shorter
+func (m *model) cursorColumn(delta int) {
+	height := m.bodyHeight()
+	last := len(m.rows()) - 1
+	switch {
+	case delta > 0 && m.cursor/height < last/height:
+		m.cursor = min(m.cursor+height, last)
+	case delta < 0 && m.cursor >= height:
+		m.cursor -= height
+	}
+}
+
+// Render the rows of the file list that fit in height, keeping the cursor visible
+func (m model) listView(width, height int) string {
+	rows := m.listRows()
+	if columns := m.listColumns(width, height); columns > 1 {
+		return m.columnsView(rows, columns, height)
+	}
+	offset := max(min(m.cursor-height+1, len(rows)-height), 0)
+	end := min(offset+height, len(rows))
+	var b strings.Builder
+	for i, row := range rows[offset:end] {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		if width > 0 {
+			row = ansi.Truncate(row, width, "")
+		}
+		b.WriteString(row)
+	}
+	return b.String()
+}
+
+// The rows top to bottom in columns left to right, scrolled by whole columns
+// to keep the cursor visible
+func (m model) columnsView(rows []string, columns, height int) string {
+	columnWidth := m.listWidth()
+	first := max(m.cursor/height-columns+1, 0)
+	lines := make([]string, height)
+	for r := range lines {
+		var line strings.Builder
+		for c := range columns {
+			i := (first+c)*height + r
+			if i >= len(rows) {
+				break
+			}
+			if c > 0 {
+				line.WriteString(strings.Repeat(" ", listColumnGap))
+			}
+			line.WriteString(rows[i])
+			if next := i + height; c < columns-1 && next < len(rows) {
+				line.WriteString(strings.Repeat(" ", columnWidth-ansi.StringWidth(rows[i])))
+			}
+		}
+		lines[r] = line.String()
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/hzqtc/git-istage/gitx"
+)
+
+func (r repo) indexLockPath() string {
+	return filepath.Join(r.gitDir, "index.lock")
+}
+
+// Run a git command that writes the index, see gitx.Run
+func (r repo) run(args ...string) error {
+	return gitx.Run(r.cwd, args...)
+}
+
+// Like run, with input given to the command on stdin
+func (r repo) runInput(input string, args ...string) error {
+	return gitx.RunInput(r.cwd, input, args...)
+}
+
+// If err is an index lock that didn't go away, explain it and offer to
+// remove the lock file. Returns whether it was one.
+func (m *model) handleIndexLock(err error) bool {
+	if !errors.Is(err, gitx.ErrIndexLocked) {
+		return false
+	}
+	path := m.repo.indexLockPath()
+	age := tr("unknown")
+	if info, err := os.Stat(path); err == nil {
+		age = time.Since(info.ModTime()).Round(time.Second).String()
+	}
+	m.confirm = &confirmation{
+		message: tr("Remove the index lock?"),
+		details: []string{
+			tr("The index is locked"),
+			"",
+			tr("%s exists, created %s ago.", path, age),
+			tr("Another git process is probably running, e.g. an editor"),
+			tr("integration or a commit waiting for its message."),
+			"",
+			tr("If no git process is running, the lock is stale, left behind"),
+			tr("by a crashed git, and can be removed safely."),
+		},
+		onYes: func(m *model) {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				m.status = tr("Failed to remove %s: %v", path, err)
+				return
+			}
+			m.status = tr("Removed the index lock, try again")
+		},
+	}
+	return true
+}
@@ -1,319 +1,225 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
-	"strconv"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/charmbracelet/lipgloss"
 )
 
-type stagingStatus int
-
-const (
-	unstaged stagingStatus = iota
-	staged
-	partiallyStaged
-)
-
-type fileEntry struct {
-	pathFromGitRoot string
-	pathFromCwd     string
-	status          stagingStatus
-	diff            diffStat
-}
-
-type diffStat struct {
-	added   int
-	deleted int
-}
-
-func (d diffStat) combine(o diffStat) diffStat {
-	d.added += o.added
-	d.deleted += o.deleted
-	return d
-}
-
-type model struct {
-	files    []fileEntry
-	cursor   int
-	quitting bool
-}
-
-var (
-	cursorStyle          = lipgloss.NewStyle().Foreground(lipgloss.Color("12"))
-	stagedStyle          = lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
-	partiallyStagedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("11"))
-	unstagedStyle        = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
-)
-
-var gitRootPath = func() string {
-	gitRootCmd := exec.Command("git", "rev-parse", "--show-toplevel")
-	gitRootBytes, _ := gitRootCmd.Output()
-	return strings.TrimSpace(string(gitRootBytes))
-}()
-
-var currentPath = func() string {
-	cwd, _ := os.Getwd()
-	return cwd
-}()
-
-func interpretGitStatus(xy string) stagingStatus {
-	x, y := xy[0], xy[1]
-
-	switch {
-	case x == '?' && y == '?':
-		// Cover cases: '??'
-		return unstaged
-	case x == 'A' && y != ' ':
-		// Cover cases: 'AM'
-		return partiallyStaged
-	case x != ' ' && y != ' ':
-		// Cover cases: '*M'
-		return partiallyStaged
-	case x == 'A':
-		// Cover cases: 'A '
-		return staged
-	case x != ' ':
-		// Cover cases: '* '
-		return staged
-	default:
-		// Cover cases: ' *'
-		return unstaged
+func main() {
+	// The locale's language until the config is read, for the usage text
+	setLanguage("")
+	themeName := flag.String("theme", "", tr("color theme, one of %v", themeNames()))
+	workspace := flag.String("workspace", "", tr("file listing repositories to open as tabs, one per line"))
+	profileKind := flag.String("profile", "", tr("write a profile on exit and time git commands, one of %v", profileKinds))
+	demo := flag.Bool("demo", false, tr("try the interface on a made up repository kept in memory"))
+	record := flag.String("record", "", tr("record the keys pressed and what git answered into a file, for bug reports"))
+	replay := flag.String("replay", "", tr("replay a session recorded with --record"))
+	findRenames := flag.Int("find-renames", 0, tr("how similar in percent a deleted and an added file must be to show as a rename"))
+	showVersion := flag.Bool("version", false, tr("print the version and exit"))
+	flag.BoolVar(&watchWhenClean, "watch", false, tr("stay open when there's nothing to stage and list changes as they appear"))
+	flag.BoolVar(&reviewAtStart, "review", false, tr("review everything the branch changes since it forked from its upstream"))
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), tr("Usage: %s [flags] [repository...]", filepath.Base(os.Args[0])))
+		fmt.Fprintln(flag.CommandLine.Output(), tr("   or: %s completion %s", filepath.Base(os.Args[0]), strings.Join(completionShells, "|")))
+		fmt.Fprintln(flag.CommandLine.Output(), tr("   or: %s config list|get <name>|set <name> <value>", filepath.Base(os.Args[0])))
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *showVersion {
+		fmt.Println(versionString())
+		return
+	}
+	if flag.Arg(0) == "completion" {
+		if flag.NArg() != 2 {
+			flag.Usage()
+			os.Exit(2)
+		}
+		if err := writeCompletion(os.Stdout, flag.Arg(1)); err != nil {
+			fmt.Println(tr("Error:"), err)
+			os.Exit(1)
+		}
+		return
 	}
-}
-
-func getGitChanges() ([]fileEntry, error) {
-	// Check if we are in a git repository
-	checkCmd := exec.Command("git", "rev-parse", "--is-inside-work-tree")
-	checkOutput, err := checkCmd.Output()
-	if err != nil || strings.TrimSpace(string(checkOutput)) != "true" {
-		return nil, fmt.Errorf("Not inside a git repository")
+	if flag.Arg(0) == "config" {
+		if n, ok := configCommands[flag.Arg(1)]; !ok || flag.NArg() != n+2 {
+			flag.Usage()
+			os.Exit(2)
+		}
+		// Messages in the language of the config, if it can be read
+		if c, err := loadConfig(); err == nil {
+			setLanguage(c.Language)
+		}
+		if err := runConfigCommand(os.Stdout, flag.Args()[1:]); err != nil {
+			fmt.Println(tr("Error:"), err)
+			os.Exit(1)
+		}
+		return
 	}
 
-	statusCh := make(chan map[string]stagingStatus)
-	diffStatsCh := make(chan map[string]diffStat)
-	go func() {
-		statusCh <- getFileStatus()
-	}()
-	go func() {
-		diffStatsCh <- getFileDiffStats()
-	}()
-	status := <-statusCh
-	diffStats := <-diffStatsCh
-
-	var files []fileEntry
-	for path, st := range status {
-		files = append(files,
-			fileEntry{
-				pathFromGitRoot: path,
-				pathFromCwd:     getRelPath(path),
-				status:          st,
-				diff:            diffStats[path],
-			})
+	var err error
+	// A replayed session gets the keys recorded without the tour
+	firstRun = isFirstRun() && *replay == ""
+	if cfg, err = loadConfig(); err != nil {
+		fmt.Println(tr("Error reading config:"), err)
+		os.Exit(1)
 	}
-	return files, nil
-}
-
-func getFileStatus() map[string]stagingStatus {
-	result := make(map[string]stagingStatus)
-	// Get porcelain status
-	cmd := exec.Command("git", "status", "--porcelain")
-	output, err := cmd.Output()
-	if err != nil {
-		return result
+	if err := setLanguage(cfg.Language); err != nil {
+		fmt.Println(tr("Error reading config:"), err)
+		os.Exit(1)
 	}
-
-	for line := range strings.SplitSeq(string(output), "\n") {
-		if len(line) < 4 {
-			continue
+	if cfg.LogFile != "" {
+		if err := openCommandLogFile(cfg.LogFile); err != nil {
+			fmt.Println(tr("Error opening log file:"), err)
+			os.Exit(1)
 		}
-		// The first 2 letters on each line of `git status --porcelain` output represent status
-		xy := line[:2]
-		pathFromGitRoot := line[3:]
-		result[pathFromGitRoot] = interpretGitStatus(xy)
 	}
-	return result
-}
-
-func getFileDiffStats() map[string]diffStat {
-	result := make(map[string]diffStat)
-	diffCh := make(chan map[string]diffStat)
-	const diffCmdNum = 2
+	if err := defaultKeyMap.configure(cfg.Leader, cfg.Keys); err != nil {
+		fmt.Println(tr("Error reading config:"), err)
+		os.Exit(1)
+	}
+	if err := defaultKeyMap.addLaunchers(cfg.Leader, cfg.Launchers); err != nil {
+		fmt.Println(tr("Error reading config:"), err)
+		os.Exit(1)
+	}
+	if *findRenames != 0 {
+		cfg.Diff.FindRenames = *findRenames
+	}
+	if err := checkConfig(cfg); err != nil {
+		fmt.Println(tr("Error reading config:"), err)
+		os.Exit(1)
+	}
+	if err := setGitTimeout(cfg.GitTimeout); err != nil {
+		fmt.Println(tr("Error reading config:"), err)
+		os.Exit(1)
+	}
+	if *themeName != "" {
+		cfg.Theme = *themeName
+	}
+	if cfg.Theme == "" {
+		cfg.Theme = defaultThemeName()
+	}
+	if err := applyTheme(cfg.Theme); err != nil {
+		fmt.Println(tr("Error:"), err)
+		os.Exit(1)
+	}
 
-	go func() {
-		cmd := exec.Command("git", "diff", "--numstat")
-		output, err := cmd.Output()
+	var replayed *session
+	if *replay != "" {
+		s, err := readSession(*replay)
 		if err != nil {
-			diffCh <- nil
+			fmt.Println(tr("Error reading session:"), err)
+			os.Exit(1)
 		}
-		diffCh <- parseDiffOutput(string(output))
-	}()
+		replayed = &s
+		reviewAtStart = s.Review
+	}
 
-	go func() {
-		cmd := exec.Command("git", "diff", "--numstat", "--cached")
-		output, err := cmd.Output()
+	dirs := flag.Args()
+	if *workspace != "" {
+		workspaceDirs, err := readWorkspace(*workspace)
 		if err != nil {
-			diffCh <- nil
-		}
-		diffCh <- parseDiffOutput(string(output))
-	}()
-
-	for range diffCmdNum {
-		diff := <-diffCh
-		for path, d := range diff {
-			result[path] = result[path].combine(d)
+			fmt.Println(tr("Error reading workspace:"), err)
+			os.Exit(1)
 		}
+		dirs = append(dirs, workspaceDirs...)
 	}
-
-	return result
-}
-
-func parseDiffOutput(output string) map[string]diffStat {
-	result := make(map[string]diffStat)
-	for line := range strings.SplitSeq(output, "\n") {
-		parts := strings.Fields(line)
-		if len(parts) < 3 {
-			continue
+	var repos []repo
+	switch {
+	case replayed != nil:
+		repos, dirs = replayed.replayRepos(), nil
+	case *demo:
+		repos = append(repos, openDemoRepo())
+	case len(dirs) == 0:
+		dirs = []string{"."}
+	}
+	if len(dirs) > 0 {
+		if err := checkGit(); err != nil {
+			fmt.Println(tr("Error:"), err)
+			os.Exit(1)
 		}
-		added, _ := strconv.Atoi(parts[0])
-		deleted, _ := strconv.Atoi(parts[1])
-		pathFromGitRoot := parts[2]
-		result[pathFromGitRoot] = diffStat{added, deleted}
 	}
-	return result
-}
-
-// Convert a path from git root to a relative path of cwd
-func getRelPath(pathFromGitRoot string) string {
-	// many git commands output file path relative to git root
-	// So we need to convert it to relative path to CWD
-	absPath := filepath.Join(gitRootPath, pathFromGitRoot)
-	pathFromCwd, _ := filepath.Rel(currentPath, absPath)
-	return pathFromCwd
-}
-
-func (m model) Init() tea.Cmd {
-	return nil
-}
-
-func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	switch msg := msg.(type) {
-	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+c", "q":
-			m.quitting = true
-			return m, tea.Quit
-		case "up", "k":
-			m.cursorUp()
-		case "down", "j":
-			m.cursorDown()
-		case " ":
-			m.toggle(m.cursor)
-		case "a":
-			for i := range len(m.files) {
-				m.toggle(i)
-			}
-		case "tab":
-			m.toggle(m.cursor)
-			m.cursorDown()
-		case "shift+tab":
-			m.toggle(m.cursor)
-			m.cursorUp()
+	seen := make(map[string]bool)
+	for _, dir := range dirs {
+		r, err := openRepo(dir)
+		if err != nil {
+			fmt.Println(tr("Error:"), err)
+			os.Exit(1)
+		}
+		if !seen[r.root] {
+			seen[r.root] = true
+			repos = append(repos, r)
 		}
 	}
-	return m, nil
-}
 
-func (m *model) cursorUp() {
-	if m.cursor > 0 {
-		m.cursor--
+	if cfg.Diff.GitColors && len(dirs) > 0 {
+		// The styles are shared by the tabs, so the first repository's config
+		applyGitColors(repos[0])
+		cfg.Diff.ColorMoved = cfg.Diff.ColorMoved || gitColorMoved(repos[0])
 	}
-}
 
-func (m *model) cursorDown() {
-	if m.cursor < len(m.files)-1 {
-		m.cursor++
+	var prof *profile
+	if *profileKind != "" {
+		if prof, err = startProfile(*profileKind); err != nil {
+			fmt.Println(tr("Error:"), err)
+			os.Exit(1)
+		}
 	}
-}
 
-func (m *model) toggle(index int) {
-	f := &m.files[index]
-	switch f.status {
-	case staged:
-		exec.Command("git", "restore", "--staged", f.pathFromCwd).Run()
-		f.status = unstaged
-	case partiallyStaged, unstaged:
-		exec.Command("git", "add", f.pathFromCwd).Run()
-		f.status = staged
+	var recorder *sessionRecorder
+	var options []tea.ProgramOption
+	if *record != "" {
+		recorder = recordSession(repos)
+		options = append(options, tea.WithFilter(recorder.filter))
 	}
-}
 
-func (m model) View() string {
-	if m.quitting {
-		return ""
+	term := openTerminal()
+	options = append(options, tea.WithOutput(term.out))
+	p := tea.NewProgram(newApp(repos), options...)
+	if replayed != nil {
+		go replayed.replayEvents(p)
 	}
-
-	maxFilenameLen := 0
-	maxAddedLen := 0
-	for _, f := range m.files {
-		maxFilenameLen = max(maxFilenameLen, len(f.pathFromGitRoot))
-		maxAddedLen = max(maxAddedLen, len(strconv.Itoa(f.diff.added)))
+	term.saveTitle()
+	final, err := p.Run()
+	term.restoreTitle()
+	if recorder != nil {
+		if err := recorder.write(*record); err != nil {
+			fmt.Println(tr("Error writing session:"), err)
+		} else {
+			fmt.Println(tr("Recorded the session to %s", *record))
+		}
 	}
-
-	var b strings.Builder
-	for i, f := range m.files {
-		var cursor string
-		if i == m.cursor {
-			cursor = cursorStyle.Render("> ")
+	if prof != nil {
+		if err := prof.stop(); err != nil {
+			fmt.Println(tr("Error writing profile:"), err)
 		} else {
-			cursor = cursorStyle.Render("  ")
+			fmt.Println(tr("Wrote the %s profile to %s", prof.kind, prof.file.Name()))
 		}
-		var checkbox string
-		switch f.status {
-		case staged:
-			checkbox = stagedStyle.Render("[✓]")
-		case partiallyStaged:
-			checkbox = partiallyStagedStyle.Render("[~]")
-		case unstaged:
-			checkbox = unstagedStyle.Render("[ ]")
+		for _, line := range gitTimeSummary() {
+			fmt.Println("  " + line)
 		}
-		b.WriteString(fmt.Sprintf(
-			"%s%s %s%s %s+%d/-%d\n",
-			cursor,
-			checkbox,
-			f.pathFromGitRoot,
-			strings.Repeat(" ", maxFilenameLen-len(f.pathFromGitRoot)),
-			strings.Repeat(" ", maxAddedLen-len(strconv.Itoa(f.diff.added))),
-			f.diff.added,
-			f.diff.deleted,
-		))
 	}
-
-	b.WriteString("\nj/k/↑/↓: navigate | space: toggle | a: toggle all | q: quit\n")
-	return b.String()
-}
-
-func main() {
-	files, err := getGitChanges()
+	if a, ok := final.(app); ok && a.crash.happened() {
+		a.crash.report(os.Stderr)
+		os.Exit(2)
+	}
+	if errors.Is(err, tea.ErrProgramPanic) {
+		// Panicked in a command, bubbletea printed the stack trace
+		writeLastCommands(os.Stderr)
+	}
 	if err != nil {
-		fmt.Println("Error:", err)
+		fmt.Println(tr("Error running program:"), err)
 		os.Exit(1)
 	}
-
-	if len(files) == 0 {
-		fmt.Println("No changes to stage or unstage.")
-		os.Exit(0)
+	if r, ok := final.(app).handOff(); ok {
+		os.Exit(r.commitInEditor())
 	}
-
-	m := model{files: files}
-	p := tea.NewProgram(m)
-	if _, err := p.Run(); err != nil {
-		fmt.Println("Error running program:", err)
-		os.Exit(1)
+	if final.(app).noChanges {
+		fmt.Println(tr("No changes to stage or unstage."))
 	}
 }
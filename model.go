@@ -0,0 +1,1106 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// State of a single repository tab
+type model struct {
+	repo           repo
+	files          []fileEntry
+	cursor         int
+	diffLines      []diffLine
+	pane           diffPane
+	fullScreenDiff bool
+	dualDiff       bool       // staged and unstaged changes side by side, see dualDiffView
+	preview        bool       // untracked files show their content, see filePreview
+	dualSide       section    // the side of the dual diff the diff pane is
+	otherDiff      []diffLine // the other side of the dual diff
+	keys           keyMap
+	mode           viewMode
+	width          int
+	height         int
+	flagged        []flaggedFile
+	flagCursor     int
+	branch         string
+	upstream       upstreamInfo
+	fetching       bool
+	stash          stashInfo
+	safetyStash    string // commit of the last stash made before a destructive operation
+	operation      *operation
+	tabbed         bool // whether other repositories are open in tabs
+	confirm        *confirmation
+	pending        tea.Cmd // commands to run after the current update, see queue
+	job            *job    // bulk operation in progress
+	diffID         int     // identifies the latest diff load, older results are dropped
+	diffLoading    bool
+	searchID       int          // identifies the latest search of all the changes, like diffID
+	searching      bool         // a search of all the changes is running
+	diffMissing    bool         // the diff lacks blobs of a partial clone, see missingBlobs
+	snapshot       repoSnapshot // what the list was loaded from
+	logScroll      int          // commands scrolled past at the bottom of the command log
+	chord          []string     // keys of an unfinished key sequence
+	fileNumber     string       // digits typed so far to go to a file
+	bookmarks      map[string]bool
+	bookmarkedOnly bool              // list only bookmarked files
+	paths          pathDisplay       // how the list shows paths
+	notes          map[string]string // review notes by path, offered for the commit message
+	note           *noteEditor       // note being written
+	pathspec       *textinput.Model  // pathspec being typed, to stage what it matches
+	moving         *moveEditor       // new path being typed for a file
+	newBranch      *textinput.Model  // name being typed for a branch to create
+	rangeInput     *textinput.Model  // ranges being typed to range-diff
+	searchInput    *textinput.Model  // text being typed to search the diff for
+	exportInput    *textinput.Model  // path being typed to export the staged summary to
+	searchTerm     string            // what the diff is searched for, highlighted in it
+	match          int               // line of the diff the last search jump went to, -1 for none
+	gitNote        *noteEditor       // git note being written for a commit
+	editor         *textarea.Model   // commit message, kept when the commit is cancelled
+	committing     bool
+	generating     bool // running the commit message generator
+	picker         *picker
+	trailers       []trailer // of the commit being written
+	trailersEditor *trailersEditor
+	authorDate     authorDate // of the commit being written, git's own when empty
+	authorEditor   *authorDateEditor
+	issues         []issue // loaded the first time # is typed in the message
+	loadingIssues  bool
+	issueTyped     string         // the message when the issues started loading
+	hunk           int            // header of the hunk jumped to in diffLines, -1 for none
+	lineSelect     *lineSelection // lines of a hunk picked in the diff pane
+	review         *reviewBase    // base of the branch when reviewing it, see toggleReview
+	history        *fileHistory   // shown in historyMode
+	recovery       *recovery      // shown in recoveryMode
+	branchList     *branchList    // shown in branchesMode
+	rangeDiff      *rangeDiff     // shown in rangeDiffMode
+	recent         *recentCommits // shown in recentMode
+	cleanList      *cleanList     // shown in cleanMode
+	searchResults  *searchResults // shown in searchMode
+	changeSet      *changeSet     // shown in changeSetMode
+	settings       *settingsList  // shown in settingsMode
+	diffMore       int            // lines left out of diffLines after diffLineLimit
+	diffKey        string         // identifies the diff in the pane, see diffLimit
+	fullDiff       string         // the diff loaded without diffLineLimit
+	loading        bool           // the list is still being loaded, see Init
+	loadingBranch  bool
+	status         string // message shown above the footer
+	quitting       bool
+	handOff        bool         // quit to run git commit with the editor
+	showHelp       bool         // every key listed over the panes, until the next key
+	fileInfo       []string     // of the selected file, shown over the panes until the next key
+	tour           [][]string   // pages of the first-run tour still to show, see tourPages
+	sessionIndex   string       // the index as a tree when the session started
+	advance        *hunkAdvance // the hunk to scroll to once the diff reloads
+	folding        bool         // unchanged lines away from changes are folded, see foldContext
+}
+
+// A yes/no question shown in the status line, blocking other keys until answered
+type confirmation struct {
+	message string
+	onYes   func(m *model)
+	onNo    func(m *model) // optional
+	details []string       // optional explanation, shown in a dialog over the panes
+}
+
+var cfg = defaultConfig()
+
+// Styles are set from the selected theme by applyTheme
+var (
+	cursorStyle           lipgloss.Style
+	stagedStyle           lipgloss.Style
+	partiallyStagedStyle  lipgloss.Style
+	unstagedStyle         lipgloss.Style
+	diffAddedStyle        lipgloss.Style
+	diffRemovedStyle      lipgloss.Style
+	diffMovedAddedStyle   lipgloss.Style
+	diffMovedRemovedStyle lipgloss.Style
+	diffHunkStyle         lipgloss.Style
+	diffMetaStyle         lipgloss.Style
+	separatorStyle        lipgloss.Style
+	activeTabStyle        lipgloss.Style
+	promptStyle           lipgloss.Style
+	badgeStyle            lipgloss.Style
+	diffModeStyle         lipgloss.Style
+	conflictStyle         lipgloss.Style
+	sectionStyle          lipgloss.Style
+	dialogStyle           lipgloss.Style
+	selectedLineStyle     lipgloss.Style
+	syntaxKeywordStyle    lipgloss.Style
+	syntaxStringStyle     lipgloss.Style
+	syntaxNumberStyle     lipgloss.Style
+	syntaxCommentStyle    lipgloss.Style
+	searchMatchStyle      lipgloss.Style
+	helpStyles            help.Styles
+)
+
+// Below this width the diff pane is only shown in full screen
+const minSplitWidth = 60
+
+func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	cmd := m.update(msg)
+	m.syncDiffPane()
+	// Commands queued along the way, e.g. loading the diff or from a confirmation
+	cmd = tea.Batch(cmd, m.pending)
+	m.pending = nil
+	return m, cmd
+}
+
+func (m *model) update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.scrollDiff(0)
+		m.sizeEditor()
+	case mergetoolFinishedMsg:
+		m.mergetoolFinished(msg)
+	case launcherFinishedMsg:
+		m.launcherFinished(msg)
+	case operationFinishedMsg:
+		m.operationFinished(msg)
+	case jobStepMsg:
+		return m.jobStepFinished(msg)
+	case diffLoadedMsg:
+		m.diffLoaded(msg)
+	case changeSetLoadedMsg:
+		m.changeSetLoaded(msg)
+	case searchLoadedMsg:
+		m.searchLoaded(msg)
+	case statusProgressMsg:
+		m.statusProgress(msg)
+	case statusLoadedMsg:
+		m.statusLoaded(msg)
+	case branchLoadedMsg:
+		m.branchLoaded(msg)
+	case formatFinishedMsg:
+		m.formatFinished(msg)
+	case fetchFinishedMsg:
+		m.fetchFinished(msg)
+	case pushFinishedMsg:
+		m.pushFinished(msg)
+	case blobsFetchedMsg:
+		m.blobsFetched(msg)
+	case issuesLoadedMsg:
+		m.issuesLoaded(msg)
+	case commitFinishedMsg:
+		m.commitFinished(msg)
+	case messageGeneratedMsg:
+		m.messageGenerated(msg)
+	case watchTickMsg:
+		m.checkExternalChanges()
+	case tea.KeyMsg:
+		key := keyName(msg)
+		m.status = ""
+		if m.confirm != nil {
+			c := m.confirm
+			m.confirm = nil
+			if m.keys.confirmYes.matches(key) {
+				c.onYes(m)
+			} else if c.onNo != nil {
+				c.onNo(m)
+			}
+			return nil
+		}
+		if m.tour != nil {
+			m.nextTourPage(key)
+			return nil
+		}
+		// Text input takes every key but the ones to finish it
+		if m.note != nil {
+			m.updateNote(msg)
+			return nil
+		}
+		if m.pathspec != nil {
+			m.updatePathspec(msg)
+			return nil
+		}
+		if m.moving != nil {
+			m.updateMove(msg)
+			return nil
+		}
+		if m.newBranch != nil {
+			m.updateNewBranch(msg)
+			return nil
+		}
+		if m.rangeInput != nil {
+			m.updateRangeInput(msg)
+			return nil
+		}
+		if m.searchInput != nil {
+			m.updateSearchInput(msg)
+			return nil
+		}
+		if m.exportInput != nil {
+			m.updateExportPrompt(msg)
+			return nil
+		}
+		if m.gitNote != nil {
+			m.updateGitNote(msg)
+			return nil
+		}
+		if m.settings != nil && m.settings.input != nil {
+			m.updateSettingInput(msg)
+			return nil
+		}
+		if m.mode == commitMode {
+			return m.updateCommit(msg)
+		}
+		if key, m.chord = m.keys.sequence(m.chord, key); key == "" {
+			m.status = strings.Join(m.chord, " ") + " …"
+			return nil
+		}
+		if m.showHelp {
+			m.showHelp = false
+			return nil
+		}
+		if m.fileInfo != nil {
+			m.fileInfo = nil
+			return nil
+		}
+		if m.keys.help.matches(key) {
+			m.showHelp = true
+			return nil
+		}
+		if m.keys.quit.matches(key) {
+			m.quitting = true
+			return tea.Quit
+		}
+		if m.loading {
+			return nil
+		}
+		if m.job != nil {
+			// Moving around is fine, but the list shows what the job will do before
+			// it's done, so nothing else may change the repository meanwhile
+			switch {
+			case m.keys.cancel.matches(key):
+				m.cancelJob()
+				return nil
+			case !m.isNavigation(key):
+				m.status = tr("Wait for %s to finish or press %s to cancel", strings.ToLower(m.job.title), m.keys.cancel.Help().Key)
+				return nil
+			}
+		}
+		if (m.diffLoading || m.searching) && m.keys.cancel.matches(key) {
+			cancelGitCommands(m.repo.root)
+			return nil
+		}
+		if (m.mode == listMode || m.mode == diffMode || m.mode == historyMode) && m.keys.fullScreen.matches(key) {
+			m.fullScreenDiff = !m.fullScreenDiff
+			m.scrollDiff(0)
+			return nil
+		}
+		if (m.mode == listMode || m.mode == diffMode) && m.keys.preview.matches(key) {
+			m.togglePreview()
+			return nil
+		}
+		if (m.mode == listMode || m.mode == diffMode) && m.keys.fold.matches(key) {
+			m.toggleFolding()
+			return nil
+		}
+		if (m.mode == listMode || m.mode == diffMode) && m.keys.pager.matches(key) {
+			return m.openInPager()
+		}
+		if (m.mode == listMode || m.mode == diffMode) && m.keys.dualDiff.matches(key) {
+			m.toggleDualDiff()
+			return nil
+		}
+		if (m.mode == listMode || m.mode == diffMode) && m.keys.functionContext.matches(key) {
+			m.repo.functionContext = !m.repo.functionContext
+			m.loadDiff()
+			if m.repo.functionContext {
+				m.status = tr("Hunks show their whole function")
+			} else {
+				m.status = tr("Hunks show the usual context")
+			}
+			return nil
+		}
+		if (m.mode == listMode || m.mode == diffMode) && m.keys.ignoreCR.matches(key) {
+			m.repo.ignoreCR = !m.repo.ignoreCR
+			m.loadDiff()
+			if m.repo.ignoreCR {
+				m.status = tr("Diffs leave out CR at the end of lines")
+			} else {
+				m.status = tr("Diffs show changes of line endings")
+			}
+			return nil
+		}
+		switch m.mode {
+		case listMode:
+			return m.updateList(key)
+		case diffMode:
+			m.updateDiff(key)
+		case flagsMode:
+			m.updateFlags(key)
+		case logMode:
+			m.updateLog(key)
+		case historyMode:
+			m.updateHistory(key)
+		case recoveryMode:
+			m.updateRecovery(key)
+		case branchesMode:
+			m.updateBranches(key)
+		case rangeDiffMode:
+			m.updateRangeDiff(key)
+		case recentMode:
+			m.updateRecentCommits(key)
+		case cleanMode:
+			m.updateClean(key)
+		case searchMode:
+			m.updateSearchResults(key)
+		case changeSetMode:
+			return m.updateChangeSet(key)
+		case settingsMode:
+			m.updateSettings(key)
+		}
+	}
+	return nil
+}
+
+func (m *model) updateList(key string) tea.Cmd {
+	// Digits typed in a row make up a file number, see typeFileNumber
+	typed := m.fileNumber
+	m.fileNumber = ""
+	switch {
+	case m.keys.launches(key):
+		// Taking over what the key is bound to otherwise in the list
+		l, _ := m.keys.launcher(key)
+		return m.launch(l)
+	case m.keys.up.matches(key):
+		m.cursorUp()
+	case m.keys.down.matches(key):
+		m.cursorDown()
+	case m.keys.goToFile.matches(key):
+		m.typeFileNumber(typed + key)
+	case m.keys.nextDir.matches(key):
+		m.cursorDir(1)
+	case m.keys.prevDir.matches(key):
+		m.cursorDir(-1)
+	case m.keys.nextColumn.matches(key) && m.shownListColumns() > 1:
+		// Before focusDiff, which shares the arrow keys with it
+		m.cursorColumn(1)
+	case m.keys.prevColumn.matches(key) && m.shownListColumns() > 1:
+		m.cursorColumn(-1)
+	case m.keys.top.matches(key):
+		m.cursor = 0
+	case m.keys.bottom.matches(key):
+		m.cursor = max(len(m.rows())-1, 0)
+	case m.keys.popStash.matches(key):
+		m.popStash()
+	case m.keys.continueOp.matches(key):
+		return m.runOperation("continue")
+	case m.keys.abortOp.matches(key):
+		return m.runOperation("abort")
+	case m.keys.skipOp.matches(key):
+		return m.runOperation("skip")
+	case m.keys.showFlags.matches(key):
+		m.mode = flagsMode
+		m.flagCursor = 0
+		return nil
+	case m.keys.showLog.matches(key):
+		m.mode = logMode
+		m.logScroll = 0
+		return nil
+	case m.keys.recover.matches(key):
+		m.openRecovery()
+		return nil
+	case m.keys.restoreIndex.matches(key):
+		m.askToRestoreIndex()
+		return nil
+	case m.keys.branches.matches(key):
+		m.openBranches()
+		return nil
+	case m.keys.fetch.matches(key):
+		return m.fetch()
+	case m.keys.rangeDiff.matches(key):
+		m.openRangeDiffPrompt()
+		return nil
+	case m.keys.recentCommits.matches(key):
+		m.openRecentCommits()
+		return nil
+	case m.keys.settings.matches(key):
+		m.openSettings()
+		return nil
+	case m.keys.discardAll.matches(key):
+		m.discardAll()
+	case m.keys.clean.matches(key):
+		m.openClean()
+	case m.keys.focusDiff.matches(key):
+		m.mode = diffMode
+		return nil
+	case m.keys.bookmarkedOnly.matches(key):
+		m.toggleBookmarkedOnly()
+	case m.keys.pathDisplay.matches(key):
+		m.paths = m.paths.next()
+		m.status = m.paths.description()
+	case m.keys.commit.matches(key):
+		m.openCommitEditor()
+		return nil
+	case m.keys.commitInEditor.matches(key):
+		return m.handOffCommit()
+	case m.keys.review.matches(key):
+		m.toggleReview()
+	case m.keys.stageMatching.matches(key):
+		m.openPathspecPrompt()
+	case m.keys.searchAll.matches(key) && m.review != nil:
+		m.status = tr("Leave the branch review with %s to search the changes", m.keys.review.Help().Key)
+	case m.keys.searchAll.matches(key):
+		m.openSearch()
+	case m.keys.changeSet.matches(key):
+		m.openChangeSet()
+	case m.keys.export.matches(key):
+		m.openExportPrompt()
+	case len(m.rows()) == 0:
+		return nil
+	case m.keys.toggle.matches(key):
+		return m.toggleRows(m.rows()[m.cursor])
+	case m.keys.toggleAll.matches(key):
+		var all []listRow
+		for i, f := range m.files {
+			if m.listed(f) {
+				all = append(all, listRow{noSection, i})
+			}
+		}
+		return m.toggleRows(all...)
+	case m.keys.toggleNext.matches(key):
+		// Move first so the cursor stays on the next row once the toggled file changes section
+		row := m.rows()[m.cursor]
+		m.cursorDown()
+		return m.toggleRows(row)
+	case m.keys.togglePrev.matches(key):
+		row := m.rows()[m.cursor]
+		m.cursorUp()
+		return m.toggleRows(row)
+	case m.selected() < 0:
+		// The remaining actions apply to a single file, not a section header
+		return nil
+	case m.keys.fileInfo.matches(key):
+		m.fileInfo = getFileInfo(m.repo, m.files[m.selected()])
+	case m.keys.useOurs.matches(key):
+		m.resolveWith(m.selected(), "ours")
+	case m.keys.useTheirs.matches(key):
+		m.resolveWith(m.selected(), "theirs")
+	case m.keys.mergetool.matches(key):
+		return m.runMergetool(m.selected())
+	case m.keys.discard.matches(key):
+		m.discard(m.selected())
+	case m.keys.format.matches(key):
+		return m.format(m.selected())
+	case m.keys.move.matches(key):
+		m.editMove(m.selected())
+	case m.keys.toggleExec.matches(key):
+		m.toggleExecutable(m.selected())
+	case m.keys.bookmark.matches(key):
+		m.toggleBookmark(m.selected())
+	case m.keys.note.matches(key):
+		m.editNote(m.selected())
+	case m.keys.history.matches(key):
+		m.openHistory(m.selected())
+		return nil
+	case m.keys.stageMode.matches(key):
+		m.stageModeChange(m.selected(), true)
+	case m.keys.stageContent.matches(key):
+		m.stageModeChange(m.selected(), false)
+	case m.keys.assumeUnchanged.matches(key):
+		m.hideWithFlag(m.selected(), "assume-unchanged")
+	case m.keys.skipWorktree.matches(key):
+		m.hideWithFlag(m.selected(), "skip-worktree")
+	default:
+		return nil
+	}
+	m.loadDiff()
+	return nil
+}
+
+func (m *model) updateDiff(key string) {
+	if m.lineSelect != nil {
+		m.updateLineSelection(key)
+		return
+	}
+	switch {
+	case m.keys.scrollUp.matches(key):
+		m.scrollDiff(-1)
+	case m.keys.scrollDown.matches(key):
+		m.scrollDiff(1)
+	case m.keys.pageUp.matches(key):
+		m.scrollDiff(-m.bodyHeight())
+	case m.keys.pageDown.matches(key):
+		m.scrollDiff(m.bodyHeight())
+	case m.keys.top.matches(key):
+		m.pane.GotoTop()
+	case m.keys.bottom.matches(key):
+		m.scrollDiff(0)
+		m.pane.GotoBottom()
+	case m.keys.nextHunk.matches(key):
+		m.jumpToHunk(1)
+	case m.keys.prevHunk.matches(key):
+		m.jumpToHunk(-1)
+	case m.keys.toggleHunk.matches(key):
+		m.toggleHunk()
+	case m.keys.switchSide.matches(key):
+		m.switchDualSide()
+	case m.keys.selectLines.matches(key):
+		m.startLineSelection()
+	case m.keys.unfold.matches(key):
+		m.unfoldOnScreen()
+	case m.keys.search.matches(key):
+		m.openSearch()
+	case m.keys.nextMatch.matches(key):
+		m.jumpToMatch(1)
+	case m.keys.prevMatch.matches(key):
+		m.jumpToMatch(-1)
+	case m.keys.loadFullDiff.matches(key):
+		m.loadFullDiff()
+	case m.keys.focusList.matches(key):
+		m.mode = listMode
+	}
+}
+
+func (m *model) updateFlags(key string) {
+	switch {
+	case m.keys.up.matches(key):
+		m.flagCursor = max(m.flagCursor-1, 0)
+	case m.keys.down.matches(key):
+		m.flagCursor = min(m.flagCursor+1, max(len(m.flagged)-1, 0))
+	case m.keys.focusList.matches(key), m.keys.showFlags.matches(key):
+		m.mode = listMode
+		m.loadDiff()
+	case len(m.flagged) == 0:
+		return
+	case m.keys.assumeUnchanged.matches(key):
+		f := m.flagged[m.flagCursor]
+		m.setFlag(f.pathFromCwd, "assume-unchanged", !f.assumeUnchanged)
+	case m.keys.skipWorktree.matches(key):
+		f := m.flagged[m.flagCursor]
+		m.setFlag(f.pathFromCwd, "skip-worktree", !f.skipWorktree)
+	}
+}
+
+func (m *model) updateLog(key string) {
+	switch {
+	case m.keys.scrollUp.matches(key):
+		m.logScroll = min(m.logScroll+1, max(len(loggedCommands())-1, 0))
+	case m.keys.scrollDown.matches(key):
+		m.logScroll = max(m.logScroll-1, 0)
+	case m.keys.focusList.matches(key), m.keys.showLog.matches(key):
+		m.mode = listMode
+	}
+}
+
+// Set an index flag on a listed file, which hides it from git status
+func (m *model) hideWithFlag(index int, flag string) {
+	f := m.files[index]
+	if !isTracked(m.repo, f.pathFromCwd) {
+		m.status = tr("Only tracked files can be marked %s", flag)
+		return
+	}
+	m.ask(tr("Mark %s %s? Its changes will be hidden from git", f.pathFromGitRoot, flag), func(m *model) {
+		m.setFlag(f.pathFromCwd, flag, true)
+		m.loadDiff()
+	})
+}
+
+func (m *model) setFlag(path, flag string, on bool) {
+	if err := setIndexFlag(m.repo, path, flag, on); m.handleIndexLock(err) {
+		return
+	} else if err != nil {
+		m.status = err.Error()
+	}
+	m.reload()
+	m.flagCursor = min(m.flagCursor, max(len(m.flagged)-1, 0))
+}
+
+func (m *model) cursorUp() {
+	if m.cursor > 0 {
+		m.cursor--
+	}
+}
+
+func (m *model) cursorDown() {
+	if m.cursor < len(m.rows())-1 {
+		m.cursor++
+	}
+}
+
+// Keys that only move the cursor or scroll, which are fine while a job runs
+func (m model) isNavigation(key string) bool {
+	switch m.mode {
+	case listMode:
+		return m.keys.up.matches(key) || m.keys.down.matches(key) || m.keys.focusDiff.matches(key) ||
+			m.keys.nextColumn.matches(key) || m.keys.prevColumn.matches(key) ||
+			m.keys.fullScreen.matches(key) || m.keys.showLog.matches(key) || m.keys.top.matches(key) ||
+			m.keys.bottom.matches(key) || m.keys.nextDir.matches(key) || m.keys.prevDir.matches(key) ||
+			m.keys.goToFile.matches(key) || m.keys.fileInfo.matches(key)
+	case diffMode:
+		return m.keys.scrollUp.matches(key) || m.keys.scrollDown.matches(key) || m.keys.pageUp.matches(key) ||
+			m.keys.pageDown.matches(key) || m.keys.focusList.matches(key) || m.keys.fullScreen.matches(key) ||
+			m.keys.top.matches(key) || m.keys.bottom.matches(key) || m.keys.nextHunk.matches(key) ||
+			m.keys.prevHunk.matches(key) || m.keys.loadFullDiff.matches(key) || m.keys.search.matches(key) ||
+			m.keys.unfold.matches(key) || m.keys.nextMatch.matches(key) || m.keys.prevMatch.matches(key)
+	case logMode, historyMode, rangeDiffMode:
+		return true
+	case recoveryMode:
+		return !m.keys.restore.matches(key)
+	case branchesMode:
+		return !m.keys.switchBranch.matches(key) && !m.keys.newBranch.matches(key)
+	case recentMode:
+		return !m.keys.note.matches(key)
+	case cleanMode:
+		return !m.keys.deleteSelected.matches(key)
+	case searchMode:
+		return true
+	case changeSetMode:
+		return !m.keys.toggle.matches(key)
+	case settingsMode:
+		return !m.keys.editSetting.matches(key) && !m.keys.toggle.matches(key)
+	}
+	return false
+}
+
+// Run a command after the current update
+func (m *model) queue(cmd tea.Cmd) {
+	m.pending = tea.Batch(m.pending, cmd)
+}
+
+func (m *model) ask(message string, onYes func(m *model)) {
+	m.confirm = &confirmation{message: message, onYes: onYes}
+}
+
+// Stage or unstage the files of rows, asking first if that would stage what
+// looks like secrets or files outside the sparse-checkout cone. Rows in the
+// staged section unstage, others stage, and rows of the flat list flip the
+// file's status.
+func (m *model) toggleRows(rows ...listRow) tea.Cmd {
+	var stagePaths, sparsePaths, unstagePaths []string
+	var staging []fileEntry
+	expected := make(map[int]bool)
+	for _, row := range rows {
+		for _, i := range m.rowFiles(row) {
+			f := m.files[i]
+			if f.status == committed {
+				// Nothing in the index or work tree to stage
+				continue
+			}
+			stage := row.section != stagedSection
+			if row.section == noSection {
+				stage = f.status != staged
+			}
+			expected[i] = stage
+			if stage {
+				staging = append(staging, f)
+			}
+			switch {
+			case !stage:
+				unstagePaths = append(unstagePaths, f.pathFromCwd)
+				if f.origPathFromCwd != "" {
+					// The old path comes back to the index too
+					unstagePaths = append(unstagePaths, f.origPathFromCwd)
+				}
+			case f.outsideSparse:
+				sparsePaths = append(sparsePaths, f.pathFromCwd)
+			default:
+				stagePaths = append(stagePaths, f.pathFromCwd)
+			}
+		}
+	}
+
+	if len(expected) == 0 {
+		m.status = tr("Changes committed on the branch can't be staged or unstaged")
+		return nil
+	}
+
+	r := m.repo
+	var steps []jobStep
+	steps = append(steps, batchSteps(unstagePaths, func(paths []string) error {
+		return r.backend.Unstage(paths...)
+	})...)
+	steps = append(steps, batchSteps(stagePaths, func(paths []string) error {
+		return r.backend.Stage(paths...)
+	})...)
+	steps = append(steps, batchSteps(sparsePaths, func(paths []string) error {
+		return r.backend.StageSparse(paths...)
+	})...)
+	title := tr("Toggling")
+	switch {
+	case len(unstagePaths) == 0:
+		title = tr("Staging")
+	case len(stagePaths)+len(sparsePaths) == 0:
+		title = tr("Unstaging")
+	}
+
+	// Show the result right away, the job puts the list back if git fails
+	start := func(m *model) tea.Cmd {
+		rollback := slices.Clone(m.files)
+		m.keepingCursor(func() {
+			for i, stage := range expected {
+				m.files[i] = m.files[i].toggled(stage)
+			}
+		})
+		cmd := m.startJob(title, steps, nil)
+		if m.job != nil {
+			m.job.rollback = rollback
+		}
+		return cmd
+	}
+	askSparse := func(m *model) tea.Cmd {
+		if len(sparsePaths) == 0 || !cfg.Sparse.Warn {
+			return start(m)
+		}
+		m.ask(tr("%d file(s) outside the sparse-checkout cone will be added to the index, continue?", len(sparsePaths)), func(m *model) {
+			m.queue(start(m))
+		})
+		return nil
+	}
+	if secrets := findStagedSecrets(m.repo, staging); len(secrets) > 0 {
+		m.askAboutSecrets(secrets, func(m *model) {
+			m.queue(askSparse(m))
+		})
+		return nil
+	}
+	return askSparse(m)
+}
+
+// Drop the unstaged changes of a file, or delete it if untracked, after confirmation
+func (m *model) discard(index int) {
+	f := m.files[index]
+	if f.status == staged || f.status == committed {
+		m.status = tr("No unstaged changes to discard in %s", f.pathFromGitRoot)
+		return
+	}
+	m.ask(tr("Discard unstaged changes to %s?", f.pathFromGitRoot), func(m *model) {
+		if err := saveToTrash(m.repo, []string{f.pathFromGitRoot}); err != nil {
+			m.status = tr("Aborted, failed to copy the files to the trash: %v", err)
+			return
+		}
+		if err := discardFile(m.repo, f); m.handleIndexLock(err) {
+			return
+		} else if err != nil {
+			m.status = tr("Failed to discard %s: %v", f.pathFromGitRoot, err)
+		} else {
+			m.status = tr("Discarded, the old content is in the trash, %s to recover it", m.keys.recover.Help().Key)
+		}
+		m.reload()
+		m.loadDiff()
+	})
+}
+
+// Stage either only the executable bit change of a file or only its content
+func (m *model) stageModeChange(index int, modeOnly bool) {
+	f := m.files[index]
+	c := f.modeChange
+	if !c.unstaged || (c.String() != "+x" && c.String() != "-x") {
+		m.status = tr("No executable bit change to stage separately")
+		return
+	}
+
+	if modeOnly {
+		if err := setIndexMode(m.repo, f.pathFromGitRoot, c.to); m.handleIndexLock(err) {
+			return
+		} else if err != nil {
+			m.status = tr("Failed to stage the mode of %s", f.pathFromGitRoot)
+			return
+		}
+	} else {
+		// Stage everything, then put the index mode back to what it was
+		revert := "-x"
+		if c.from == "100755" {
+			revert = "+x"
+		}
+		err := m.repo.backend.Stage(f.pathFromCwd)
+		if err == nil {
+			err = m.repo.run("update-index", "--chmod="+revert, "--", f.pathFromCwd)
+		}
+		if m.handleIndexLock(err) {
+			return
+		}
+	}
+	m.reload()
+}
+
+// Flip the executable bit of a file in the work tree and stage the mode change
+func (m *model) toggleExecutable(index int) {
+	f := m.files[index]
+	if f.symlink {
+		m.status = tr("Symlinks have no executable bit")
+		return
+	}
+	path := filepath.Join(m.repo.root, f.pathFromGitRoot)
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		m.status = tr("Can't change the mode of %s", f.pathFromGitRoot)
+		return
+	}
+
+	perm := info.Mode().Perm()
+	executable := perm&0o111 == 0
+	indexMode := "100644"
+	if executable {
+		// Like chmod +x, grant execute to whoever can read
+		perm |= (perm & 0o444) >> 2
+		indexMode = "100755"
+	} else {
+		perm &^= 0o111
+	}
+	if err := os.Chmod(path, perm); err != nil {
+		m.status = tr("Failed to change the mode of %s: %v", f.pathFromGitRoot, err)
+		return
+	}
+
+	if !isTracked(m.repo, f.pathFromCwd) {
+		m.status = tr("Changed the mode of %s, stage it to record the mode", f.pathFromGitRoot)
+	} else if err := setIndexMode(m.repo, f.pathFromGitRoot, indexMode); err != nil {
+		if !m.handleIndexLock(err) {
+			m.status = tr("Changed the mode of %s but failed to stage it", f.pathFromGitRoot)
+		}
+	} else if executable {
+		m.status = tr("Made %s executable", f.pathFromGitRoot)
+	} else {
+		m.status = tr("Made %s non-executable", f.pathFromGitRoot)
+	}
+	m.reload()
+}
+
+// Reload the file list and repository state from git
+func (m *model) reload() {
+	m.applyStatus(getRepoStatus(m.repo, m.review != nil, nil))
+	m.applyBranchInfo(getBranchInfo(m.repo))
+}
+
+func (m model) View() string {
+	if m.quitting {
+		return ""
+	}
+
+	var body string
+	height := m.bodyHeight()
+	switch {
+	case m.confirm != nil && len(m.confirm.details) > 0:
+		body = m.dialogView(m.width, height)
+	case m.showHelp:
+		body = m.helpView(m.width, height)
+	case m.fileInfo != nil:
+		body = m.fileInfoView(m.width, height)
+	case m.tour != nil:
+		body = m.tourView(m.width, height)
+	case m.mode == flagsMode:
+		body = m.flagsView(m.width, height)
+	case m.mode == logMode:
+		body = m.commandLogView(m.width, height)
+	case m.mode == commitMode:
+		body = m.commitView(m.width, height)
+	case m.mode == historyMode:
+		body = m.historyView(m.width, height)
+	case m.mode == recoveryMode:
+		body = m.recoveryView(m.width, height)
+	case m.mode == branchesMode:
+		body = m.branchesView(m.width, height)
+	case m.mode == rangeDiffMode:
+		body = m.rangeDiffView(m.width, height)
+	case m.mode == recentMode:
+		body = m.recentCommitsView(m.width, height)
+	case m.mode == cleanMode:
+		body = m.cleanView(m.width, height)
+	case m.mode == searchMode:
+		body = m.searchResultsView(m.width, height)
+	case m.mode == changeSetMode:
+		body = m.changeSetView(m.width, height)
+	case m.mode == settingsMode:
+		body = m.settingsView(m.width, height)
+	case m.idle() && m.mode == listMode:
+		body = lipgloss.Place(m.width, height, lipgloss.Center, lipgloss.Center,
+			separatorStyle.Render(tr("Nothing to stage, watching for changes…")))
+	case m.height == 0:
+		// Window size is not known yet
+		body = m.listView(m.width, len(m.rows()))
+	case m.fullScreenDiff:
+		body = m.diffView(m.width, height)
+	case m.width < minSplitWidth:
+		body = m.listView(m.width, height)
+	default:
+		body = m.sideBySide(m.width, height, m.listPaneWidth(), m.listView)
+	}
+	if m.height > 0 {
+		body = lipgloss.NewStyle().Height(height).MaxHeight(height).Render(body)
+	}
+
+	var b strings.Builder
+	b.WriteString(m.header() + "\n")
+	if m.operation != nil {
+		b.WriteString(ansi.Truncate(m.operationBanner(), max(m.width, 1), "…") + "\n")
+	}
+	b.WriteString(body)
+	b.WriteString("\n" + m.statusLine())
+	if cfg.ShowFooter {
+		b.WriteString("\n" + renderFooter(m.footerBindings(), m.width))
+	}
+	return b.String()
+}
+
+// A list next to the diff pane, separated by a vertical line
+func (m model) sideBySide(width, height, listWidth int, listView func(width, height int) string) string {
+	list := lipgloss.NewStyle().Width(listWidth).Render(listView(listWidth, height))
+	separator := separatorStyle.Render(strings.TrimSuffix(strings.Repeat("│\n", height), "\n"))
+	return lipgloss.JoinHorizontal(lipgloss.Top, list, separator, m.diffView(width-listWidth-1, height))
+}
+
+// Keys that do something right now, hinted at in the footer
+func (m model) footerBindings() []keyBinding {
+	if m.confirm != nil {
+		return []keyBinding{m.keys.confirmYes, m.keys.confirmNo}
+	} else if m.job != nil {
+		return []keyBinding{m.keys.cancel, m.keys.quit}
+	}
+	return m.keys.footerBindings(footerContext{
+		mode:      m.mode,
+		tabbed:    m.tabbed,
+		conflict:  m.selected() >= 0 && m.files[m.selected()].status == conflicted,
+		generator: cfg.Commit.Generator != "",
+		gitmoji:   cfg.Commit.Gitmoji != "",
+		selecting: m.lineSelect != nil,
+		truncated: m.diffMore > 0,
+		columns:   m.shownListColumns() > 1,
+		dual:      m.dualShown(),
+		searching: m.searchTerm != "",
+		folding:   m.folding,
+	})
+}
+
+// Every key of the footer, in columns as high as the body allows
+func (m model) helpView(width, height int) string {
+	bindings := translatedBindings(m.footerBindings())
+	// The box takes two lines of border and one of title
+	rows := max(height-3, 1)
+	var columns [][]key.Binding
+	for chunk := range slices.Chunk(bindings, rows) {
+		columns = append(columns, chunk)
+	}
+	h := newHelp(width - 4)
+	h.FullSeparator = "   "
+	box := dialogStyle.Render(promptStyle.Render(tr("Keys")) + "\n" + h.FullHelpView(columns))
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, box)
+}
+
+// The details of the pending confirmation in a box over the panes, the
+// first line being its title
+func (m model) dialogView(width, height int) string {
+	lines := slices.Clone(m.confirm.details)
+	lines[0] = promptStyle.Render(lines[0])
+	box := dialogStyle.Render(strings.Join(lines, "\n"))
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, box)
+}
+
+func (m model) fileInfoView(width, height int) string {
+	lines := slices.Clone(m.fileInfo)
+	lines[0] = promptStyle.Render(lines[0])
+	box := dialogStyle.Render(strings.Join(lines, "\n"))
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, box)
+}
+
+func (m model) header() string {
+	var parts []string
+	if m.branch != "" {
+		parts = append(parts, cursorStyle.Render(m.branch))
+		if !strings.HasPrefix(m.branch, "(") {
+			parts = append(parts, m.upstreamBadge())
+		}
+	}
+	if summary := m.summary(); summary != "" {
+		parts = append(parts, summary)
+	}
+	if m.stash.count > 0 {
+		parts = append(parts, tr("%d stash(es), %s to pop", m.stash.count, m.keys.popStash.Help().Key))
+	}
+	if m.safetyStash != "" {
+		parts = append(parts, tr("safety stash %s", promptStyle.Render(shortHash(m.safetyStash))))
+	}
+	if m.bookmarkedOnly {
+		parts = append(parts, promptStyle.Render(tr("showing %d bookmarked", m.bookmarkCount())))
+	}
+	if m.review != nil {
+		parts = append(parts, promptStyle.Render(m.reviewBadge()))
+	}
+	line := strings.Join(parts, separatorStyle.Render(" · "))
+	if m.width > 0 {
+		line = ansi.Truncate(line, m.width, "…")
+	}
+	return line
+}
+
+// Counts of files by section, e.g. "3 staged · 7 unstaged · 2 untracked · 1 conflict"
+func (m model) summary() string {
+	counts := make(map[section]int)
+	for _, f := range m.files {
+		for _, s := range f.sections() {
+			counts[s]++
+		}
+	}
+	var parts []string
+	if n := counts[stagedSection]; n > 0 {
+		parts = append(parts, stagedStyle.Render(tr("%d staged", n)))
+	}
+	if n := counts[unstagedSection]; n > 0 {
+		parts = append(parts, partiallyStagedStyle.Render(tr("%d unstaged", n)))
+	}
+	if n := counts[untrackedSection]; n > 0 {
+		parts = append(parts, unstagedStyle.Render(tr("%d untracked", n)))
+	}
+	if n := counts[branchSection]; n > 0 {
+		parts = append(parts, tr("%d on the branch", n))
+	}
+	if n := counts[conflictsSection]; n == 1 {
+		parts = append(parts, conflictStyle.Render(tr("1 conflict")))
+	} else if n > 1 {
+		parts = append(parts, conflictStyle.Render(tr("%d conflicts", n)))
+	}
+	return strings.Join(parts, separatorStyle.Render(" · "))
+}
+
+func (m model) statusLine() string {
+	var line string
+	if m.confirm != nil {
+		line = promptStyle.Render(m.confirm.message + " [y/N]")
+	} else if m.note != nil {
+		return m.noteView()
+	} else if m.pathspec != nil {
+		return m.pathspecView()
+	} else if m.moving != nil {
+		return m.moveView()
+	} else if m.newBranch != nil {
+		return m.newBranchView()
+	} else if m.rangeInput != nil {
+		return m.rangeInputView()
+	} else if m.searchInput != nil {
+		return m.searchInputView()
+	} else if m.exportInput != nil {
+		return m.exportPromptView()
+	} else if m.gitNote != nil {
+		return m.gitNoteView()
+	} else if m.settings != nil && m.settings.input != nil {
+		return m.settingInputView()
+	} else if m.status != "" {
+		line = m.status
+	} else if m.loading && len(m.files) > 0 {
+		line = badgeStyle.Render(tr("Loading changes, %d so far…", len(m.files)))
+	} else if m.loading {
+		line = badgeStyle.Render(tr("Loading changes…"))
+	} else if m.job != nil {
+		line = m.jobProgress()
+	} else if len(m.flagged) > 0 && m.mode == listMode {
+		line = badgeStyle.Render(tr("%d file(s) hidden by skip-worktree or assume-unchanged, press %s to show",
+			len(m.flagged), m.keys.showFlags.Help().Key))
+	}
+	if m.width > 0 {
+		line = ansi.Truncate(line, m.width, "…")
+	}
+	return line
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// A new path being typed for a file to git mv it, shown in the status line
+type moveEditor struct {
+	from  string // from the root
+	input textinput.Model
+}
+
+// Start typing where to move a file, from the root like the list shows it
+func (m *model) editMove(index int) {
+	from := m.files[index].currentPath()
+	input := textinput.New()
+	input.Prompt = tr("Move %s to: ", from)
+	input.PromptStyle = promptStyle
+	input.Cursor.SetMode(cursor.CursorStatic)
+	input.SetValue(from)
+	input.Focus()
+	m.moving = &moveEditor{from, input}
+}
+
+func (m *model) updateMove(msg tea.KeyMsg) {
+	switch key := keyName(msg); {
+	case key == "enter":
+		from, to := m.moving.from, filepath.ToSlash(filepath.Clean(strings.TrimSpace(m.moving.input.Value())))
+		m.moving = nil
+		if to != "." && to != from {
+			m.move(from, to)
+		}
+	case m.keys.cancel.matches(key):
+		m.moving = nil
+	default:
+		m.moving.input, _ = m.moving.input.Update(msg)
+	}
+}
+
+func (m model) moveView() string {
+	m.moving.input.Width = max(m.width-ansi.StringWidth(m.moving.input.Prompt)-1, 0)
+	return m.moving.input.View()
+}
+
+// git mv a file, making the directories it moves into, and keep the cursor
+// on it once the list shows the staged rename
+func (m *model) move(from, to string) {
+	if strings.HasPrefix(to, "../") || filepath.IsAbs(to) {
+		m.status = tr("%s is outside the repository", to)
+		return
+	}
+	if dir := filepath.Dir(filepath.Join(m.repo.root, to)); !fileExists(dir) {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			m.status = tr("Failed to move %s: %v", from, err)
+			return
+		}
+	}
+	err := m.repo.atRoot().run("mv", "--", from, to)
+	if m.handleIndexLock(err) {
+		return
+	}
+	if err != nil {
+		m.status = tr("Failed to move %s: %v", from, err)
+		return
+	}
+	m.reload()
+	for i, row := range m.rows() {
+		if row.file >= 0 && m.files[row.file].currentPath() == to {
+			m.cursor = i
+			break
+		}
+	}
+	m.status = tr("Moved %s to %s", from, to)
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Letters and digits a block of moved lines needs to be shown as moved, like
+// git's --color-moved, so braces and blank lines don't count on their own
+const movedMinAlnum = 20
+
+// Lines removed and added anywhere in a diff, by their content
+type changedLines struct {
+	removed map[string]bool
+	added   map[string]bool
+}
+
+func collectChangedLines(output []byte) changedLines {
+	c := changedLines{map[string]bool{}, map[string]bool{}}
+	for _, line := range strings.Split(string(output), "\n") {
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+		case strings.HasPrefix(line, "+"):
+			c.added[line[1:]] = true
+		case strings.HasPrefix(line, "-"):
+			c.removed[line[1:]] = true
+		}
+	}
+	return c
+}
+
+// The lines changed on the same side as the diff of f in section s, across
+// all files, so lines moved between files are found too. Without context,
+// and up to diffLineLimit like the diff pane.
+func movedCandidates(r repo, f fileEntry, s section, review *reviewBase) changedLines {
+	var output []byte
+	switch {
+	case review != nil:
+		output, _ = r.limitedDiff(diffLineLimit, "-U0", "--no-relative", review.commit)
+	case s == stagedSection, s == noSection && f.status == staged:
+		output, _ = r.limitedDiff(diffLineLimit, "-U0", "--no-relative", "--cached")
+	case s == noSection && f.status == partiallyStaged:
+		output, _ = r.limitedDiff(diffLineLimit, "-U0", "--no-relative", "--cached")
+		unstaged, _ := r.limitedDiff(diffLineLimit, "-U0", "--no-relative")
+		output = append(output, unstaged...)
+	default:
+		output, _ = r.limitedDiff(diffLineLimit, "-U0", "--no-relative")
+	}
+	return collectChangedLines(output)
+}
+
+// Mark the removed lines that were added elsewhere and the added lines that
+// were removed elsewhere, in blocks of consecutive lines, leaving out blocks
+// too small to tell a move from a coincidence
+func markMoved(lines []diffLine, changed changedLines) {
+	for start := 0; start < len(lines); {
+		sign, ok := movedSign(lines[start], changed)
+		if !ok {
+			start++
+			continue
+		}
+		end, alnum := start, 0
+		for end < len(lines) {
+			s, ok := movedSign(lines[end], changed)
+			if !ok || s != sign {
+				break
+			}
+			alnum += countAlnum(lines[end].text)
+			end++
+		}
+		if alnum >= movedMinAlnum {
+			for i := start; i < end; i++ {
+				lines[i].moved = true
+			}
+		}
+		start = end
+	}
+}
+
+// Whether a line of a regular hunk was removed or added with its content
+// changed the other way elsewhere
+func movedSign(l diffLine, changed changedLines) (byte, bool) {
+	if l.columns != 1 || l.text == "" {
+		return 0, false
+	}
+	content := l.text[1:]
+	switch l.text[0] {
+	case '-':
+		return '-', changed.added[content]
+	case '+':
+		return '+', changed.removed[content]
+	}
+	return 0, false
+}
+
+func countAlnum(s string) int {
+	n := 0
+	for _, r := range s {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			n++
+		}
+	}
+	return n
+}
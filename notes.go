@@ -0,0 +1,73 @@
+package main
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// A note being written for a file, shown in the status line
+type noteEditor struct {
+	path  string
+	input textinput.Model
+}
+
+// Start writing a review note for a file, or editing the one it has
+func (m *model) editNote(index int) {
+	path := m.files[index].pathFromGitRoot
+	input := textinput.New()
+	input.Prompt = tr("Note for %s: ", path)
+	input.PromptStyle = promptStyle
+	input.Cursor.SetMode(cursor.CursorStatic)
+	input.SetValue(m.notes[path])
+	input.Focus()
+	m.note = &noteEditor{path, input}
+}
+
+func (m *model) updateNote(msg tea.KeyMsg) {
+	switch key := keyName(msg); {
+	case key == "enter":
+		note := strings.TrimSpace(m.note.input.Value())
+		if m.notes == nil {
+			m.notes = make(map[string]string)
+		}
+		if note == "" {
+			delete(m.notes, m.note.path)
+		} else {
+			m.notes[m.note.path] = note
+		}
+		m.note = nil
+	case m.keys.cancel.matches(key):
+		m.note = nil
+	default:
+		m.note.input, _ = m.note.input.Update(msg)
+	}
+}
+
+func (m model) noteView() string {
+	m.note.input.Width = max(m.width-ansi.StringWidth(m.note.input.Prompt)-1, 0)
+	return m.note.input.View()
+}
+
+func (m model) noteBadge(f fileEntry) string {
+	note := m.notes[f.pathFromGitRoot]
+	if note == "" {
+		return ""
+	}
+	return " " + badgeStyle.Render("✎ "+note)
+}
+
+// Notes of the given files as bullet points for a commit message
+func (m model) noteBullets(paths []string) string {
+	var bullets []string
+	for _, path := range slices.Sorted(slices.Values(paths)) {
+		if note := m.notes[path]; note != "" {
+			bullets = append(bullets, "- "+path+": "+note)
+		}
+	}
+	return strings.Join(bullets, "\n")
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// No config file yet, so the first tab starts with the tour of the keys
+var firstRun bool
+
+func isFirstRun() bool {
+	path := configPath()
+	if path == "" {
+		return false
+	}
+	_, err := os.Stat(path)
+	return os.IsNotExist(err)
+}
+
+// Pages of the tour, each a title and lines naming the keys as the keymap
+// binds them
+func tourPages(k keyMap) [][]string {
+	help := func(b keyBinding) string { return b.Help().Key }
+	return [][]string{
+		{
+			tr("Welcome to git-istage"),
+			"",
+			tr("%s and %s move between files, and the diff of the one", help(k.down), help(k.up)),
+			tr("under the cursor shows next to the list."),
+			tr("%s stages or unstages it, %s all of them.", help(k.toggle), help(k.toggleAll)),
+			tr("%s commits what is staged.", help(k.commit)),
+		},
+		{
+			tr("Hunks and lines"),
+			"",
+			tr("%s focuses the diff, %s and %s jump between its hunks.", help(k.focusDiff), help(k.nextHunk), help(k.prevHunk)),
+			tr("%s stages or unstages the hunk under the cursor,", help(k.toggleHunk)),
+			tr("%s selects lines of it to stage on their own.", help(k.selectLines)),
+			tr("%s goes back to the list.", help(k.focusList)),
+		},
+		{
+			tr("Finding your way"),
+			"",
+			tr("%s shows every key, the footer the most useful ones.", help(k.help)),
+			tr("%s searches the changes, %s recovers what was discarded.", help(k.searchAll), help(k.recover)),
+			tr("%s lists the settings to change.", help(k.settings)),
+		},
+	}
+}
+
+// Go to the next page of the tour, or skip the rest with cancel, then offer
+// to write the config file for the tour not to show again
+func (m *model) nextTourPage(key string) {
+	m.tour = m.tour[1:]
+	if m.keys.cancel.matches(key) {
+		m.tour = nil
+	}
+	if len(m.tour) > 0 {
+		return
+	}
+	m.tour = nil
+	path := configPath()
+	m.confirm = &confirmation{
+		message: tr("Write a default config file to %s?", path),
+		details: []string{
+			tr("A config file"),
+			"",
+			tr("Settings are kept in %s,", path),
+			tr("changed with %s or git-istage config set.", m.keys.settings.Help().Key),
+			tr("Without one this tour shows again on the next start."),
+		},
+		onYes: func(m *model) {
+			if err := writeDefaultConfig(path); err != nil {
+				m.status = tr("Failed to write the config file: %v", err)
+				return
+			}
+			m.status = tr("Wrote the default config to %s", path)
+		},
+	}
+}
+
+// The defaults spelled out, for editing in place
+func writeDefaultConfig(path string) error {
+	var b bytes.Buffer
+	b.WriteString("# git-istage config, see the README for what each setting does\n\n")
+	e := toml.NewEncoder(&b)
+	e.Indent = ""
+	if err := e.Encode(defaultConfig()); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	// Not over a config file written meanwhile
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(b.Bytes()); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// The current page over the panes, with how to go on
+func (m model) tourView(width, height int) string {
+	lines := slices.Clone(m.tour[0])
+	lines[0] = promptStyle.Render(lines[0])
+	hint := tr("any key for more, %s to skip", m.keys.cancel.Help().Key)
+	if len(m.tour) == 1 {
+		hint = tr("any key to finish")
+	}
+	lines = append(lines, "", separatorStyle.Render(hint))
+	box := dialogStyle.Render(strings.Join(lines, "\n"))
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, box)
+}
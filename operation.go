@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// A multi-step git operation that staging is often part of
+type operation struct {
+	name    string // as shown in the banner
+	command []string
+	canSkip bool
+}
+
+// Detect an operation in progress from the state files git keeps in the git dir
+func getOperation(r repo) *operation {
+	exists := func(name string) bool {
+		_, err := os.Stat(filepath.Join(r.gitDir, name))
+		return err == nil
+	}
+	switch {
+	case exists("rebase-merge"), exists("rebase-apply/rebasing"):
+		return &operation{tr("Rebase"), []string{"rebase"}, true}
+	case exists("rebase-apply"):
+		return &operation{"git am", []string{"am"}, true}
+	case exists("MERGE_HEAD"):
+		return &operation{tr("Merge"), []string{"merge"}, false}
+	case exists("CHERRY_PICK_HEAD"):
+		return &operation{tr("Cherry-pick"), []string{"cherry-pick"}, true}
+	case exists("REVERT_HEAD"):
+		return &operation{tr("Revert"), []string{"revert"}, true}
+	}
+	return nil
+}
+
+type operationFinishedMsg struct {
+	action string
+	err    error
+}
+
+// Run --continue, --abort or --skip of the operation in progress. Continuing
+// may open an editor for the commit message, so git gets the terminal.
+func (m *model) runOperation(action string) tea.Cmd {
+	op := m.operation
+	if op == nil || (action == "skip" && !op.canSkip) {
+		return nil
+	}
+	args := append(op.command, "--"+action)
+	run := func() tea.Cmd {
+		return execGit(m.repo.interactiveGit(args...), func(err error) tea.Msg {
+			return operationFinishedMsg{action, err}
+		})
+	}
+	if action == "continue" {
+		return run()
+	}
+
+	// Aborting and skipping throw work away, so ask first. The confirmation
+	// callback can't return a command, so it's queued for the next update.
+	m.ask(fmt.Sprintf("%s --%s?", op.name, action), func(m *model) {
+		m.queue(run())
+	})
+	return nil
+}
+
+func (m *model) operationFinished(msg operationFinishedMsg) {
+	name := tr("Operation")
+	if m.operation != nil {
+		name = m.operation.name
+	}
+	m.reload()
+	m.loadDiff()
+	if msg.err != nil {
+		m.status = tr("%s --%s failed, see the output above or run it from the shell", name, msg.action)
+	} else if m.operation == nil {
+		m.status = tr("%s finished", name)
+	}
+}
+
+func (m model) operationBanner() string {
+	op := m.operation
+	bindings := []keyBinding{m.keys.continueOp, m.keys.abortOp}
+	if op.canSkip {
+		bindings = append(bindings, m.keys.skipOp)
+	}
+	return conflictStyle.Render(tr("%s in progress", op.name)) + "  " + renderFooter(bindings, 0)
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"math"
+	"os"
+	"os/exec"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Characters that make git run a pager with sh rather than split it into words
+const shellMetacharacters = "|&;<>()$`*?[]#~\n"
+
+// Split a command into words like sh does with quotes and backslashes. Not
+// ok for a command that needs a shell, like a pipeline or a variable.
+func splitShellWords(command string) ([]string, bool) {
+	var words []string
+	var word strings.Builder
+	inWord, quote := false, rune(0)
+	runes := []rune(command)
+	for i := 0; i < len(runes); i++ {
+		c := runes[i]
+		switch {
+		case quote == '\'' && c == '\'', quote == '"' && c == '"':
+			quote = 0
+		case quote == '\'':
+			word.WriteRune(c)
+		case c == '\\' && i+1 < len(runes) && (quote == 0 || strings.ContainsRune("\"\\$`", runes[i+1])):
+			i++
+			word.WriteRune(runes[i])
+			inWord = true
+		case quote == '"' && strings.ContainsRune("$`", c):
+			return nil, false
+		case quote == '"':
+			word.WriteRune(c)
+		case c == '\'' || c == '"':
+			quote, inWord = c, true
+		case c == ' ' || c == '\t':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		case strings.ContainsRune(shellMetacharacters, c) || c == '\\':
+			return nil, false
+		default:
+			word.WriteRune(c)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, false
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, true
+}
+
+// The pager git would use, from GIT_PAGER, core.pager or PAGER, less if none
+// is set. Empty if paging is turned off with cat or an empty pager.
+func getPager(r repo) string {
+	pager := trimmedOutput(r.git("var", "GIT_PAGER").Output())
+	if pager == "cat" {
+		return ""
+	}
+	return pager
+}
+
+// The pager with its arguments, run with sh only when it needs a shell
+func pagerCommand(pager string) *exec.Cmd {
+	cmd := exec.Command("sh", "-c", pager)
+	if words, ok := splitShellWords(pager); ok && len(words) > 0 {
+		cmd = exec.Command(words[0], words[1:]...)
+	}
+	// Like git sets them, but less is left to wait for q even if the diff
+	// fits the screen, it would be back to the UI at once otherwise
+	cmd.Env = os.Environ()
+	if _, ok := os.LookupEnv("LESS"); !ok {
+		cmd.Env = append(cmd.Env, "LESS=RX")
+	}
+	if _, ok := os.LookupEnv("LV"); !ok {
+		cmd.Env = append(cmd.Env, "LV=-c")
+	}
+	return cmd
+}
+
+// The whole diff the diff pane shows, colored like there
+func pagedDiff(lines []string) string {
+	var b strings.Builder
+	for _, l := range parseDiff(lines) {
+		b.WriteString(renderDiffLine(l, math.MaxInt) + "\n")
+	}
+	return b.String()
+}
+
+// Suspend the UI and read the diff of the selected row in the pager git
+// uses, unlimited and unfolded
+func (m *model) openInPager() tea.Cmd {
+	row, ok := m.selectedRow()
+	if !ok || row.file < 0 {
+		return nil
+	}
+	pager := getPager(m.repo)
+	if pager == "" {
+		m.status = tr("Paging is turned off, set core.pager to read diffs in a pager")
+		return nil
+	}
+	r, f, s, review := m.repo, m.files[row.file], m.diffSection(row), m.review
+	return func() tea.Msg {
+		var lines []string
+		if review != nil {
+			lines, _ = getReviewDiff(r, f, *review, -1)
+		} else {
+			lines, _ = getFileDiff(r, f, s, -1)
+		}
+		cmd := pagerCommand(pager)
+		cmd.Dir = r.root
+		cmd.Stdin = strings.NewReader(pagedDiff(lines))
+		return tea.ExecProcess(cmd, func(err error) tea.Msg {
+			return launcherFinishedMsg{pager, err}
+		})()
+	}
+}
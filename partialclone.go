@@ -0,0 +1,114 @@
+package main
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type blobsFetchedMsg struct {
+	root string
+	oids []string
+	err  error
+}
+
+// The remote a partial clone fetches the objects it lacks from, empty for
+// a full clone
+func promisorRemote(r repo) string {
+	if output, err := r.git("config", "extensions.partialClone").Output(); err == nil {
+		return strings.TrimSpace(string(output))
+	}
+	output, _ := r.git("config", "--get-regexp", `^remote\..*\.promisor$`).Output()
+	for _, line := range splitDiffLines(string(output)) {
+		key, value, _ := strings.Cut(line, " ")
+		if value == "true" {
+			return strings.TrimSuffix(strings.TrimPrefix(key, "remote."), ".promisor")
+		}
+	}
+	return ""
+}
+
+// The blobs the diff of a file in a section compares that a partial clone
+// lacks, which git would otherwise fetch while the diff loads, without a
+// word and for as long as the network takes
+func missingBlobs(r repo, f fileEntry, s section, review *reviewBase) []string {
+	if r.promisor == "" || f.untracked {
+		return nil
+	}
+	var entries []byte
+	switch {
+	case review != nil:
+		entries, _ = r.atRoot().git("ls-tree", review.commit, "--", f.pathFromGitRoot).Output()
+	case s == stagedSection, s == noSection && (f.status == staged || f.status == partiallyStaged):
+		entries, _ = r.atRoot().git("ls-tree", "HEAD", "--", f.pathFromGitRoot).Output()
+	}
+	index, _ := r.atRoot().git("ls-files", "--stage", "--", f.pathFromGitRoot).Output()
+	var missing []string
+	for _, line := range splitDiffLines(string(entries) + string(index)) {
+		// "<mode> blob <oid>\t<path>" from ls-tree, "<mode> <oid> <stage>\t<path>"
+		// from ls-files, submodules being commits of mode 160000
+		fields := strings.Fields(line)
+		if len(fields) < 3 || fields[0] == "160000" {
+			continue
+		}
+		oid := fields[1]
+		if fields[1] == "blob" {
+			oid = fields[2]
+		}
+		// Unlike most commands, rev-list --missing doesn't fetch what's missing
+		cmd := r.git("rev-list", "--objects", "--missing=print", "--no-walk", oid)
+		cmd.Env = append(cmd.Environ(), "GIT_NO_LAZY_FETCH=1")
+		if output, err := cmd.Output(); err != nil || strings.HasPrefix(string(output), "?") {
+			missing = append(missing, oid)
+		}
+	}
+	return missing
+}
+
+// Offer to fetch the blobs the selected diff lacks, see missingBlobs
+func (m *model) askToFetchBlobs(path string, oids []string) {
+	if m.confirm != nil {
+		return
+	}
+	m.ask(tr("The content of %s isn't available locally, fetch it from %s?", path, m.repo.promisor), func(m *model) {
+		m.queue(m.fetchBlobs(oids))
+	})
+}
+
+// The git fetch of blobs from the promisor remote that git runs itself when
+// it lacks them
+func fetchBlobsArgs(r repo) []string {
+	return []string{"-c", "fetch.negotiationAlgorithm=noop", "fetch", r.promisor, "--no-tags", "--no-write-fetch-head",
+		"--recurse-submodules=no", "--filter=blob:none", "--stdin"}
+}
+
+// Fetch the blobs in the background, see runInBackground
+func (m *model) fetchBlobs(oids []string) tea.Cmd {
+	m.status = tr("Fetching the content from %s…", m.repo.promisor)
+	r := m.repo
+	return func() tea.Msg {
+		cmd := r.git(fetchBlobsArgs(r)...)
+		cmd.Stdin = strings.NewReader(strings.Join(oids, "\n") + "\n")
+		return blobsFetchedMsg{r.root, oids, runInBackground(cmd)}
+	}
+}
+
+func (m *model) blobsFetched(msg blobsFetchedMsg) {
+	if needsCredentials(msg.err) {
+		m.ask(tr("Fetching needs credentials, enter them in the terminal?"), func(m *model) {
+			r := m.repo
+			cmd := r.interactiveGit(fetchBlobsArgs(r)...)
+			cmd.Stdin = strings.NewReader(strings.Join(msg.oids, "\n") + "\n")
+			m.queue(runInTerminal(cmd, func(err error) tea.Msg {
+				return blobsFetchedMsg{r.root, msg.oids, err}
+			}))
+		})
+		return
+	}
+	if msg.err != nil {
+		m.status = tr("Failed to fetch the content: %v", msg.err)
+		return
+	}
+	m.status = tr("Fetched the content from %s", m.repo.promisor)
+	m.loadDiff()
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// How the list shows paths, set by paths in the config and cycled with the
+// path display key. Git commands are given the paths from the current
+// directory whichever is shown.
+type pathDisplay string
+
+const (
+	pathsFromRoot pathDisplay = "root"
+	pathsFromCwd  pathDisplay = "cwd"
+	pathsAbsolute pathDisplay = "absolute"
+)
+
+var pathDisplays = []pathDisplay{pathsFromRoot, pathsFromCwd, pathsAbsolute}
+
+func checkPathDisplay(d pathDisplay) error {
+	if !slices.Contains(pathDisplays, d) {
+		return errors.New(tr("Unknown paths %q, available paths: %v", d, pathDisplays))
+	}
+	return nil
+}
+
+func (d pathDisplay) next() pathDisplay {
+	return pathDisplays[(slices.Index(pathDisplays, d)+1)%len(pathDisplays)]
+}
+
+func (d pathDisplay) description() string {
+	switch d {
+	case pathsFromCwd:
+		return tr("Paths from the current directory")
+	case pathsAbsolute:
+		return tr("Absolute paths")
+	default:
+		return tr("Paths from the repository root")
+	}
+}
+
+// The path from the root the file has now, the new one of a rename
+func (f fileEntry) currentPath() string {
+	if _, to, renamed := strings.Cut(f.pathFromGitRoot, " -> "); renamed {
+		return to
+	}
+	return f.pathFromGitRoot
+}
+
+// A path as git status lists it, from the root, shown the way d says. Both
+// sides of a rename like "old -> new" are.
+func (r repo) displayPath(pathFromGitRoot string, d pathDisplay) string {
+	if from, to, renamed := strings.Cut(pathFromGitRoot, " -> "); renamed {
+		return r.displayPath(from, d) + " -> " + r.displayPath(to, d)
+	}
+	switch d {
+	case pathsFromCwd:
+		return filepath.ToSlash(r.relPath(pathFromGitRoot))
+	case pathsAbsolute:
+		return filepath.ToSlash(filepath.Join(r.root, pathFromGitRoot))
+	default:
+		return pathFromGitRoot
+	}
+}
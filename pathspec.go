@@ -0,0 +1,142 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/hzqtc/git-istage/gitx"
+)
+
+// Files listed in the preview before staging by a pathspec, the rest are counted
+const pathspecShown = 12
+
+// Start typing a pathspec like *.go or src/**/test_* in the status line
+func (m *model) openPathspecPrompt() {
+	input := textinput.New()
+	input.Prompt = tr("Stage or unstage matching: ")
+	input.PromptStyle = promptStyle
+	input.Cursor.SetMode(cursor.CursorStatic)
+	input.Focus()
+	m.pathspec = &input
+}
+
+func (m *model) updatePathspec(msg tea.KeyMsg) {
+	switch key := keyName(msg); {
+	case key == "enter":
+		spec := strings.TrimSpace(m.pathspec.Value())
+		m.pathspec = nil
+		if spec != "" {
+			m.previewPathspec(spec)
+		}
+	case m.keys.cancel.matches(key):
+		m.pathspec = nil
+	default:
+		*m.pathspec, _ = m.pathspec.Update(msg)
+	}
+}
+
+func (m model) pathspecView() string {
+	m.pathspec.Width = max(m.width-ansi.StringWidth(m.pathspec.Prompt)-1, 0)
+	return m.pathspec.View()
+}
+
+// What git would stage for the pathspec, from the root, as git add
+// --dry-run lists it: "add 'path'" or "remove 'path'" for deletions
+func pathspecToStage(r repo, spec string) []string {
+	output, err := r.git("add", "--dry-run", "--", spec).Output()
+	if err != nil {
+		return nil
+	}
+	var paths []string
+	for line := range strings.SplitSeq(string(output), "\n") {
+		for _, prefix := range []string{"add '", "remove '"} {
+			if path, ok := strings.CutPrefix(line, prefix); ok {
+				paths = append(paths, strings.TrimSuffix(path, "'"))
+			}
+		}
+	}
+	return paths
+}
+
+// The staged files the pathspec matches, from the root
+func pathspecToUnstage(r repo, spec string) []string {
+	output, err := r.git("diff", "--cached", "--name-only", "--no-relative", "--", spec).Output()
+	if err != nil {
+		return nil
+	}
+	var paths []string
+	for _, line := range splitDiffLines(string(output)) {
+		paths = append(paths, gitx.UnquotePath(line))
+	}
+	return paths
+}
+
+// List what the pathspec would stage, or unstage when everything it matches
+// is staged already, and ask before doing it
+func (m *model) previewPathspec(spec string) {
+	stage := true
+	paths := pathspecToStage(m.repo, spec)
+	if len(paths) == 0 {
+		stage, paths = false, pathspecToUnstage(m.repo, spec)
+	}
+	if len(paths) == 0 {
+		m.status = tr("Nothing to stage or unstage matches %s", spec)
+		return
+	}
+	details := []string{tr("Files matching %s", spec), ""}
+	details = append(details, paths[:min(len(paths), pathspecShown)]...)
+	if len(paths) > pathspecShown {
+		details = append(details, tr("and %d more", len(paths)-pathspecShown))
+	}
+	message := tr("Stage %d file(s) matching %s?", len(paths), spec)
+	if !stage {
+		message = tr("Unstage %d file(s) matching %s?", len(paths), spec)
+	}
+	m.confirm = &confirmation{
+		message: message,
+		details: details,
+		onYes: func(m *model) {
+			if !stage {
+				m.queue(m.togglePathspec(spec, len(paths), false))
+				return
+			}
+			if secrets := findStagedSecrets(m.repo, m.pathspecEntries(paths)); len(secrets) > 0 {
+				m.askAboutSecrets(secrets, func(m *model) {
+					m.queue(m.togglePathspec(spec, len(paths), true))
+				})
+				return
+			}
+			m.queue(m.togglePathspec(spec, len(paths), true))
+		},
+	}
+}
+
+// Let git stage or unstage by the pathspec itself, so it matches the way
+// it does on the command line
+func (m *model) togglePathspec(spec string, files int, stage bool) tea.Cmd {
+	r := m.repo
+	title, run := tr("Unstaging"), r.backend.Unstage
+	if stage {
+		title, run = tr("Staging"), r.backend.Stage
+	}
+	return m.startJob(title, []jobStep{{files, func() error { return run(spec) }}}, nil)
+}
+
+// Entries for paths from the root that git would stage, untracked if in an
+// untracked file or directory of the list
+func (m model) pathspecEntries(paths []string) []fileEntry {
+	var entries []fileEntry
+	for _, path := range paths {
+		e := fileEntry{pathFromGitRoot: path, pathFromCwd: m.repo.relPath(path)}
+		for _, f := range m.files {
+			if f.untracked && (f.pathFromGitRoot == path || strings.HasSuffix(f.pathFromGitRoot, "/") && strings.HasPrefix(path, f.pathFromGitRoot)) {
+				e.untracked = true
+			}
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Picks one of a list of candidates for the commit message, filtered by
+// typing, like a co-author to credit
+type picker struct {
+	filter     textinput.Model
+	candidates []string
+	cursor     int
+	onPick     func(m *model, choice string)
+}
+
+func newPicker(prompt string, candidates []string, onPick func(m *model, choice string)) *picker {
+	filter := textinput.New()
+	filter.Prompt = prompt
+	filter.PromptStyle = promptStyle
+	filter.Cursor.SetMode(cursor.CursorStatic)
+	filter.Focus()
+	return &picker{filter: filter, candidates: candidates, onPick: onPick}
+}
+
+// Candidates containing the filter text, ignoring case
+func (p picker) matches() []string {
+	filter := strings.ToLower(p.filter.Value())
+	var matches []string
+	for _, c := range p.candidates {
+		if strings.Contains(strings.ToLower(c), filter) {
+			matches = append(matches, c)
+		}
+	}
+	return matches
+}
+
+func (m *model) updatePicker(msg tea.KeyMsg) {
+	p := m.picker
+	switch key := keyName(msg); {
+	case key == "up":
+		p.cursor = max(p.cursor-1, 0)
+	case key == "down":
+		p.cursor = min(p.cursor+1, max(len(p.matches())-1, 0))
+	case key == "enter":
+		m.picker = nil
+		if matches := p.matches(); len(matches) > 0 {
+			p.onPick(m, matches[p.cursor])
+		}
+	case m.keys.cancel.matches(key):
+		m.picker = nil
+	default:
+		p.filter, _ = p.filter.Update(msg)
+		p.cursor = min(p.cursor, max(len(p.matches())-1, 0))
+	}
+}
+
+func (m model) pickerView(width, height int) string {
+	p := m.picker
+	p.filter.Width = max(width-ansi.StringWidth(p.filter.Prompt)-1, 0)
+	rows := []string{p.filter.View()}
+	matches := p.matches()
+	offset := max(p.cursor-(height-1)+1, 0)
+	for i := offset; i < len(matches) && len(rows) < height; i++ {
+		row := cfg.Glyphs.cursor(i == p.cursor) + matches[i]
+		rows = append(rows, ansi.Truncate(row, width, "…"))
+	}
+	return strings.Join(rows, "\n")
+}
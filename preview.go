@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Bytes of a file looked at for a NUL, like git does to tell binary files
+const binaryCheckBytes = 8000
+
+func humanSize(size int64) string {
+	switch {
+	case size < 1<<10:
+		return fmt.Sprintf("%d B", size)
+	case size < 1<<20:
+		return fmt.Sprintf("%.1f KiB", float64(size)/(1<<10))
+	default:
+		return fmt.Sprintf("%.1f MiB", float64(size)/(1<<20))
+	}
+}
+
+// The content of an untracked file as the diff pane shows it in preview,
+// highlighted and numbered under a line with its size and length, up to
+// limit lines. Not ok for anything but a regular file. The file is read
+// only up to the limit, so the lines left out are estimated from its size.
+func filePreview(r repo, f fileEntry, limit int) ([]diffLine, int, bool) {
+	path := filepath.Join(r.root, f.pathFromGitRoot)
+	info, err := os.Lstat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return nil, 0, false
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, 0, false
+	}
+	defer file.Close()
+	reader := bufio.NewReaderSize(file, binaryCheckBytes)
+	if head, _ := reader.Peek(binaryCheckBytes); bytes.IndexByte(head, 0) >= 0 {
+		header := tr("%s · %s · binary", f.pathFromCwd, humanSize(info.Size()))
+		return []diffLine{{text: header, preview: diffMetaStyle.Render(header)}}, 0, true
+	}
+	var lines []string
+	read := 0
+	for limit < 0 || len(lines) < limit {
+		line, err := reader.ReadString('\n')
+		read += len(line)
+		if line != "" {
+			lines = append(lines, strings.TrimSuffix(line, "\n"))
+		}
+		if err != nil {
+			break
+		}
+	}
+	header := tr("%s · %s · %d line(s)", f.pathFromCwd, humanSize(info.Size()), len(lines))
+	more := 0
+	if rest := info.Size() - int64(read); rest > 0 && read > 0 {
+		// As long as the lines read on average
+		more = max(int(rest*int64(len(lines))/int64(read)), 1)
+		header = tr("%s · %s · about %d line(s)", f.pathFromCwd, humanSize(info.Size()), len(lines)+more)
+	}
+	result := []diffLine{{text: header, preview: diffMetaStyle.Render(header)}}
+	width := len(fmt.Sprint(len(lines)))
+	for i, line := range highlightLines(f.pathFromGitRoot, lines) {
+		number := separatorStyle.Render(fmt.Sprintf("%*d ", width, i+1))
+		result = append(result, diffLine{text: lines[i], preview: number + line})
+	}
+	return result, more, true
+}
+
+func (m *model) togglePreview() {
+	m.preview = !m.preview
+	if m.preview {
+		m.status = tr("Untracked files show their content")
+	} else {
+		m.status = tr("Untracked files show as a diff")
+	}
+	m.loadDiff()
+}
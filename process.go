@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hzqtc/git-istage/gitx"
+)
+
+// Git commands of a repository run under a shared context so every command
+// in flight there can be killed at once, leaving the other tabs' running.
+// Cancelling starts a new generation for the commands after.
+type gitRun struct {
+	ctx        context.Context
+	cancel     context.CancelFunc
+	generation int
+}
+
+// The runs by the root of their repository, and by the directories it was
+// opened from, which may not be under the root as git resolves it. Commands
+// from a directory outside all of them, like finding the root, share the one
+// of "".
+var gitRuns = struct {
+	sync.Mutex
+	byDir map[string]*gitRun
+}{byDir: make(map[string]*gitRun)}
+
+func init() {
+	gitx.Context = func(dir string) context.Context {
+		gitRuns.Lock()
+		defer gitRuns.Unlock()
+		return gitRunIn(dir).ctx
+	}
+}
+
+// The run of the innermost directory dir is in, with gitRuns locked
+func gitRunIn(dir string) *gitRun {
+	in := ""
+	for d := range gitRuns.byDir {
+		if len(d) > len(in) && (dir == d || strings.HasPrefix(dir, d+string(filepath.Separator))) {
+			in = d
+		}
+	}
+	return gitRunOf(in)
+}
+
+// The run of root, started on first use, with gitRuns locked
+func gitRunOf(root string) *gitRun {
+	run, ok := gitRuns.byDir[root]
+	if !ok {
+		run = &gitRun{}
+		run.ctx, run.cancel = context.WithCancel(context.Background())
+		gitRuns.byDir[root] = run
+	}
+	return run
+}
+
+// Run the commands from dir under the context of the repository at root
+func trackGitRuns(root, dir string) {
+	gitRuns.Lock()
+	defer gitRuns.Unlock()
+	gitRuns.byDir[dir] = gitRunOf(root)
+}
+
+// The context of the commands in the repository at root, and its generation
+func gitContext(root string) (context.Context, int) {
+	gitRuns.Lock()
+	defer gitRuns.Unlock()
+	run := gitRunOf(root)
+	return run.ctx, run.generation
+}
+
+// The latest git command killed after gitx.Timeout, for the status line to
+// tell, since what ran it may drop the error
+var gitTimeout = struct {
+	sync.Mutex
+	err error
+}{}
+
+// The command that timed out since the last call, if any
+func takeGitTimeout() error {
+	gitTimeout.Lock()
+	defer gitTimeout.Unlock()
+	err := gitTimeout.err
+	gitTimeout.err = nil
+	return err
+}
+
+func parseGitTimeout(timeout string) (time.Duration, error) {
+	d, err := time.ParseDuration(timeout)
+	if timeout == "0" {
+		d, err = 0, nil
+	}
+	if err != nil || d < 0 {
+		return 0, errors.New(tr("Invalid git_timeout %q, write it like \"30s\" or \"2m\", or \"0\" for none", timeout))
+	}
+	return d, nil
+}
+
+// Set how long git commands may run, see gitx.Timeout
+func setGitTimeout(timeout string) error {
+	d, err := parseGitTimeout(timeout)
+	if err != nil {
+		return err
+	}
+	gitx.Timeout = d
+	return nil
+}
+
+// Kill the running git commands of the repository at root
+func cancelGitCommands(root string) {
+	gitRuns.Lock()
+	defer gitRuns.Unlock()
+	run := gitRunOf(root)
+	run.cancel()
+	run.ctx, run.cancel = context.WithCancel(context.Background())
+	run.generation++
+}
+
+// Whether cancelGitCommands was called for root since the given generation
+// started
+func gitCancelledSince(root string, generation int) bool {
+	_, current := gitContext(root)
+	return current != generation
+}
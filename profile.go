@@ -0,0 +1,141 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Kinds of profile --profile writes
+var profileKinds = []string{"cpu", "mem", "trace"}
+
+// A profile being recorded until the program exits
+type profile struct {
+	kind string
+	file *os.File
+}
+
+// Time spent in the git commands of one subcommand, like status or diff
+type gitTime struct {
+	subcommand string
+	count      int
+	total      time.Duration
+	longest    time.Duration
+}
+
+// Collected only while profiling
+var gitTimes = struct {
+	sync.Mutex
+	bySubcommand map[string]*gitTime
+}{}
+
+// Start recording a profile into a new file in the temporary directory, so
+// it doesn't show up as untracked in the repository
+func startProfile(kind string) (*profile, error) {
+	if !slices.Contains(profileKinds, kind) {
+		return nil, errors.New(tr("Unknown profile %q, available profiles: %v", kind, profileKinds))
+	}
+	pattern := "git-istage-*." + kind + ".pprof"
+	if kind == "trace" {
+		pattern = "git-istage-*.trace"
+	}
+	file, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return nil, err
+	}
+	switch kind {
+	case "cpu":
+		err = pprof.StartCPUProfile(file)
+	case "trace":
+		err = trace.Start(file)
+	}
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	gitTimes.bySubcommand = make(map[string]*gitTime)
+	return &profile{kind, file}, nil
+}
+
+// Finish the profile, a memory profile being taken now
+func (p *profile) stop() error {
+	var err error
+	switch p.kind {
+	case "cpu":
+		pprof.StopCPUProfile()
+	case "mem":
+		// Up to date statistics of what's still allocated
+		runtime.GC()
+		err = pprof.WriteHeapProfile(p.file)
+	case "trace":
+		trace.Stop()
+	}
+	if closeErr := p.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// Add a finished command to the time spent by its subcommand. In a trace it
+// also shows up as a log event of the goroutine that ran it.
+func recordGitTime(c loggedCommand) {
+	if trace.IsEnabled() {
+		trace.Log(context.Background(), "git", fmt.Sprintf("%s %s (%s)", strings.Join(c.args, " "), c.result, c.duration))
+	}
+	gitTimes.Lock()
+	defer gitTimes.Unlock()
+	if gitTimes.bySubcommand == nil {
+		return
+	}
+	name := gitSubcommand(c.args)
+	t := gitTimes.bySubcommand[name]
+	if t == nil {
+		t = &gitTime{subcommand: name}
+		gitTimes.bySubcommand[name] = t
+	}
+	t.count++
+	t.total += c.duration
+	t.longest = max(t.longest, c.duration)
+}
+
+// The first argument after the options of git itself
+func gitSubcommand(args []string) string {
+	for i := 1; i < len(args); i++ {
+		switch {
+		case args[i] == "-c" || args[i] == "-C":
+			i++
+		case !strings.HasPrefix(args[i], "-"):
+			return args[i]
+		}
+	}
+	return filepath.Base(args[0])
+}
+
+// Time spent by each git subcommand, the most first
+func gitTimeSummary() []string {
+	gitTimes.Lock()
+	defer gitTimes.Unlock()
+	var times []*gitTime
+	for _, t := range gitTimes.bySubcommand {
+		times = append(times, t)
+	}
+	slices.SortFunc(times, func(a, b *gitTime) int {
+		return cmp.Compare(b.total, a.total)
+	})
+	var lines []string
+	for _, t := range times {
+		lines = append(lines, tr("%s: %d run(s), %s in total, %s at most", t.subcommand, t.count,
+			t.total.Round(time.Millisecond), t.longest.Round(time.Millisecond)))
+	}
+	return lines
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type pushFinishedMsg struct {
+	root     string
+	upstream upstreamInfo
+	err      error
+}
+
+// Offer to push the branch just committed to and run the pull_request
+// command of the config, like gh pr create
+func (m *model) offerPullRequest() {
+	if cfg.Commit.PullRequest == "" || m.branch == "" || m.branch[0] == '(' {
+		return
+	}
+	m.ask(tr("Push %s and run %s?", m.branch, cfg.Commit.PullRequest), func(m *model) {
+		m.queue(m.push())
+	})
+}
+
+// The arguments of git push for the branch, setting origin as its upstream
+// if it has none
+func (m model) pushArgs() []string {
+	if m.upstream.name == "" {
+		return []string{"push", "--quiet", "--set-upstream", "origin", "HEAD"}
+	}
+	return []string{"push", "--quiet"}
+}
+
+// git push in the background, see runInBackground
+func (m *model) push() tea.Cmd {
+	m.status = tr("Pushing…")
+	r, args := m.repo, m.pushArgs()
+	return func() tea.Msg {
+		err := runInBackground(r.git(args...))
+		return pushFinishedMsg{r.root, getUpstream(r), err}
+	}
+}
+
+func (m *model) pushFinished(msg pushFinishedMsg) {
+	m.upstream = msg.upstream
+	switch {
+	case needsCredentials(msg.err):
+		m.ask(tr("Pushing needs credentials, enter them in the terminal?"), func(m *model) {
+			r := m.repo
+			m.queue(runInTerminal(r.interactiveGit(m.pushArgs()...), func(err error) tea.Msg {
+				return pushFinishedMsg{r.root, getUpstream(r), err}
+			}))
+		})
+	case msg.err != nil:
+		m.status = tr("Failed to push: %v", msg.err)
+	default:
+		m.status = tr("Pushed %s", m.branch)
+		m.queue(m.runPullRequestCommand())
+	}
+}
+
+// Suspend the UI and run the pull_request command, which may ask for the
+// title and description of the pull request, with the branch in $BRANCH
+// and the repository's page on the forge in $URL
+func (m *model) runPullRequestCommand() tea.Cmd {
+	cmd := exec.Command("sh", "-c", cfg.Commit.PullRequest)
+	cmd.Dir = m.repo.root
+	cmd.Env = append(os.Environ(), "BRANCH="+m.branch, "URL="+m.repo.forgeURL(""))
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return launcherFinishedMsg{cfg.Commit.PullRequest, err}
+	})
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/hzqtc/git-istage/gitx"
+)
+
+// The commits the branch has over its upstream, to check a rewrite of them
+// before pushing, or without an upstream the ones a rebase just rewrote
+func (m model) defaultRangeDiff() string {
+	if m.upstream.name == "" {
+		return "ORIG_HEAD...HEAD"
+	}
+	return "@{upstream}...HEAD"
+}
+
+// The output of git range-diff shown in rangeDiffMode
+type rangeDiff struct {
+	spec   string
+	lines  []string
+	scroll int
+}
+
+// Start typing the ranges to compare, like "@{upstream}...HEAD", "A...B" or
+// "base old-tip new-tip", as git range-diff takes them
+func (m *model) openRangeDiffPrompt() {
+	input := textinput.New()
+	input.Prompt = tr("Range-diff of: ")
+	input.PromptStyle = promptStyle
+	input.Cursor.SetMode(cursor.CursorStatic)
+	input.SetValue(m.defaultRangeDiff())
+	input.Focus()
+	m.rangeInput = &input
+}
+
+func (m *model) updateRangeInput(msg tea.KeyMsg) {
+	switch key := keyName(msg); {
+	case key == "enter":
+		spec := strings.TrimSpace(m.rangeInput.Value())
+		m.rangeInput = nil
+		if spec != "" {
+			m.openRangeDiff(spec)
+		}
+	case m.keys.cancel.matches(key):
+		m.rangeInput = nil
+	default:
+		*m.rangeInput, _ = m.rangeInput.Update(msg)
+	}
+}
+
+func (m model) rangeInputView() string {
+	m.rangeInput.Width = max(m.width-ansi.StringWidth(m.rangeInput.Prompt)-1, 0)
+	return m.rangeInput.View()
+}
+
+func getRangeDiff(r repo, spec string) ([]string, error) {
+	args := append([]string{"range-diff", "--no-color"}, strings.Fields(spec)...)
+	output, err := r.git(args...).CombinedOutput()
+	if err != nil {
+		if msg := firstLine(string(output)); msg != "" && !gitx.IsTimeout(err) {
+			return nil, errors.New(strings.TrimPrefix(msg, "fatal: "))
+		}
+		return nil, err
+	}
+	return splitDiffLines(string(output)), nil
+}
+
+func (m *model) openRangeDiff(spec string) {
+	lines, err := getRangeDiff(m.repo, spec)
+	switch {
+	case err != nil:
+		m.status = tr("Failed to range-diff %s: %v", spec, err)
+		return
+	case len(lines) == 0:
+		m.status = tr("No commits to compare in %s", spec)
+		return
+	}
+	m.rangeDiff = &rangeDiff{spec: spec, lines: lines}
+	m.mode = rangeDiffMode
+}
+
+func (m *model) updateRangeDiff(key string) {
+	rd := m.rangeDiff
+	last := max(len(rd.lines)-m.bodyHeight()+1, 0)
+	switch {
+	case m.keys.scrollUp.matches(key):
+		rd.scroll = max(rd.scroll-1, 0)
+	case m.keys.scrollDown.matches(key):
+		rd.scroll = min(rd.scroll+1, last)
+	case m.keys.pageUp.matches(key):
+		rd.scroll = max(rd.scroll-m.bodyHeight(), 0)
+	case m.keys.pageDown.matches(key):
+		rd.scroll = min(rd.scroll+m.bodyHeight(), last)
+	case m.keys.top.matches(key):
+		rd.scroll = 0
+	case m.keys.bottom.matches(key):
+		rd.scroll = last
+	case m.keys.focusList.matches(key), m.keys.rangeDiff.matches(key):
+		m.rangeDiff = nil
+		m.mode = listMode
+		m.loadDiff()
+	}
+}
+
+// Pairs of commits are headed by lines like "1:  abc1234 ! 1:  def5678 subject",
+// = for the same patch, ! for a changed one, < and > for one only in the old
+// or the new range. What changed is a diff of the two diffs below, indented.
+func renderRangeDiffLine(line string) string {
+	content, nested := strings.CutPrefix(line, "    ")
+	if fields := strings.Fields(line); !nested && len(fields) > 2 {
+		switch fields[2] {
+		case "=":
+			return separatorStyle.Render(line)
+		case "!":
+			return promptStyle.Render(line)
+		case "<":
+			return diffRemovedStyle.Render(line)
+		case ">":
+			return diffAddedStyle.Render(line)
+		}
+		return line
+	}
+	inner := strings.TrimLeft(content, " +-")
+	switch {
+	case strings.HasPrefix(content, "+"):
+		return diffAddedStyle.Render(line)
+	case strings.HasPrefix(content, "-"):
+		return diffRemovedStyle.Render(line)
+	case strings.HasPrefix(inner, "@@"):
+		return diffHunkStyle.Render(line)
+	case strings.HasPrefix(inner, "## "):
+		return diffMetaStyle.Render(line)
+	}
+	return line
+}
+
+func (m model) rangeDiffView(width, height int) string {
+	rd := m.rangeDiff
+	rows := []string{sectionStyle.Render(ansi.Truncate(tr("Range-diff of %s", rd.spec), width, "…"))}
+	for i := rd.scroll; i < len(rd.lines) && len(rows) < height; i++ {
+		rows = append(rows, ansi.Truncate(renderRangeDiffLine(rd.lines[i]), width, "…"))
+	}
+	return strings.Join(rows, "\n")
+}
@@ -0,0 +1,255 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/hzqtc/git-istage/gitx"
+)
+
+// Reflog entries of HEAD listed for recovery
+const recoveryReflogEntries = 50
+
+// A snapshot files can be restored from: files this tool discarded or
+// deleted, a safety stash of it or a commit HEAD pointed at
+type recoveryEntry struct {
+	commit  string
+	ref     string // like stash@{0} or HEAD@{3}
+	age     string
+	subject string
+	stash   bool
+	trash   string // directory in the trash
+}
+
+// A file whose content in a snapshot differs from the work tree
+type recoveryFile struct {
+	path      string // from the git root
+	source    string // commit or tree to restore it from, or the copy in the trash
+	fromTrash bool
+}
+
+// State of recoveryMode: the snapshots, then the files of the one opened
+type recovery struct {
+	entries    []recoveryEntry
+	cursor     int
+	files      []recoveryFile // of the opened entry, nil in the list of snapshots
+	fileCursor int
+}
+
+// The trash and safety stashes first, newest first, then the reflog of HEAD
+func getRecoveryEntries(r repo) []recoveryEntry {
+	entries := getTrashEntries(r)
+	parse := func(output []byte, stash bool) {
+		for _, line := range splitDiffLines(string(output)) {
+			fields := strings.SplitN(line, "\x00", 4)
+			if len(fields) < 4 || (stash && !strings.Contains(fields[3], ": "+safetyStashPrefix)) {
+				continue
+			}
+			entries = append(entries, recoveryEntry{commit: fields[0], ref: fields[1], age: fields[2], subject: fields[3], stash: stash})
+		}
+	}
+	const format = "--format=%H%x00%gd%x00%cr%x00%gs"
+	output, _ := r.git("stash", "list", format).Output()
+	parse(output, true)
+	output, _ = r.git("reflog", "-n", strconv.Itoa(recoveryReflogEntries), format).Output()
+	parse(output, false)
+	return entries
+}
+
+// Files the snapshot has other content for than the work tree. For a stash
+// that's the work tree it saved, and the untracked files it saved with it.
+func getRecoveryFiles(r repo, e recoveryEntry) []recoveryFile {
+	if e.trash != "" {
+		return getTrashFiles(r, e.trash)
+	}
+	var files []recoveryFile
+	output, _ := r.git("diff", "--name-only", "--no-relative", e.commit).Output()
+	for _, path := range splitDiffLines(string(output)) {
+		files = append(files, recoveryFile{path: gitx.UnquotePath(path), source: e.commit})
+	}
+	if !e.stash {
+		return files
+	}
+	untracked := e.commit + "^3"
+	if r.git("rev-parse", "-q", "--verify", untracked).Run() != nil {
+		return files
+	}
+	// Lines look like "<mode> blob <object>\t<path>"
+	output, _ = r.git("ls-tree", "-r", untracked).Output()
+	for _, line := range splitDiffLines(string(output)) {
+		info, path, _ := strings.Cut(line, "\t")
+		path = gitx.UnquotePath(path)
+		fields := strings.Fields(info)
+		if len(fields) < 3 || sameContent(r, path, fields[2]) {
+			continue
+		}
+		files = append(files, recoveryFile{path: path, source: untracked})
+	}
+	return files
+}
+
+// Whether the work tree file has the content of a blob
+func sameContent(r repo, pathFromGitRoot, object string) bool {
+	if _, err := os.Lstat(filepath.Join(r.root, pathFromGitRoot)); err != nil {
+		return false
+	}
+	output, err := r.atRoot().git("hash-object", "--", pathFromGitRoot).Output()
+	return err == nil && strings.TrimSpace(string(output)) == object
+}
+
+func (m *model) openRecovery() {
+	entries := getRecoveryEntries(m.repo)
+	if len(entries) == 0 {
+		m.status = tr("No safety stashes or reflog entries to recover from")
+		return
+	}
+	m.recovery = &recovery{entries: entries}
+	m.mode = recoveryMode
+	m.diffLines = nil
+	m.diffID++
+}
+
+func (m *model) openRecoveryEntry() {
+	rc := m.recovery
+	e := rc.entries[rc.cursor]
+	files := getRecoveryFiles(m.repo, e)
+	if len(files) == 0 {
+		m.status = tr("The work tree has the same content as %s", e.ref)
+		return
+	}
+	rc.files, rc.fileCursor = files, 0
+	m.loadRecoveryDiff()
+}
+
+// Load what restoring the file under the cursor would change into the diff pane
+func (m *model) loadRecoveryDiff() {
+	m.pane.YOffset = 0
+	m.diffLines = nil
+	m.diffMore = 0
+	m.lineSelect = nil
+	m.hunk = -1
+	m.diffID++
+	m.diffLoading = true
+	r, file, id := m.repo, m.recovery.files[m.recovery.fileCursor], m.diffID
+	limit := m.diffLimit(file.source + ":" + file.path)
+	m.queue(func() tea.Msg {
+		_, generation := gitContext(r.root)
+		args := []string{"diff", "--no-color", "-R", file.source, "--", file.path}
+		if file.fromTrash {
+			worktree := file.path
+			if _, err := os.Lstat(filepath.Join(r.root, file.path)); err != nil {
+				worktree = "/dev/null"
+			}
+			// Exits with 1 when there are differences, so only the output matters
+			trashed, _ := filepath.Rel(r.root, file.source)
+			args = []string{"diff", "--no-color", "--no-index", "--", worktree, trashed}
+		}
+		output, more, _ := r.atRoot().git(args...).OutputLines(limit)
+		f := fileEntry{pathFromGitRoot: file.path, pathFromCwd: r.relPath(file.path)}
+		lines := parseDiff(presentDiff(r, f, output))
+		return diffLoadedMsg{r.root, id, lines, more, gitCancelledSince(r.root, generation), nil, nil}
+	})
+}
+
+// Put the content of the file under the cursor back into the work tree,
+// leaving the index as it is
+func (m *model) restoreRecoveryFile() {
+	rc := m.recovery
+	e, file := rc.entries[rc.cursor], rc.files[rc.fileCursor]
+	m.ask(tr("Overwrite %s in the work tree with its content from %s?", file.path, e.ref), func(m *model) {
+		var err error
+		if file.fromTrash {
+			err = copyPath(file.source, filepath.Join(m.repo.root, file.path))
+		} else {
+			err = m.repo.atRoot().run("restore", "--source="+file.source, "--worktree", "--", file.path)
+		}
+		if m.handleIndexLock(err) {
+			return
+		} else if err != nil {
+			m.status = tr("Failed to restore %s: %v", file.path, err)
+			return
+		}
+		m.reload()
+		m.status = tr("Restored %s from %s", file.path, e.ref)
+		rc.files = getRecoveryFiles(m.repo, e)
+		if len(rc.files) == 0 {
+			rc.files = nil
+			m.diffLines = nil
+			return
+		}
+		rc.fileCursor = min(rc.fileCursor, len(rc.files)-1)
+		m.loadRecoveryDiff()
+	})
+}
+
+func (m *model) updateRecovery(key string) {
+	rc := m.recovery
+	if rc.files == nil {
+		switch {
+		case m.keys.up.matches(key):
+			rc.cursor = max(rc.cursor-1, 0)
+		case m.keys.down.matches(key):
+			rc.cursor = min(rc.cursor+1, len(rc.entries)-1)
+		case m.keys.restore.matches(key):
+			m.openRecoveryEntry()
+		case m.keys.focusList.matches(key), m.keys.recover.matches(key):
+			m.recovery = nil
+			m.mode = listMode
+			m.loadDiff()
+		}
+		return
+	}
+	switch {
+	case m.keys.up.matches(key) && rc.fileCursor > 0:
+		rc.fileCursor--
+		m.loadRecoveryDiff()
+	case m.keys.down.matches(key) && rc.fileCursor < len(rc.files)-1:
+		rc.fileCursor++
+		m.loadRecoveryDiff()
+	case m.keys.pageUp.matches(key):
+		m.scrollDiff(-m.bodyHeight())
+	case m.keys.pageDown.matches(key):
+		m.scrollDiff(m.bodyHeight())
+	case m.keys.loadFullDiff.matches(key):
+		m.loadFullDiff()
+	case m.keys.restore.matches(key):
+		m.restoreRecoveryFile()
+	case m.keys.focusList.matches(key):
+		rc.files = nil
+		m.diffLines = nil
+		m.diffID++
+	}
+}
+
+func (m model) recoveryListView(width, height int) string {
+	rc := m.recovery
+	var rows []string
+	if rc.files != nil {
+		e := rc.entries[rc.cursor]
+		rows = append(rows, sectionStyle.Render(ansi.Truncate(tr("Files to restore from %s", e.ref), width, "…")))
+		offset := max(rc.fileCursor+1-(height-1), 0)
+		for i := offset; i < len(rc.files) && len(rows) < height; i++ {
+			rows = append(rows, ansi.Truncate(cfg.Glyphs.cursor(i == rc.fileCursor)+rc.files[i].path, width, "…"))
+		}
+		return strings.Join(rows, "\n")
+	}
+	rows = append(rows, sectionStyle.Render(ansi.Truncate(tr("Recover from the trash, a safety stash or the reflog"), width, "…")))
+	offset := max(rc.cursor+1-(height-1), 0)
+	for i := offset; i < len(rc.entries) && len(rows) < height; i++ {
+		e := rc.entries[i]
+		row := cfg.Glyphs.cursor(i == rc.cursor) + cursorStyle.Render(e.ref) + " " + badgeStyle.Render(e.age) + " " + e.subject
+		rows = append(rows, ansi.Truncate(row, width, "…"))
+	}
+	return strings.Join(rows, "\n")
+}
+
+func (m model) recoveryView(width, height int) string {
+	if rc := m.recovery; rc.files == nil || width < minSplitWidth {
+		return m.recoveryListView(width, height)
+	}
+	return m.sideBySide(width, height, width/2, m.recoveryListView)
+}
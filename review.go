@@ -0,0 +1,84 @@
+package main
+
+import (
+	"errors"
+	"strings"
+)
+
+// Whether tabs open reviewing their branch, set by --review
+var reviewAtStart bool
+
+// The commit the branch forked from its upstream at, and the upstream's name
+type reviewBase struct {
+	commit   string
+	upstream string
+}
+
+func getReviewBase(r repo) (reviewBase, error) {
+	output, err := r.git("rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{upstream}").Output()
+	if err != nil {
+		return reviewBase{}, errors.New(tr("No upstream branch to review against, set one with `git branch -u`"))
+	}
+	upstream := strings.TrimSpace(string(output))
+	output, err = r.git("merge-base", "HEAD", "@{upstream}").Output()
+	if err != nil {
+		return reviewBase{}, errors.New(tr("No common ancestor of HEAD and %s", upstream))
+	}
+	return reviewBase{strings.TrimSpace(string(output)), upstream}, nil
+}
+
+// Files the branch's commits change that have no changes of their own in
+// the index or work tree
+func getBranchFiles(r repo, base reviewBase, files []fileEntry) []fileEntry {
+	listed := make(map[string]bool)
+	for _, f := range files {
+		listed[f.pathFromGitRoot] = true
+	}
+	var branchFiles []fileEntry
+	for path, stat := range getNumstat(r, base.commit, "HEAD") {
+		if listed[path] {
+			continue
+		}
+		branchFiles = append(branchFiles, fileEntry{
+			pathFromGitRoot: path,
+			pathFromCwd:     r.relPath(path),
+			status:          committed,
+			diff:            stat,
+			symlink:         r.isSymlink(path),
+		})
+	}
+	return branchFiles
+}
+
+// Everything the branch changes in a file: from the base to the work tree
+func getReviewDiff(r repo, f fileEntry, base reviewBase, limit int) ([]string, int) {
+	var output []byte
+	var more int
+	if f.untracked {
+		output, more = r.limitedDiff(limit, "--no-index", "--", "/dev/null", f.pathFromCwd)
+	} else {
+		output, more = r.limitedDiff(limit, base.commit, "--", f.pathFromCwd)
+	}
+	return presentDiff(r, f, output), more
+}
+
+// Show the changes since the merge base with the upstream, or go back to
+// the changes in the index and work tree
+func (m *model) toggleReview() {
+	if m.review != nil {
+		m.review = nil
+		m.reload()
+		return
+	}
+	base, err := getReviewBase(m.repo)
+	if err != nil {
+		m.status = err.Error()
+		return
+	}
+	m.review = &base
+	m.reload()
+}
+
+func (m model) reviewBadge() string {
+	return tr("reviewing against %s (%s)", m.review.upstream, shortHash(m.review.commit))
+}
@@ -0,0 +1,308 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Start typing the text to search the focused diff for, or all the changes
+// from the list
+func (m *model) openSearch() {
+	input := textinput.New()
+	input.Prompt = "/"
+	if m.mode == listMode {
+		input.Prompt = tr("Search the changes for: ")
+	}
+	input.PromptStyle = promptStyle
+	input.Cursor.SetMode(cursor.CursorStatic)
+	input.SetValue(m.searchTerm)
+	input.CursorEnd()
+	input.Focus()
+	m.searchInput = &input
+}
+
+func (m *model) updateSearchInput(msg tea.KeyMsg) {
+	switch key := keyName(msg); {
+	case key == "enter":
+		m.searchTerm = m.searchInput.Value()
+		m.searchInput = nil
+		m.match = -1
+		switch {
+		case m.searchTerm == "":
+			m.status = tr("Search cleared")
+		case m.mode == listMode:
+			m.openSearchResults(m.searchTerm)
+		default:
+			m.jumpToMatch(1)
+		}
+	case m.keys.cancel.matches(key):
+		m.searchInput = nil
+	default:
+		*m.searchInput, _ = m.searchInput.Update(msg)
+	}
+}
+
+func (m model) searchInputView() string {
+	m.searchInput.Width = max(m.width-ansi.StringWidth(m.searchInput.Prompt)-1, 0)
+	return m.searchInput.View()
+}
+
+// Where term is found in text, as ranges of runes. The search ignores case
+// unless term has an uppercase letter, like smartcase in vim.
+func searchRanges(text, term string) [][2]int {
+	if term == "" {
+		return nil
+	}
+	fold := func(r rune) rune { return r }
+	if !strings.ContainsFunc(term, unicode.IsUpper) {
+		fold = unicode.ToLower
+	}
+	haystack, needle := []rune(strings.Map(fold, text)), []rune(strings.Map(fold, term))
+	var ranges [][2]int
+	for i := 0; i+len(needle) <= len(haystack); {
+		if slices.Equal(haystack[i:i+len(needle)], needle) {
+			ranges = append(ranges, [2]int{i, i + len(needle)})
+			i += len(needle)
+		} else {
+			i++
+		}
+	}
+	return ranges
+}
+
+// A rendered line with what the search finds in it highlighted
+func highlightSearch(line, term string) string {
+	plain := []rune(ansi.Strip(line))
+	var ranges []lipgloss.Range
+	for _, r := range searchRanges(string(plain), term) {
+		start := ansi.StringWidth(string(plain[:r[0]]))
+		end := start + ansi.StringWidth(string(plain[r[0]:r[1]]))
+		ranges = append(ranges, lipgloss.NewRange(start, end, searchMatchStyle))
+	}
+	return lipgloss.StyleRanges(line, ranges...)
+}
+
+// Scroll to the next or previous line of the diff that has the search term,
+// going round at the end
+func (m *model) jumpToMatch(direction int) {
+	if m.searchTerm == "" {
+		m.status = tr("Search the diff with %s first", m.keys.search.Help().Key)
+		return
+	}
+	var matches []int
+	for i, l := range m.diffLines {
+		if len(searchRanges(l.text, m.searchTerm)) > 0 {
+			matches = append(matches, i)
+		}
+	}
+	if len(matches) == 0 {
+		m.status = tr("No match for %q", m.searchTerm)
+		return
+	}
+	from := m.match
+	if from < 0 {
+		// From the top of the pane
+		from = m.pane.YOffset - max(direction, 0)
+	}
+	next := len(matches) - 1
+	if direction > 0 {
+		next = 0
+	}
+	for i, line := range matches {
+		if direction > 0 && line > from {
+			next = i
+			break
+		}
+		if direction < 0 && line < from {
+			next = i
+		}
+	}
+	m.match = matches[next]
+	m.scrollDiff(0)
+	m.pane.SetYOffset(m.match - min(max(cfg.Diff.ScrollOff, 0), m.bodyHeight()/2))
+	m.status = tr("Match %d of %d for %q", next+1, len(matches), m.searchTerm)
+}
+
+// A hunk of the pending changes that a search of all of them found, the
+// index-th hunk of its side of the diff of the file
+type searchHit struct {
+	section section
+	path    string
+	cached  bool
+	index   int
+	line    diffLine // the hunk header or the first line of the hunk with the term
+}
+
+// State of searchMode, the hunks the search found to jump to
+type searchResults struct {
+	term   string
+	hits   []searchHit
+	cut    int // diffs searched only up to diffLineLimit
+	cursor int
+}
+
+type searchLoadedMsg struct {
+	root      string
+	id        int
+	term      string
+	hits      []searchHit
+	cut       int
+	cancelled bool
+}
+
+// The hunks of the conflicted, staged, unstaged and untracked changes that
+// add or remove a line with term, or whose function in the header has it,
+// in the order of the list, and how many diffs were longer than
+// diffLineLimit and searched only that far
+func getSearchHits(r repo, files []fileEntry, term string) ([]searchHit, int) {
+	var hits []searchHit
+	cut := 0
+	_, generation := gitContext(r.root)
+	for _, s := range []section{conflictsSection, stagedSection, unstagedSection, untrackedSection} {
+		for _, f := range files {
+			if !slices.Contains(f.sections(), s) {
+				continue
+			}
+			if gitCancelledSince(r.root, generation) {
+				return hits, cut
+			}
+			lines, more := getFileDiff(r, f, s, diffLineLimit)
+			if more > 0 {
+				cut++
+			}
+			index, found := -1, false
+			for _, l := range parseDiff(lines) {
+				hit := searchHit{s, f.pathFromGitRoot, s == stagedSection, index, l}
+				if isHunkHeader(l) {
+					index++
+					hit.index = index
+					_, function, _ := strings.Cut(strings.TrimPrefix(l.text, "@@"), "@@")
+					if found = len(searchRanges(function, term)) > 0; found {
+						hits = append(hits, hit)
+					}
+					continue
+				}
+				// The +/- columns of the line, more than one in a combined diff
+				prefix := l.text[:min(l.columns, len(l.text))]
+				if !found && index >= 0 && strings.ContainsAny(prefix, "+-") && len(searchRanges(l.text[len(prefix):], term)) > 0 {
+					hits = append(hits, hit)
+					found = true
+				}
+			}
+		}
+	}
+	return hits, cut
+}
+
+// Search every pending change for the term in the background, listing the
+// hunks that have it once done
+func (m *model) openSearchResults(term string) {
+	var files []fileEntry
+	for _, f := range m.files {
+		if m.listed(f) {
+			files = append(files, f)
+		}
+	}
+	m.searchID++
+	m.searching = true
+	m.status = tr("Searching the changes for %q…", term)
+	r, id := m.repo, m.searchID
+	m.queue(func() tea.Msg {
+		_, generation := gitContext(r.root)
+		hits, cut := getSearchHits(r, files, term)
+		return searchLoadedMsg{r.root, id, term, hits, cut, gitCancelledSince(r.root, generation)}
+	})
+}
+
+func (m *model) searchLoaded(msg searchLoadedMsg) {
+	if msg.id != m.searchID || !m.searching {
+		return
+	}
+	m.searching = false
+	switch {
+	case msg.cancelled:
+		m.status = tr("Cancelled the search")
+	case m.mode != listMode:
+		// Left the list meanwhile
+		m.status = ""
+	case len(msg.hits) == 0:
+		m.status = tr("No change matches %q", msg.term) + searchCutNote(msg.cut)
+	default:
+		m.status = ""
+		m.searchResults = &searchResults{term: msg.term, hits: msg.hits, cut: msg.cut}
+		m.mode = searchMode
+	}
+}
+
+// Note that diffs longer than diffLineLimit were searched only that far
+func searchCutNote(cut int) string {
+	if cut == 0 {
+		return ""
+	}
+	return tr(", %d diff(s) searched only in their first %d lines", cut, diffLineLimit)
+}
+
+func (m *model) updateSearchResults(key string) {
+	sr := m.searchResults
+	switch {
+	case m.keys.up.matches(key):
+		sr.cursor = max(sr.cursor-1, 0)
+	case m.keys.down.matches(key):
+		sr.cursor = min(sr.cursor+1, len(sr.hits)-1)
+	case m.keys.goToHunk.matches(key):
+		m.goToSearchHit(sr.hits[sr.cursor])
+	case m.keys.focusList.matches(key), m.keys.searchAll.matches(key):
+		m.searchResults = nil
+		m.mode = listMode
+	}
+}
+
+// Focus the diff of the file of a hit, scrolled to its hunk with the term
+// highlighted
+func (m *model) goToSearchHit(hit searchHit) {
+	m.searchResults = nil
+	m.mode = diffMode
+	s := hit.section
+	if !cfg.GroupByStatus {
+		s = noSection
+	}
+	if i := m.findRow(s, hit.path); i >= 0 {
+		m.cursor = i
+	}
+	if hit.section == stagedSection || hit.section == unstagedSection {
+		m.dualSide = hit.section
+	}
+	m.loadDiff()
+	m.advance = &hunkAdvance{hit.path, hit.cached, hit.index}
+}
+
+func (m model) searchResultsView(width, height int) string {
+	sr := m.searchResults
+	var lines []string
+	cursorLine := 0
+	for i, hit := range sr.hits {
+		if i == 0 || hit.section != sr.hits[i-1].section {
+			lines = append(lines, sectionStyle.Render(ansi.Truncate(hit.section.title(), width, "…")))
+		}
+		if i == sr.cursor {
+			cursorLine = len(lines)
+		}
+		row := cfg.Glyphs.cursor(i == sr.cursor) + cursorStyle.Render(hit.path) + " " +
+			highlightSearch(renderDiffLine(hit.line, width), sr.term)
+		lines = append(lines, ansi.Truncate(row, width, "…"))
+	}
+	title := tr("%d hunk(s) matching %q", len(sr.hits), sr.term) + searchCutNote(sr.cut)
+	rows := []string{sectionStyle.Render(ansi.Truncate(title, width, "…"))}
+	offset := max(cursorLine+1-(height-1), 0)
+	for i := offset; i < len(lines) && len(rows) < height; i++ {
+		rows = append(rows, lines[i])
+	}
+	return strings.Join(rows, "\n")
+}
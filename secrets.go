@@ -0,0 +1,181 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+type secretsConfig struct {
+	// Regular expressions matched against the lines added by the files being
+	// staged, asking first when one matches. Empty to not look.
+	Patterns []string `toml:"patterns"`
+}
+
+// AWS access keys, private keys, and GitHub, GitLab, Slack and Stripe tokens
+func defaultSecretPatterns() []string {
+	return []string{
+		`AKIA[0-9A-Z]{16}`,
+		`-----BEGIN ([A-Z0-9]+ )*PRIVATE KEY-----`,
+		`gh[pousr]_[A-Za-z0-9]{36,}`,
+		`github_pat_[A-Za-z0-9_]{22,}`,
+		`glpat-[A-Za-z0-9_-]{20,}`,
+		`xox[abposr]-[A-Za-z0-9-]{10,}`,
+		`sk_live_[A-Za-z0-9]{24,}`,
+	}
+}
+
+// Untracked files larger than this aren't looked into, they're rarely typed
+const secretScanMaxFile = 1 << 20
+
+// Matches listed in the confirmation, the rest are counted
+const secretsShown = 8
+
+func checkSecretsConfig(c secretsConfig) error {
+	for _, p := range c.Patterns {
+		if _, err := regexp.Compile(p); err != nil {
+			return errors.New(tr("Invalid pattern in [secrets]: %v", err))
+		}
+	}
+	return nil
+}
+
+// An added line that looks like it holds a secret
+type secretMatch struct {
+	path  string // from the root
+	line  int
+	match string
+}
+
+// Where the match is, with most of it hidden so the secret isn't shown
+// on screen while asking about it
+func (s secretMatch) String() string {
+	shown := s.match
+	if len(shown) > 4 {
+		shown = shown[:4] + "…"
+	}
+	return fmt.Sprintf("%s:%d  %s", s.path, s.line, shown)
+}
+
+func compileSecretPatterns() []*regexp.Regexp {
+	var patterns []*regexp.Regexp
+	for _, p := range cfg.Secrets.Patterns {
+		// Checked with the config
+		if re, err := regexp.Compile(p); err == nil {
+			patterns = append(patterns, re)
+		}
+	}
+	return patterns
+}
+
+func matchSecret(patterns []*regexp.Regexp, text string) string {
+	for _, re := range patterns {
+		if match := re.FindString(text); match != "" {
+			return match
+		}
+	}
+	return ""
+}
+
+// The added lines of a diff without context that match, numbered from the
+// hunk headers. Paths are from the root, as with --no-relative.
+func findSecretsInDiff(patterns []*regexp.Regexp, diff []byte) []secretMatch {
+	var matches []secretMatch
+	var path string
+	line := 0
+	for _, text := range strings.Split(string(diff), "\n") {
+		switch {
+		case strings.HasPrefix(text, "+++ "):
+			path = strings.TrimPrefix(strings.TrimPrefix(text, "+++ "), "b/")
+		case strings.HasPrefix(text, "@@ "):
+			// @@ -a,b +c,d @@: the added lines start at c
+			fields := strings.Fields(text)
+			if len(fields) > 2 {
+				start, _, _ := strings.Cut(strings.TrimPrefix(fields[2], "+"), ",")
+				line, _ = strconv.Atoi(start)
+			}
+		case strings.HasPrefix(text, "+"):
+			if match := matchSecret(patterns, text[1:]); match != "" {
+				matches = append(matches, secretMatch{path, line, match})
+			}
+			line++
+		case strings.HasPrefix(text, " "):
+			line++
+		}
+	}
+	return matches
+}
+
+// Every line of a new file is added
+func findSecretsInFile(patterns []*regexp.Regexp, path string, content []byte) []secretMatch {
+	var matches []secretMatch
+	for i, text := range strings.Split(string(content), "\n") {
+		if match := matchSecret(patterns, text); match != "" {
+			matches = append(matches, secretMatch{path, i + 1, match})
+		}
+	}
+	return matches
+}
+
+// Secrets in what staging the files would add to the index: the unstaged
+// changes of tracked files and the content of untracked ones, leaving out
+// binary files like git diff does
+func findStagedSecrets(r repo, files []fileEntry) []secretMatch {
+	patterns := compileSecretPatterns()
+	if len(patterns) == 0 {
+		return nil
+	}
+	var matches []secretMatch
+	var tracked, untracked []string
+	for _, f := range files {
+		switch {
+		case !f.untracked:
+			tracked = append(tracked, f.pathFromCwd)
+		case strings.HasSuffix(f.pathFromGitRoot, "/"):
+			// An untracked directory, listed once, stages the files in it
+			output, _ := r.atRoot().git("ls-files", "--others", "--exclude-standard", "-z", "--", f.pathFromGitRoot).Output()
+			untracked = append(untracked, strings.FieldsFunc(string(output), func(c rune) bool { return c == 0 })...)
+		default:
+			untracked = append(untracked, f.pathFromGitRoot)
+		}
+	}
+	for _, pathFromGitRoot := range untracked {
+		path := filepath.Join(r.root, pathFromGitRoot)
+		if info, err := os.Stat(path); err != nil || !info.Mode().IsRegular() || info.Size() > secretScanMaxFile {
+			continue
+		}
+		content, err := os.ReadFile(path)
+		if err == nil && !bytes.Contains(content, []byte{0}) {
+			matches = append(matches, findSecretsInFile(patterns, pathFromGitRoot, content)...)
+		}
+	}
+	for batch := range slices.Chunk(tracked, jobBatchSize) {
+		args := append([]string{"--no-color", "--no-ext-diff", "--no-textconv", "--no-relative", "-U0", "--"}, batch...)
+		output, _, _ := r.backend.Diff(diffLineLimit, args...)
+		matches = append(matches, findSecretsInDiff(patterns, output)...)
+	}
+	return matches
+}
+
+// Ask before staging what looks like secrets, listing where they are
+func (m *model) askAboutSecrets(matches []secretMatch, onYes func(m *model)) {
+	details := []string{tr("Possible secrets in the changes to stage"), ""}
+	for _, s := range matches[:min(len(matches), secretsShown)] {
+		details = append(details, s.String())
+	}
+	if len(matches) > secretsShown {
+		details = append(details, tr("and %d more", len(matches)-secretsShown))
+	}
+	details = append(details, "", tr("Once committed, they stay in the history even if removed later."))
+	m.confirm = &confirmation{
+		message: tr("Stage %d possible secret(s) anyway?", len(matches)),
+		details: details,
+		onYes:   onYes,
+	}
+}
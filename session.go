@@ -0,0 +1,136 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/hzqtc/git-istage/gitx"
+)
+
+// Version of the session files written by --record
+const sessionVersion = 1
+
+// A session recorded with --record: the keys pressed and the terminal sizes,
+// each with when it happened, and what the git backend of every repository
+// answered. Git commands run outside the backend aren't recorded.
+type session struct {
+	Version int            `json:"version"`
+	Review  bool           `json:"review,omitempty"`
+	Events  []sessionEvent `json:"events"`
+	Repos   []sessionRepo  `json:"repos"`
+}
+
+type sessionEvent struct {
+	At     time.Duration `json:"at"` // since the program started
+	Key    *tea.Key      `json:"key,omitempty"`
+	Width  int           `json:"width,omitempty"`
+	Height int           `json:"height,omitempty"`
+}
+
+type sessionRepo struct {
+	Root  string      `json:"root"`
+	Cwd   string      `json:"cwd"`
+	Calls []gitx.Call `json:"calls"`
+}
+
+// Records the session while the program runs
+type sessionRecorder struct {
+	mu        sync.Mutex
+	start     time.Time
+	events    []sessionEvent
+	repos     []repo
+	recorders []*gitx.Recorder
+}
+
+// Start recording the calls to the backends of repos, which are changed to
+// go through the recorder
+func recordSession(repos []repo) *sessionRecorder {
+	s := &sessionRecorder{start: time.Now()}
+	for i := range repos {
+		rec := gitx.NewRecorder(repos[i].backend)
+		repos[i].backend = rec
+		s.repos = append(s.repos, repos[i])
+		s.recorders = append(s.recorders, rec)
+	}
+	return s
+}
+
+// A tea.WithFilter filter keeping the keys and sizes the program gets
+func (s *sessionRecorder) filter(_ tea.Model, msg tea.Msg) tea.Msg {
+	event := sessionEvent{At: time.Since(s.start)}
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		key := tea.Key(msg)
+		event.Key = &key
+	case tea.WindowSizeMsg:
+		event.Width, event.Height = msg.Width, msg.Height
+	default:
+		return msg
+	}
+	s.mu.Lock()
+	s.events = append(s.events, event)
+	s.mu.Unlock()
+	return msg
+}
+
+func (s *sessionRecorder) write(path string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	recorded := session{Version: sessionVersion, Review: reviewAtStart, Events: s.events}
+	for i, r := range s.repos {
+		recorded.Repos = append(recorded.Repos, sessionRepo{r.root, r.cwd, s.recorders[i].Calls()})
+	}
+	data, err := json.MarshalIndent(recorded, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+func readSession(path string) (session, error) {
+	var s session
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return s, err
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return s, err
+	}
+	if s.Version != sessionVersion {
+		return s, errors.New(tr("Unsupported session version %d", s.Version))
+	}
+	return s, nil
+}
+
+// Repositories answering from the recording. Like the demo they live in a
+// directory that doesn't exist, so replaying can't change a real repository.
+func (s session) replayRepos() []repo {
+	var repos []repo
+	for _, recorded := range s.Repos {
+		root := filepath.Join(os.TempDir(), "git-istage-replay", filepath.Base(recorded.Root))
+		cwd := root
+		if rel, err := filepath.Rel(recorded.Root, recorded.Cwd); err == nil {
+			cwd = filepath.Join(root, rel)
+		}
+		repos = append(repos, repo{root: root, cwd: cwd, gitDir: filepath.Join(root, ".git"), backend: gitx.NewReplay(recorded.Calls)})
+	}
+	return repos
+}
+
+// Send the recorded keys and sizes to the program as they came while recording
+func (s session) replayEvents(p *tea.Program) {
+	start := time.Now()
+	for _, e := range s.Events {
+		time.Sleep(time.Until(start.Add(e.At)))
+		if e.Key != nil {
+			p.Send(tea.KeyMsg(*e.Key))
+		} else {
+			p.Send(tea.WindowSizeMsg{Width: e.Width, Height: e.Height})
+		}
+	}
+}
@@ -0,0 +1,308 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Arguments each `git-istage config` subcommand takes
+var configCommands = map[string]int{"list": 0, "get": 1, "set": 2}
+
+// A setting of the config file with a single value, named with its table
+// first like diff.fold_context. Lists and tables like [keys] are left to
+// editing the file.
+type setting struct {
+	name  string
+	value reflect.Value // the field of a config
+}
+
+// The settings of c in the order the config declares them
+func settingsOf(c *config) []setting {
+	var settings []setting
+	var walk func(prefix string, v reflect.Value)
+	walk = func(prefix string, v reflect.Value) {
+		for i := range v.NumField() {
+			name, f := v.Type().Field(i).Tag.Get("toml"), v.Field(i)
+			switch f.Kind() {
+			case reflect.Struct:
+				walk(prefix+name+".", f)
+			case reflect.Bool, reflect.Int, reflect.String:
+				settings = append(settings, setting{prefix + name, f})
+			}
+		}
+	}
+	walk("", reflect.ValueOf(c).Elem())
+	return settings
+}
+
+func findSetting(c *config, name string) (setting, error) {
+	for _, s := range settingsOf(c) {
+		if s.name == name {
+			return s, nil
+		}
+	}
+	return setting{}, errors.New(tr("Unknown setting %q, git-istage config list shows them all", name))
+}
+
+// The value as it's typed, a string without quotes
+func (s setting) text() string {
+	return fmt.Sprint(s.value.Interface())
+}
+
+// The value as TOML writes it
+func (s setting) literal() string {
+	if s.value.Kind() == reflect.String {
+		return strconv.Quote(s.value.String())
+	}
+	return s.text()
+}
+
+// Set the value from how it's typed. A string may be quoted.
+func (s setting) set(text string) error {
+	switch s.value.Kind() {
+	case reflect.Bool:
+		b, err := strconv.ParseBool(text)
+		if err != nil {
+			return errors.New(tr("%s takes true or false", s.name))
+		}
+		s.value.SetBool(b)
+	case reflect.Int:
+		n, err := strconv.Atoi(text)
+		if err != nil {
+			return errors.New(tr("%s takes a whole number", s.name))
+		}
+		s.value.SetInt(int64(n))
+	default:
+		if unquoted, err := strconv.Unquote(text); err == nil {
+			text = unquoted
+		}
+		s.value.SetString(text)
+	}
+	return nil
+}
+
+// Write name = value into TOML, replacing the line that sets it or adding
+// one at the end of its table, so the rest and its comments stay as they are
+func setTOMLValue(content, name, value string) string {
+	table, key := "", name
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		table, key = name[:i], name[i+1:]
+	}
+	var lines []string
+	if content != "" {
+		lines = strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	}
+	current, end := "", -1
+	if table == "" {
+		end = 0
+	}
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "[["):
+			// An array of tables, like [[launchers]]
+			current = "[["
+		case strings.HasPrefix(trimmed, "["):
+			current, _, _ = strings.Cut(strings.TrimPrefix(trimmed, "["), "]")
+			current = strings.TrimSpace(current)
+			if current == table {
+				end = i + 1
+			}
+		case current != table || trimmed == "" || strings.HasPrefix(trimmed, "#"):
+		default:
+			if k, _, ok := strings.Cut(trimmed, "="); ok && strings.Trim(strings.TrimSpace(k), `"`) == key {
+				lines[i] = line[:len(line)-len(strings.TrimLeft(line, " \t"))] + key + " = " + value
+				return strings.Join(lines, "\n") + "\n"
+			}
+			end = i + 1
+		}
+	}
+	if end < 0 {
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, "["+table+"]", key+" = "+value)
+	} else {
+		lines = append(lines[:end], append([]string{key + " = " + value}, lines[end:]...)...)
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// Set a setting in the config file, creating it if needed, and return the
+// config as the file now has it. Nothing is written when the value or the
+// file it makes is invalid.
+func saveSetting(name, text string) (config, error) {
+	path := configPath()
+	if path == "" {
+		return config{}, errors.New(tr("No user config directory to keep the config file in"))
+	}
+	content, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return config{}, err
+	}
+	c := defaultConfig()
+	s, err := findSetting(&c, name)
+	if err != nil {
+		return config{}, err
+	}
+	if err := s.set(text); err != nil {
+		return config{}, err
+	}
+	updated := setTOMLValue(string(content), name, s.literal())
+	c = defaultConfig()
+	if _, err := toml.Decode(updated, &c); err != nil {
+		return config{}, err
+	}
+	if err := checkConfig(c); err != nil {
+		return config{}, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return config{}, err
+	}
+	return c, os.WriteFile(path, []byte(updated), 0o644)
+}
+
+// Run `git-istage config list`, `get <name>` or `set <name> <value>`
+func runConfigCommand(w io.Writer, args []string) error {
+	c, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	switch args[0] {
+	case "list":
+		for _, s := range settingsOf(&c) {
+			fmt.Fprintf(w, "%s = %s\n", s.name, s.literal())
+		}
+	case "get":
+		s, err := findSetting(&c, args[1])
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, s.text())
+	case "set":
+		c, err := saveSetting(args[1], args[2])
+		if err != nil {
+			return err
+		}
+		s, _ := findSetting(&c, args[1])
+		fmt.Fprintln(w, tr("Set %s = %s in %s", s.name, s.literal(), configPath()))
+	}
+	return nil
+}
+
+// State of settingsMode
+type settingsList struct {
+	cursor int
+	input  *textinput.Model // the value being typed for the setting under the cursor
+}
+
+func (m *model) openSettings() {
+	m.settings = &settingsList{}
+	m.mode = settingsMode
+}
+
+func (m *model) updateSettings(key string) {
+	sl := m.settings
+	settings := settingsOf(&cfg)
+	switch {
+	case m.keys.up.matches(key):
+		sl.cursor = max(sl.cursor-1, 0)
+	case m.keys.down.matches(key):
+		sl.cursor = min(sl.cursor+1, len(settings)-1)
+	case m.keys.focusList.matches(key), m.keys.settings.matches(key):
+		m.settings = nil
+		m.mode = listMode
+		m.loadDiff()
+	case m.keys.editSetting.matches(key), m.keys.toggle.matches(key):
+		s := settings[sl.cursor]
+		if s.value.Kind() == reflect.Bool {
+			m.applySetting(s.name, strconv.FormatBool(!s.value.Bool()))
+			return
+		}
+		input := textinput.New()
+		input.Prompt = s.name + " = "
+		input.PromptStyle = promptStyle
+		input.Cursor.SetMode(cursor.CursorStatic)
+		input.SetValue(s.text())
+		input.Focus()
+		sl.input = &input
+	}
+}
+
+func (m *model) updateSettingInput(msg tea.KeyMsg) {
+	sl := m.settings
+	switch key := keyName(msg); {
+	case key == "enter":
+		text := sl.input.Value()
+		sl.input = nil
+		m.applySetting(settingsOf(&cfg)[sl.cursor].name, text)
+	case m.keys.cancel.matches(key):
+		sl.input = nil
+	default:
+		*sl.input, _ = sl.input.Update(msg)
+	}
+}
+
+func (m model) settingInputView() string {
+	input := m.settings.input
+	input.Width = max(m.width-ansi.StringWidth(input.Prompt)-1, 0)
+	return input.View()
+}
+
+// Save a setting to the config file and use it from now on. Those read at
+// start, like the keys, take effect on the next one.
+func (m *model) applySetting(name, text string) {
+	saved, err := saveSetting(name, text)
+	if err != nil {
+		m.status = tr("Failed to save %s: %v", name, err)
+		return
+	}
+	from, _ := findSetting(&saved, name)
+	to, _ := findSetting(&cfg, name)
+	to.value.Set(from.value)
+	theme := cfg.Theme
+	if theme == "" {
+		theme = defaultThemeName()
+	}
+	for _, err := range []error{applyTheme(theme), setLanguage(cfg.Language), setGitTimeout(cfg.GitTimeout)} {
+		if err != nil {
+			m.status = tr("Failed to apply %s: %v", name, err)
+			return
+		}
+	}
+	if cfg.Diff.GitColors {
+		applyGitColors(m.repo)
+	}
+	m.status = tr("Saved %s = %s to %s", name, to.literal(), configPath())
+	m.loadDiff()
+}
+
+// Every setting with its value, the one under the cursor editable
+func (m model) settingsView(width, height int) string {
+	sl := m.settings
+	settings := settingsOf(&cfg)
+	nameWidth := 0
+	for _, s := range settings {
+		nameWidth = max(nameWidth, len(s.name))
+	}
+	rows := []string{sectionStyle.Render(ansi.Truncate(tr("Settings, saved to %s", configPath()), width, "…"))}
+	offset := max(sl.cursor+1-(height-1), 0)
+	for i := offset; i < len(settings) && len(rows) < height; i++ {
+		s := settings[i]
+		row := cfg.Glyphs.cursor(i == sl.cursor) + fmt.Sprintf("%-*s ", nameWidth, s.name) + badgeStyle.Render(s.literal())
+		rows = append(rows, ansi.Truncate(row, width, "…"))
+	}
+	return strings.Join(rows, "\n")
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/hzqtc/git-istage/gitx"
+)
+
+type sparseCheckout struct {
+	enabled bool
+	cone    bool
+	dirs    []string        // directories in the cone, in cone mode
+	skipped map[string]bool // tracked files marked skip-worktree, in non-cone mode
+}
+
+func getSparseCheckout(r repo) sparseCheckout {
+	var s sparseCheckout
+	if !r.gitConfigBool("core.sparseCheckout") {
+		return s
+	}
+	s.enabled = true
+	s.cone = r.gitConfigBool("core.sparseCheckoutCone")
+
+	if s.cone {
+		output, _ := r.git("sparse-checkout", "list").Output()
+		for line := range strings.SplitSeq(string(output), "\n") {
+			if dir := strings.Trim(line, "/"); dir != "" {
+				s.dirs = append(s.dirs, dir)
+			}
+		}
+		return s
+	}
+
+	// Without cone mode patterns are arbitrary, so rely on git's view of tracked files
+	s.skipped = make(map[string]bool)
+	output, _ := r.git("ls-files", "-t", "--full-name").Output()
+	for line := range strings.SplitSeq(string(output), "\n") {
+		if path, ok := strings.CutPrefix(line, "S "); ok {
+			s.skipped[gitx.UnquotePath(path)] = true
+		}
+	}
+	return s
+}
+
+// Whether a path from git root is outside the sparse-checkout definition
+func (s sparseCheckout) excludes(pathFromGitRoot string) bool {
+	if !s.enabled {
+		return false
+	}
+	if !s.cone {
+		return s.skipped[pathFromGitRoot]
+	}
+
+	// In cone mode, files directly in the root, in any cone directory or in
+	// any parent of a cone directory are included
+	parent := ""
+	if i := strings.LastIndex(pathFromGitRoot, "/"); i >= 0 {
+		parent = pathFromGitRoot[:i]
+	}
+	if parent == "" {
+		return false
+	}
+	for _, dir := range s.dirs {
+		if strings.HasPrefix(pathFromGitRoot, dir+"/") || dir == parent || strings.HasPrefix(dir, parent+"/") {
+			return false
+		}
+	}
+	return true
+}
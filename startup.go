@@ -0,0 +1,149 @@
+package main
+
+import (
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// What the list shows: the changed files and what's needed to keep them up
+// to date
+type repoStatus struct {
+	files     []fileEntry
+	review    *reviewBase // nil when not reviewing
+	reviewErr error       // why there's nothing to review against
+	flagged   []flaggedFile
+	snapshot  repoSnapshot
+}
+
+// The branch and what's going on with it, shown in the header
+type branchInfo struct {
+	branch    string
+	stash     stashInfo
+	operation *operation
+	upstream  upstreamInfo
+}
+
+// Results of the loads started by Init, each run in its own goroutine so
+// a slow git status doesn't hold up the others
+type statusLoadedMsg struct {
+	root   string
+	status repoStatus
+	index  string // the tree of the index before the session, see writeIndexTree
+}
+
+// Files git status listed so far while the list is loading, the next
+// message coming from updates
+type statusProgressMsg struct {
+	root    string
+	files   []fileEntry
+	updates <-chan tea.Msg
+}
+
+type branchLoadedMsg struct {
+	root string
+	info branchInfo
+}
+
+// Progress is optional, see streamGitChanges
+func getRepoStatus(r repo, reviewing bool, progress func([]fileEntry)) repoStatus {
+	var s repoStatus
+	if reviewing {
+		// The upstream may have moved since the last time
+		if base, err := getReviewBase(r); err != nil {
+			s.reviewErr = err
+		} else {
+			s.review = &base
+		}
+	}
+	s.files = streamGitChanges(r, progress)
+	if s.review != nil {
+		s.files = append(s.files, getBranchFiles(r, *s.review, s.files)...)
+		slices.SortFunc(s.files, func(a, b fileEntry) int {
+			return strings.Compare(a.pathFromGitRoot, b.pathFromGitRoot)
+		})
+	}
+	s.flagged = getFlaggedFiles(r, getSparseCheckout(r))
+	s.snapshot = takeSnapshot(r)
+	return s
+}
+
+func getBranchInfo(r repo) branchInfo {
+	return branchInfo{r.backend.Branch(), getStashInfo(r), getOperation(r), getUpstream(r)}
+}
+
+func (m *model) applyStatus(s repoStatus) {
+	if s.reviewErr != nil {
+		m.status = s.reviewErr.Error()
+	}
+	m.review = s.review
+	m.keepingCursor(func() {
+		m.files = s.files
+	})
+	m.flagged = s.flagged
+	m.snapshot = s.snapshot
+}
+
+func (m *model) applyBranchInfo(info branchInfo) {
+	m.branch = info.branch
+	m.stash = info.stash
+	m.operation = info.operation
+	m.upstream = info.upstream
+	m.loadingBranch = false
+}
+
+// Wait for the next message sent to a channel
+func receive(updates <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return <-updates
+	}
+}
+
+// Load the status and the branch of the repository at the same time, the
+// list showing files as git status lists them. The diff of the first file is
+// loaded once the list is complete, while the branch may still be loading.
+func (m model) Init() tea.Cmd {
+	r, reviewing := m.repo, reviewAtStart
+	return tea.Batch(
+		func() tea.Msg {
+			updates := make(chan tea.Msg)
+			go func() {
+				index := writeIndexTree(r)
+				status := getRepoStatus(r, reviewing, func(files []fileEntry) {
+					updates <- statusProgressMsg{r.root, files, updates}
+				})
+				updates <- statusLoadedMsg{r.root, status, index}
+			}()
+			return <-updates
+		},
+		func() tea.Msg {
+			return branchLoadedMsg{r.root, getBranchInfo(r)}
+		},
+	)
+}
+
+func (m *model) statusProgress(msg statusProgressMsg) {
+	m.keepingCursor(func() {
+		m.files = msg.files
+	})
+	m.queue(receive(msg.updates))
+}
+
+func (m *model) statusLoaded(msg statusLoadedMsg) {
+	m.loading = false
+	m.sessionIndex = msg.index
+	m.applyStatus(msg.status)
+	if m.selected() < 0 && len(m.files) > 0 {
+		// Start on the first file rather than its section header
+		m.cursor = 1
+	}
+	m.loadDiff()
+}
+
+func (m *model) branchLoaded(msg branchLoadedMsg) {
+	// A reload since has newer information
+	if m.loadingBranch {
+		m.applyBranchInfo(msg.info)
+	}
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type stashInfo struct {
+	count  int
+	latest string // subject of stash@{0}
+}
+
+func getStashInfo(r repo) stashInfo {
+	output, err := r.git("stash", "list", "--format=%gs").Output()
+	if err != nil {
+		return stashInfo{}
+	}
+	lines := splitDiffLines(string(output))
+	if len(lines) == 0 {
+		return stashInfo{}
+	}
+	return stashInfo{count: len(lines), latest: lines[0]}
+}
+
+// Pop the latest stash into the work tree, asking first
+func (m *model) popStash() {
+	if m.stash.count == 0 {
+		m.status = tr("No stashes to pop")
+		return
+	}
+	m.ask(tr("Pop stash@{0} (%s)?", m.stash.latest), func(m *model) {
+		output, err := m.repo.git("stash", "pop").CombinedOutput()
+		m.reload()
+		m.loadDiff()
+		if err == nil {
+			m.status = tr("Popped stash@{0}")
+			return
+		}
+		m.status = popFailure(output)
+	})
+}
+
+// Why git stash pop failed, from its output
+func popFailure(output []byte) string {
+	var conflicts []string
+	for line := range strings.SplitSeq(string(output), "\n") {
+		// Lines look like "CONFLICT (content): Merge conflict in <path>"
+		if _, path, ok := strings.Cut(line, "Merge conflict in "); ok && strings.HasPrefix(line, "CONFLICT") {
+			conflicts = append(conflicts, path)
+		}
+	}
+	if len(conflicts) > 0 {
+		return tr("Stash applied with conflicts in %s, the stash was kept", strings.Join(conflicts, ", "))
+	}
+	return tr("Failed to pop stash: %s", firstLine(string(output)))
+}
+
+// Starts the message of the safety stashes, which tells them from the user's own
+const safetyStashPrefix = "git-istage: "
+
+// Stash the current changes without touching the work tree so a bulk
+// destructive operation can be undone. untracked is the option of git stash
+// push that saves untracked files too, --include-untracked or --all for the
+// ignored ones as well, or "" for none. Returns the stash commit, or "" when
+// there is nothing to stash.
+func createSafetyStash(r repo, message, untracked string) (string, error) {
+	if untracked == "" {
+		output, err := r.git("stash", "create", message).Output()
+		if err != nil {
+			return "", err
+		}
+		commit := strings.TrimSpace(string(output))
+		if commit == "" {
+			return "", nil
+		}
+		// Keep it in the stash list, otherwise it's only reachable by its hash
+		return commit, r.git("stash", "store", "-m", message, commit).Run()
+	}
+
+	// `git stash create` can't include untracked files, so stash for real and
+	// put everything back right away
+	before := latestStash(r)
+	if output, err := r.git("stash", "push", untracked, "-m", message).CombinedOutput(); err != nil {
+		return "", fmt.Errorf("%s", firstLine(string(output)))
+	}
+	// With nothing to save push doesn't stash, and stash@{0} is still the
+	// user's own
+	commit := latestStash(r)
+	if commit == before {
+		return "", nil
+	}
+	if err := r.run("stash", "apply", "--index", commit); err != nil {
+		return commit, fmt.Errorf("failed to restore changes from safety stash %s", shortHash(commit))
+	}
+	return commit, nil
+}
+
+// The commit of stash@{0}, "" without stashes
+func latestStash(r repo) string {
+	return trimmedOutput(r.git("rev-parse", "-q", "--verify", "stash@{0}").Output())
+}
+
+// Start a destructive bulk operation, creating a safety stash first if configured
+func (m *model) withSafetyStash(message, title, untracked string, steps []jobStep) tea.Cmd {
+	if cfg.AutoStash {
+		commit, err := createSafetyStash(m.repo, safetyStashPrefix+message, untracked)
+		if err != nil {
+			m.status = tr("Aborted, failed to create a safety stash: %v", err)
+			m.reload()
+			return nil
+		}
+		if commit != "" {
+			m.safetyStash = commit
+		}
+	}
+
+	return m.startJob(title, steps, func(m *model) {
+		if cfg.AutoStash && m.safetyStash != "" {
+			m.status = tr("Done, recover with `git stash apply %s`", shortHash(m.safetyStash))
+		} else {
+			m.status = tr("Done, the old content is in the trash, %s to recover it", m.keys.recover.Help().Key)
+		}
+	})
+}
+
+// Discard unstaged changes of all tracked files
+func (m *model) discardAll() {
+	var paths, trashPaths []string
+	for _, f := range m.files {
+		if f.status != staged && f.status != committed && !f.untracked {
+			paths = append(paths, f.pathFromCwd)
+			trashPaths = append(trashPaths, f.pathFromGitRoot)
+		}
+	}
+	if len(paths) == 0 {
+		m.status = tr("No unstaged changes to discard")
+		return
+	}
+	m.ask(tr("Discard unstaged changes in %d file(s)?", len(paths)), func(m *model) {
+		r := m.repo
+		if err := saveToTrash(r, trashPaths); err != nil {
+			m.status = tr("Aborted, failed to copy the files to the trash: %v", err)
+			return
+		}
+		m.queue(m.withSafetyStash("discard all", tr("Discarding"), "", batchSteps(paths, func(paths []string) error {
+			return r.run(append([]string{"restore", "--"}, paths...)...)
+		})))
+	})
+}
+
+func shortHash(commit string) string {
+	return commit[:min(len(commit), 7)]
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(s), "\n")
+	return line
+}
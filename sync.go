@@ -0,0 +1,94 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// The upstream of the current branch and how far HEAD is from it, name ""
+// without one
+type upstreamInfo struct {
+	name   string
+	ahead  int
+	behind int
+}
+
+func getUpstream(r repo) upstreamInfo {
+	name := trimmedOutput(r.git("rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{upstream}").Output())
+	if name == "" {
+		return upstreamInfo{}
+	}
+	// Counts of commits only in the upstream, then only in HEAD
+	fields := strings.Fields(trimmedOutput(r.git("rev-list", "--left-right", "--count", "@{upstream}...HEAD").Output()))
+	u := upstreamInfo{name: name}
+	if len(fields) == 2 {
+		u.behind, _ = strconv.Atoi(fields[0])
+		u.ahead, _ = strconv.Atoi(fields[1])
+	}
+	return u
+}
+
+// Like "origin/main ↑2 ↓3" for the header, or that there's no upstream
+func (m model) upstreamBadge() string {
+	u := m.upstream
+	if u.name == "" {
+		return badgeStyle.Render(tr("no upstream"))
+	}
+	badge := u.name
+	if u.ahead > 0 {
+		badge += " " + stagedStyle.Render("↑"+strconv.Itoa(u.ahead))
+	}
+	if u.behind > 0 {
+		badge += " " + partiallyStagedStyle.Render("↓"+strconv.Itoa(u.behind))
+	}
+	if u.ahead == 0 && u.behind == 0 {
+		badge += " " + badgeStyle.Render(tr("up to date"))
+	}
+	return badge
+}
+
+type fetchFinishedMsg struct {
+	root     string
+	upstream upstreamInfo
+	err      error
+}
+
+// git fetch in the background, see runInBackground
+func (m *model) fetch() tea.Cmd {
+	if m.fetching {
+		return nil
+	}
+	m.fetching = true
+	m.status = tr("Fetching…")
+	r := m.repo
+	return func() tea.Msg {
+		err := runInBackground(r.git("fetch", "--quiet"))
+		return fetchFinishedMsg{r.root, getUpstream(r), err}
+	}
+}
+
+func (m *model) fetchFinished(msg fetchFinishedMsg) {
+	m.fetching = false
+	m.upstream = msg.upstream
+	u := msg.upstream
+	switch {
+	case needsCredentials(msg.err):
+		m.ask(tr("Fetching needs credentials, enter them in the terminal?"), func(m *model) {
+			m.fetching = true
+			r := m.repo
+			m.queue(runInTerminal(r.interactiveGit("fetch", "--quiet"), func(err error) tea.Msg {
+				return fetchFinishedMsg{r.root, getUpstream(r), err}
+			}))
+		})
+	case msg.err != nil:
+		m.status = tr("Failed to fetch: %v", msg.err)
+	case u.name == "":
+		m.status = tr("Fetched, the branch has no upstream to compare with")
+	case u.behind > 0:
+		m.status = tr("Fetched, %d commit(s) behind %s, pull before committing", u.behind, u.name)
+	default:
+		m.status = tr("Fetched, nothing new on %s", u.name)
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Times a zero window size is asked for again, some terminals like tmux
+// popups only have a size a moment after the program starts
+const sizeRetries = 20
+
+const sizeRetryInterval = 100 * time.Millisecond
+
+type sizeRetryMsg struct{}
+
+// The terminal the UI is drawn on, and what to put back when it's done
+type terminal struct {
+	out       *os.File
+	tmux      bool
+	tmuxTitle string // title of the tmux pane before
+}
+
+// The terminal of stdout, or the controlling terminal when stdout isn't one,
+// like in `git istage | tee`, so the UI still gets a size
+func openTerminal() terminal {
+	t := terminal{out: os.Stdout, tmux: os.Getenv("TMUX") != ""}
+	if info, err := os.Stdout.Stat(); err == nil && info.Mode()&os.ModeCharDevice == 0 {
+		if tty, err := os.OpenFile("/dev/tty", os.O_WRONLY, 0); err == nil {
+			t.out = tty
+		}
+	}
+	if t.tmux && cfg.TerminalTitle {
+		t.tmuxTitle = trimmedOutput(exec.Command("tmux", "display-message", "-p", "#{pane_title}").Output())
+	}
+	return t
+}
+
+// Keep the title to restore it on exit, on the title stack of xterm-like
+// terminals. tmux has no stack, its pane title is set back in restoreTitle.
+func (t terminal) saveTitle() {
+	if cfg.TerminalTitle {
+		fmt.Fprint(t.out, "\x1b[22;0t")
+	}
+}
+
+func (t terminal) restoreTitle() {
+	if !cfg.TerminalTitle {
+		return
+	}
+	fmt.Fprint(t.out, "\x1b[23;0t")
+	if t.tmux {
+		fmt.Fprintf(t.out, "\x1b]2;%s\x07", t.tmuxTitle)
+	}
+}
+
+// Like "istage: git-istage (main)", of the repository in the active tab
+func (a app) windowTitle() string {
+	if len(a.tabs) == 0 {
+		return ""
+	}
+	tab := a.tabs[a.active]
+	name := filepath.Base(tab.repo.root)
+	if tab.branch == "" {
+		return "istage: " + name
+	}
+	return fmt.Sprintf("istage: %s (%s)", name, tab.branch)
+}
+
+// Set the terminal title when the active repository or its branch changed
+func (a app) retitle(cmd tea.Cmd) (tea.Model, tea.Cmd) {
+	if title := a.windowTitle(); cfg.TerminalTitle && title != a.title {
+		a.title = title
+		cmd = tea.Batch(cmd, tea.SetWindowTitle(title))
+	}
+	return a, cmd
+}
+
+// Ask for the size again a little later if the terminal had none
+func (a app) retrySize(msg tea.WindowSizeMsg) (app, tea.Cmd) {
+	if (msg.Width > 0 && msg.Height > 0) || a.sizeRetries >= sizeRetries {
+		return a, nil
+	}
+	a.sizeRetries++
+	return a, tea.Tick(sizeRetryInterval, func(time.Time) tea.Msg { return sizeRetryMsg{} })
+}
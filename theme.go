@@ -0,0 +1,237 @@
+package main
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// A color with one variant per terminal color profile
+type paletteColor struct {
+	ansi      string // one of the 16 basic colors
+	ansi256   string
+	trueColor string
+}
+
+type palette struct {
+	cursor          paletteColor
+	staged          paletteColor
+	partiallyStaged paletteColor
+	unstaged        paletteColor
+	diffAdded       paletteColor
+	diffRemoved     paletteColor
+	// Added and removed lines that only moved, see markMoved
+	diffMovedAdded   paletteColor
+	diffMovedRemoved paletteColor
+	diffHunk         paletteColor
+	diffMeta         paletteColor
+	separator        paletteColor
+	conflict         paletteColor
+}
+
+var themes = map[string]palette{
+	"dark": {
+		cursor:           paletteColor{"12", "12", "#5f87ff"},
+		staged:           paletteColor{"10", "42", "#00d787"},
+		partiallyStaged:  paletteColor{"11", "11", "#ffd75f"},
+		unstaged:         paletteColor{"8", "240", "#585858"},
+		diffAdded:        paletteColor{"2", "77", "#5fd75f"},
+		diffRemoved:      paletteColor{"1", "203", "#ff5f5f"},
+		diffMovedAdded:   paletteColor{"14", "87", "#5fffff"},
+		diffMovedRemoved: paletteColor{"13", "213", "#ff87ff"},
+		diffHunk:         paletteColor{"6", "37", "#00afaf"},
+		diffMeta:         paletteColor{"15", "252", "#d0d0d0"},
+		separator:        paletteColor{"8", "238", "#444444"},
+		conflict:         paletteColor{"9", "196", "#ff5f87"},
+	},
+	"light": {
+		cursor:           paletteColor{"4", "25", "#005faf"},
+		staged:           paletteColor{"2", "28", "#008700"},
+		partiallyStaged:  paletteColor{"3", "136", "#af8700"},
+		unstaged:         paletteColor{"8", "245", "#8a8a8a"},
+		diffAdded:        paletteColor{"2", "28", "#008700"},
+		diffRemoved:      paletteColor{"1", "124", "#af0000"},
+		diffMovedAdded:   paletteColor{"6", "31", "#0087af"},
+		diffMovedRemoved: paletteColor{"5", "127", "#af00af"},
+		diffHunk:         paletteColor{"6", "30", "#008787"},
+		diffMeta:         paletteColor{"0", "235", "#262626"},
+		separator:        paletteColor{"7", "250", "#bcbcbc"},
+		conflict:         paletteColor{"1", "160", "#d70000"},
+	},
+	// For red-green color blindness: blue and orange instead of green and red
+	"deuteranopia-dark": {
+		cursor:           paletteColor{"15", "231", "#ffffff"},
+		staged:           paletteColor{"12", "33", "#0087ff"},
+		partiallyStaged:  paletteColor{"11", "220", "#ffd700"},
+		unstaged:         paletteColor{"8", "240", "#585858"},
+		diffAdded:        paletteColor{"12", "39", "#00afff"},
+		diffRemoved:      paletteColor{"3", "208", "#ff8700"},
+		diffMovedAdded:   paletteColor{"14", "123", "#87ffff"},
+		diffMovedRemoved: paletteColor{"11", "228", "#ffff87"},
+		diffHunk:         paletteColor{"5", "141", "#af87ff"},
+		diffMeta:         paletteColor{"15", "252", "#d0d0d0"},
+		separator:        paletteColor{"8", "238", "#444444"},
+		conflict:         paletteColor{"13", "213", "#ff87ff"},
+	},
+	"deuteranopia-light": {
+		cursor:           paletteColor{"0", "16", "#000000"},
+		staged:           paletteColor{"4", "25", "#005faf"},
+		partiallyStaged:  paletteColor{"3", "136", "#af8700"},
+		unstaged:         paletteColor{"8", "245", "#8a8a8a"},
+		diffAdded:        paletteColor{"4", "25", "#005faf"},
+		diffRemoved:      paletteColor{"3", "166", "#d75f00"},
+		diffMovedAdded:   paletteColor{"6", "30", "#008787"},
+		diffMovedRemoved: paletteColor{"3", "94", "#875f00"},
+		diffHunk:         paletteColor{"5", "97", "#875faf"},
+		diffMeta:         paletteColor{"0", "235", "#262626"},
+		separator:        paletteColor{"7", "250", "#bcbcbc"},
+		conflict:         paletteColor{"5", "127", "#af00af"},
+	},
+	// Reds look dark to protanopes, so removed lines are a bright amber
+	"protanopia-dark": {
+		cursor:           paletteColor{"15", "231", "#ffffff"},
+		staged:           paletteColor{"12", "33", "#0087ff"},
+		partiallyStaged:  paletteColor{"7", "252", "#d0d0d0"},
+		unstaged:         paletteColor{"8", "240", "#585858"},
+		diffAdded:        paletteColor{"12", "39", "#00afff"},
+		diffRemoved:      paletteColor{"11", "214", "#ffaf00"},
+		diffMovedAdded:   paletteColor{"14", "123", "#87ffff"},
+		diffMovedRemoved: paletteColor{"7", "230", "#ffffd7"},
+		diffHunk:         paletteColor{"5", "141", "#af87ff"},
+		diffMeta:         paletteColor{"15", "252", "#d0d0d0"},
+		separator:        paletteColor{"8", "238", "#444444"},
+		conflict:         paletteColor{"11", "226", "#ffff00"},
+	},
+	"protanopia-light": {
+		cursor:           paletteColor{"0", "16", "#000000"},
+		staged:           paletteColor{"4", "25", "#005faf"},
+		partiallyStaged:  paletteColor{"8", "240", "#585858"},
+		unstaged:         paletteColor{"7", "248", "#a8a8a8"},
+		diffAdded:        paletteColor{"4", "25", "#005faf"},
+		diffRemoved:      paletteColor{"3", "130", "#af5f00"},
+		diffMovedAdded:   paletteColor{"6", "30", "#008787"},
+		diffMovedRemoved: paletteColor{"3", "94", "#875f00"},
+		diffHunk:         paletteColor{"5", "97", "#875faf"},
+		diffMeta:         paletteColor{"0", "235", "#262626"},
+		separator:        paletteColor{"7", "250", "#bcbcbc"},
+		conflict:         paletteColor{"3", "136", "#af8700"},
+	},
+	// For blue-yellow color blindness: teal and pink, which tritanopes tell apart
+	"tritanopia-dark": {
+		cursor:           paletteColor{"15", "231", "#ffffff"},
+		staged:           paletteColor{"14", "44", "#00d7d7"},
+		partiallyStaged:  paletteColor{"13", "218", "#ffafd7"},
+		unstaged:         paletteColor{"8", "240", "#585858"},
+		diffAdded:        paletteColor{"6", "37", "#00afaf"},
+		diffRemoved:      paletteColor{"9", "203", "#ff5f5f"},
+		diffMovedAdded:   paletteColor{"14", "123", "#87ffff"},
+		diffMovedRemoved: paletteColor{"13", "211", "#ff87af"},
+		diffHunk:         paletteColor{"7", "250", "#bcbcbc"},
+		diffMeta:         paletteColor{"15", "255", "#eeeeee"},
+		separator:        paletteColor{"8", "238", "#444444"},
+		conflict:         paletteColor{"9", "196", "#ff0000"},
+	},
+	"tritanopia-light": {
+		cursor:           paletteColor{"0", "16", "#000000"},
+		staged:           paletteColor{"6", "30", "#008787"},
+		partiallyStaged:  paletteColor{"5", "168", "#d75f87"},
+		unstaged:         paletteColor{"8", "245", "#8a8a8a"},
+		diffAdded:        paletteColor{"6", "30", "#008787"},
+		diffRemoved:      paletteColor{"1", "160", "#d70000"},
+		diffMovedAdded:   paletteColor{"4", "24", "#005f87"},
+		diffMovedRemoved: paletteColor{"5", "125", "#af005f"},
+		diffHunk:         paletteColor{"8", "242", "#6c6c6c"},
+		diffMeta:         paletteColor{"0", "235", "#262626"},
+		separator:        paletteColor{"7", "250", "#bcbcbc"},
+		conflict:         paletteColor{"1", "124", "#af0000"},
+	},
+}
+
+func themeNames() []string {
+	var names []string
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Pick the palette variant matching what the terminal can display
+func (c paletteColor) resolve(profile termenv.Profile) lipgloss.TerminalColor {
+	switch profile {
+	case termenv.TrueColor:
+		return lipgloss.Color(c.trueColor)
+	case termenv.ANSI256:
+		return lipgloss.Color(c.ansi256)
+	case termenv.ANSI:
+		return lipgloss.Color(c.ansi)
+	default:
+		return lipgloss.NoColor{}
+	}
+}
+
+func defaultThemeName() string {
+	if lipgloss.HasDarkBackground() {
+		return "dark"
+	}
+	return "light"
+}
+
+func findTheme(name string) (palette, error) {
+	p, ok := themes[name]
+	if !ok {
+		// A theme with dark and light variants, like "deuteranopia"
+		p, ok = themes[name+"-"+defaultThemeName()]
+	}
+	if !ok {
+		return p, errors.New(tr("Unknown theme %q, available themes: %v", name, themeNames()))
+	}
+	return p, nil
+}
+
+func applyTheme(name string) error {
+	p, err := findTheme(name)
+	if err != nil {
+		return err
+	}
+
+	profile := lipgloss.ColorProfile()
+	cursorStyle = lipgloss.NewStyle().Foreground(p.cursor.resolve(profile))
+	stagedStyle = lipgloss.NewStyle().Foreground(p.staged.resolve(profile))
+	partiallyStagedStyle = lipgloss.NewStyle().Foreground(p.partiallyStaged.resolve(profile))
+	unstagedStyle = lipgloss.NewStyle().Foreground(p.unstaged.resolve(profile))
+	diffAddedStyle = lipgloss.NewStyle().Foreground(p.diffAdded.resolve(profile))
+	diffRemovedStyle = lipgloss.NewStyle().Foreground(p.diffRemoved.resolve(profile))
+	diffMovedAddedStyle = lipgloss.NewStyle().Foreground(p.diffMovedAdded.resolve(profile)).Bold(true)
+	diffMovedRemovedStyle = lipgloss.NewStyle().Foreground(p.diffMovedRemoved.resolve(profile)).Bold(true)
+	diffHunkStyle = lipgloss.NewStyle().Foreground(p.diffHunk.resolve(profile))
+	diffMetaStyle = lipgloss.NewStyle().Foreground(p.diffMeta.resolve(profile)).Bold(true)
+	separatorStyle = lipgloss.NewStyle().Foreground(p.separator.resolve(profile))
+	conflictStyle = lipgloss.NewStyle().Foreground(p.conflict.resolve(profile)).Bold(true)
+	activeTabStyle = cursorStyle.Bold(true)
+	promptStyle = partiallyStagedStyle.Bold(true)
+	badgeStyle = unstagedStyle.Italic(true)
+	diffModeStyle = partiallyStagedStyle.Bold(true)
+	sectionStyle = diffMetaStyle
+	selectedLineStyle = lipgloss.NewStyle().Reverse(true)
+	syntaxKeywordStyle = diffHunkStyle.Bold(true)
+	syntaxStringStyle = stagedStyle
+	syntaxNumberStyle = partiallyStagedStyle
+	syntaxCommentStyle = separatorStyle.Italic(true)
+	searchMatchStyle = promptStyle.Reverse(true)
+	dialogStyle = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).
+		BorderForeground(p.partiallyStaged.resolve(profile)).Padding(0, 1)
+	helpStyles = help.Styles{
+		ShortKey:       cursorStyle,
+		ShortDesc:      lipgloss.NewStyle(),
+		ShortSeparator: separatorStyle,
+		Ellipsis:       separatorStyle,
+		FullKey:        cursorStyle,
+		FullDesc:       lipgloss.NewStyle(),
+		FullSeparator:  separatorStyle,
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"errors"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Trailer keys offered in the trailers editor when none are configured
+func defaultTrailerKeys() []string {
+	return []string{"Reviewed-by", "Fixes", "Refs"}
+}
+
+// A trailer of the commit being written, added below the message on commit
+type trailer struct {
+	key   string
+	value string
+}
+
+func (t trailer) String() string {
+	return t.key + ": " + t.value
+}
+
+// Fills in a value for each trailer key, empty ones being left out
+type trailersEditor struct {
+	inputs []textinput.Model
+	focus  int
+}
+
+func checkTrailers(c commitConfig) error {
+	for pattern, trailers := range c.DefaultTrailers {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return errors.New(tr("Invalid pattern %q in [commit.default_trailers]: %v", pattern, err))
+		}
+		for _, t := range trailers {
+			if !trailerLine.MatchString(t) {
+				return errors.New(tr("Invalid trailer %q in [commit.default_trailers], write it as \"Key: value\"", t))
+			}
+		}
+	}
+	return nil
+}
+
+// The trailers [commit.default_trailers] gives the repository, under the
+// first glob in sorted order that matches its directory name or its path
+func defaultTrailers(r repo) []trailer {
+	for _, pattern := range slices.Sorted(maps.Keys(cfg.Commit.DefaultTrailers)) {
+		byName, _ := filepath.Match(pattern, filepath.Base(r.root))
+		byPath, _ := filepath.Match(pattern, r.root)
+		if !byName && !byPath {
+			continue
+		}
+		var trailers []trailer
+		for _, t := range cfg.Commit.DefaultTrailers[pattern] {
+			key, value, _ := strings.Cut(t, ": ")
+			trailers = append(trailers, trailer{key, strings.TrimSpace(value)})
+		}
+		return trailers
+	}
+	return nil
+}
+
+// Open a field for each configured key and each key the commit has a
+// trailer for already, filled in with its value
+func (m *model) openTrailersEditor() {
+	keys := slices.Clone(cfg.Commit.Trailers)
+	for _, t := range m.trailers {
+		if !slices.Contains(keys, t.key) {
+			keys = append(keys, t.key)
+		}
+	}
+	if len(keys) == 0 {
+		m.status = tr("No trailers to fill in, add some to trailers in the [commit] config")
+		return
+	}
+	e := &trailersEditor{}
+	for _, key := range keys {
+		input := textinput.New()
+		input.Prompt = key + ": "
+		input.PromptStyle = promptStyle
+		input.Cursor.SetMode(cursor.CursorStatic)
+		for _, t := range m.trailers {
+			if t.key == key {
+				input.SetValue(t.value)
+			}
+		}
+		e.inputs = append(e.inputs, input)
+	}
+	e.inputs[0].Focus()
+	m.trailersEditor = e
+}
+
+func (m *model) updateTrailersEditor(msg tea.KeyMsg) {
+	e := m.trailersEditor
+	move := func(delta int) {
+		e.inputs[e.focus].Blur()
+		e.focus = (e.focus + delta + len(e.inputs)) % len(e.inputs)
+		e.inputs[e.focus].Focus()
+	}
+	switch key := keyName(msg); {
+	case key == "up", key == "shift+tab":
+		move(-1)
+	case key == "down", key == "tab":
+		move(1)
+	case key == "enter":
+		m.trailers = nil
+		for _, input := range e.inputs {
+			if value := strings.TrimSpace(input.Value()); value != "" {
+				m.trailers = append(m.trailers, trailer{strings.TrimSuffix(input.Prompt, ": "), value})
+			}
+		}
+		m.trailersEditor = nil
+	case m.keys.cancel.matches(key):
+		m.trailersEditor = nil
+	default:
+		e.inputs[e.focus], _ = e.inputs[e.focus].Update(msg)
+	}
+}
+
+// Add the trailers below the message the way git interpret-trailers does,
+// which follows the trailer.* settings of the repository, skipping ones the
+// message has already
+func withTrailers(r repo, message string, trailers []trailer) (string, error) {
+	if len(trailers) == 0 {
+		return message, nil
+	}
+	args := []string{"interpret-trailers", "--if-exists", "addIfDifferent"}
+	for _, t := range trailers {
+		args = append(args, "--trailer", t.String())
+	}
+	cmd := r.git(args...)
+	cmd.Stdin = strings.NewReader(message + "\n")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", errors.New(tr("Failed to add the trailers: %v", err))
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+func (m model) trailersEditorView(width, height int) string {
+	e := m.trailersEditor
+	rows := []string{sectionStyle.Render(ansi.Truncate(tr("Trailers, empty ones are left out"), width, "…")), ""}
+	offset := max(e.focus+2-(height-1), 0)
+	for i := offset; i < len(e.inputs) && len(rows) < height; i++ {
+		input := e.inputs[i]
+		input.Width = max(width-ansi.StringWidth(input.Prompt)-3, 0)
+		rows = append(rows, cfg.Glyphs.cursor(i == e.focus)+input.View())
+	}
+	return strings.Join(rows, "\n")
+}
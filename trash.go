@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// Directories of the trash are named by when the files were put there
+const trashTimeFormat = "2006-01-02T150405.000"
+
+// Where discarded and deleted files are kept, one directory per discard
+func (r repo) trashDir() string {
+	return filepath.Join(r.gitDir, "istage-trash")
+}
+
+// A new directory of the trash, named by the time
+func (r repo) newTrashDir() string {
+	return filepath.Join(r.trashDir(), time.Now().Format(trashTimeFormat))
+}
+
+// Copy the work tree content of files into a new directory of the trash, so
+// discarding or deleting them can be undone. Files that don't exist, like
+// deleted ones whose content is still in git, are left out.
+func saveToTrash(r repo, pathsFromGitRoot []string) error {
+	return copyToTrash(r, r.newTrashDir(), pathsFromGitRoot)
+}
+
+// Copy the work tree content of files into the directory of the trash, for
+// files saved one batch at a time to end up together
+func copyToTrash(r repo, dir string, pathsFromGitRoot []string) error {
+	for _, path := range pathsFromGitRoot {
+		src := filepath.Join(r.root, path)
+		if _, err := os.Lstat(src); os.IsNotExist(err) {
+			continue
+		}
+		if err := copyPath(src, filepath.Join(dir, path)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Copy a file, symlink or directory with everything in it
+func copyPath(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(src, path)
+		target := filepath.Join(dst, rel)
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, 0o755)
+		case d.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return err
+			}
+			os.Remove(target)
+			return os.Symlink(link, target)
+		default:
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return err
+			}
+			return os.WriteFile(target, content, info.Mode().Perm())
+		}
+	})
+}
+
+// Whether two paths are files with the same content or links to the same target
+func samePathContent(a, b string) bool {
+	if target, err := os.Readlink(a); err == nil {
+		other, err := os.Readlink(b)
+		return err == nil && target == other
+	}
+	contentA, errA := os.ReadFile(a)
+	contentB, errB := os.ReadFile(b)
+	return errA == nil && errB == nil && bytes.Equal(contentA, contentB)
+}
+
+// Directories of the trash, newest first
+func getTrashEntries(r repo) []recoveryEntry {
+	dirs, _ := os.ReadDir(r.trashDir())
+	var entries []recoveryEntry
+	for _, d := range slices.Backward(dirs) {
+		info, err := d.Info()
+		if !d.IsDir() || err != nil {
+			continue
+		}
+		entries = append(entries, recoveryEntry{
+			ref:     "trash/" + d.Name(),
+			age:     tr("%s ago", time.Since(info.ModTime()).Round(time.Second)),
+			subject: tr("discarded or deleted files"),
+			trash:   filepath.Join(r.trashDir(), d.Name()),
+		})
+	}
+	return entries
+}
+
+// Files kept in a directory of the trash that the work tree has other
+// content for
+func getTrashFiles(r repo, dir string) []recoveryFile {
+	var files []recoveryFile
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		rel, _ := filepath.Rel(dir, path)
+		if !samePathContent(path, filepath.Join(r.root, rel)) {
+			files = append(files, recoveryFile{filepath.ToSlash(rel), path, true})
+		}
+		return nil
+	})
+	return files
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/exp/golden"
+	"github.com/charmbracelet/x/exp/teatest"
+)
+
+// End-to-end tests run the program on a fixture repository and compare the
+// screen they end on with testdata/<test>.golden. After a deliberate change
+// to the interface, rewrite those with `go test -run TestTUI -update`.
+
+// How long to wait for the screen to show what a step expects
+const tuiTimeout = 5 * time.Second
+
+// A repository with a.txt, b.txt and c.txt committed, then a.txt changed in
+// the work tree, b.txt staged and d.txt new. Commits get the same hashes on
+// every run.
+func newFixtureRepo(t *testing.T) repo {
+	dir := t.TempDir()
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	for _, name := range []string{"GIT_AUTHOR", "GIT_COMMITTER"} {
+		t.Setenv(name+"_NAME", "Test")
+		t.Setenv(name+"_EMAIL", "test@example.com")
+		t.Setenv(name+"_DATE", "2024-01-01T12:00:00Z")
+	}
+	write := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	git := func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, output)
+		}
+	}
+	git("init", "-q", "-b", "main")
+	write("a.txt", "one\ntwo\nthree\n")
+	write("b.txt", "alpha\nbeta\n")
+	write("c.txt", "unchanged\n")
+	git("add", ".")
+	git("commit", "-q", "-m", "Initial commit")
+	write("a.txt", "one\n2\nthree\nfour\n")
+	write("b.txt", "alpha\nbeta\ngamma\n")
+	git("add", "b.txt")
+	write("d.txt", "new\n")
+
+	r, err := openRepo(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+// The program's model, keeping its latest screen for the test to wait on
+type recordedApp struct {
+	app
+	screen *struct {
+		sync.Mutex
+		view string
+	}
+}
+
+func (a recordedApp) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	next, cmd := a.app.Update(msg)
+	a.app = next.(app)
+	a.screen.Lock()
+	a.screen.view = a.app.View()
+	a.screen.Unlock()
+	return a, cmd
+}
+
+type tuiSession struct {
+	*teatest.TestModel
+	recorded recordedApp
+}
+
+// Start the program on the repository and wait for the list and the diff of
+// the first file to load
+func startTUI(t *testing.T, r repo) tuiSession {
+	if err := applyTheme("dark"); err != nil {
+		t.Fatal(err)
+	}
+	recorded := recordedApp{app: newApp([]repo{r})}
+	recorded.screen = &struct {
+		sync.Mutex
+		view string
+	}{}
+	s := tuiSession{teatest.NewTestModel(t, recorded, teatest.WithInitialTermSize(80, 16)), recorded}
+	s.waitFor(t, "Untracked (", "+gamma")
+	return s
+}
+
+// Wait for the screen to show all of texts at once, with nothing left
+// running in the background, which the status line would offer to cancel
+func (s tuiSession) waitFor(t *testing.T, texts ...string) {
+	t.Helper()
+	deadline := time.Now().Add(tuiTimeout)
+	for {
+		s.recorded.screen.Lock()
+		view := s.recorded.screen.view
+		s.recorded.screen.Unlock()
+		missing := slices.ContainsFunc(texts, func(text string) bool { return !strings.Contains(view, text) })
+		if !missing && !strings.Contains(view, "to cancel") {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("the screen didn't show %q in time, it shows:\n%s", texts, view)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// Press keys by name: runes, or one of the special keys the tests use
+func (s tuiSession) typeKeys(keys ...string) {
+	special := map[string]tea.KeyType{
+		"space":  tea.KeySpace,
+		"enter":  tea.KeyEnter,
+		"esc":    tea.KeyEsc,
+		"ctrl+s": tea.KeyCtrlS,
+	}
+	for _, k := range keys {
+		if keyType, ok := special[k]; ok {
+			s.Send(tea.KeyMsg{Type: keyType})
+		} else {
+			s.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)})
+		}
+	}
+}
+
+// Stop the program and compare its last screen with the golden file
+func (s tuiSession) requireScreen(t *testing.T) {
+	t.Helper()
+	if err := s.Quit(); err != nil {
+		t.Fatal(err)
+	}
+	final := s.FinalModel(t, teatest.WithFinalTimeout(tuiTimeout))
+	golden.RequireEqual(t, []byte(final.View()))
+}
+
+func TestTUIStartup(t *testing.T) {
+	s := startTUI(t, newFixtureRepo(t))
+	s.requireScreen(t)
+}
+
+func TestTUINavigate(t *testing.T) {
+	s := startTUI(t, newFixtureRepo(t))
+	s.typeKeys("j", "j")
+	s.waitFor(t, "> [ ] a.txt", "+four")
+	s.requireScreen(t)
+}
+
+func TestTUIStageFile(t *testing.T) {
+	s := startTUI(t, newFixtureRepo(t))
+	s.typeKeys("j", "j", "space")
+	s.waitFor(t, "> [✓] a.txt", "+four")
+	s.requireScreen(t)
+}
+
+func TestTUIUnstageFile(t *testing.T) {
+	s := startTUI(t, newFixtureRepo(t))
+	s.typeKeys("space")
+	s.waitFor(t, "> [ ] b.txt", "+gamma")
+	s.requireScreen(t)
+}
+
+func TestTUIToggleAll(t *testing.T) {
+	s := startTUI(t, newFixtureRepo(t))
+	s.typeKeys("a")
+	s.waitFor(t, "[✓] d.txt", "> [ ] b.txt", "+gamma")
+	s.requireScreen(t)
+}
+
+func TestTUIStageHunk(t *testing.T) {
+	s := startTUI(t, newFixtureRepo(t))
+	s.typeKeys("j", "j")
+	s.waitFor(t, "+four")
+	s.typeKeys("enter", "]", "s")
+	s.waitFor(t, "2 staged", "scroll down")
+	s.requireScreen(t)
+}
+
+func TestTUICommit(t *testing.T) {
+	s := startTUI(t, newFixtureRepo(t))
+	s.typeKeys("c")
+	s.waitFor(t, "Commit message")
+	s.typeKeys("Add gamma", "ctrl+s")
+	s.waitFor(t, "Committed", "+four")
+	s.requireScreen(t)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+// Set by the Makefile with -ldflags "-X main.version=… -X main.commit=…
+// -X main.date=…". Left empty, the build info Go embeds fills them in.
+var (
+	version string
+	commit  string
+	date    string
+)
+
+// What --version prints: the version, the commit and when it was made, and
+// the Go it was built with, like "git-istage v1.2.0 (4f1c2ab, 2026-10-01T12:00:00Z) go1.23.2 linux/amd64"
+func versionString() string {
+	v, c, d := version, commit, date
+	modified := false
+	if info, ok := debug.ReadBuildInfo(); ok {
+		if v == "" && info.Main.Version != "" {
+			v = info.Main.Version
+		}
+		for _, s := range info.Settings {
+			switch {
+			case s.Key == "vcs.revision" && c == "":
+				c = s.Value
+			case s.Key == "vcs.time" && d == "":
+				d = s.Value
+			case s.Key == "vcs.modified":
+				modified = s.Value == "true"
+			}
+		}
+	}
+	if v == "" {
+		v = "(devel)"
+	}
+	if len(c) > 12 {
+		c = c[:12]
+	}
+	if modified && commit == "" && !strings.Contains(v, "dirty") {
+		c += "-dirty"
+	}
+	info := fmt.Sprintf("git-istage %s", v)
+	switch {
+	case c != "" && d != "":
+		info += fmt.Sprintf(" (%s, %s)", c, d)
+	case c != "":
+		info += fmt.Sprintf(" (%s)", c)
+	}
+	return fmt.Sprintf("%s %s %s/%s", info, runtime.Version(), runtime.GOOS, runtime.GOARCH)
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// How often to look for changes made to the repository by other processes
+const watchInterval = time.Second
+
+// Set by --watch: stay open when there's nothing to stage, and list the
+// changes as they appear
+var watchWhenClean bool
+
+// What the list was loaded from, to notice when another process changes the
+// index or moves HEAD
+type repoSnapshot struct {
+	mtimes []time.Time // of the files in watchedFiles
+	state  string      // HEAD and the status of every file
+}
+
+type watchTickMsg struct{}
+
+func watchTick() tea.Cmd {
+	return tea.Tick(watchInterval, func(time.Time) tea.Msg {
+		return watchTickMsg{}
+	})
+}
+
+// The index, and HEAD with its reflog, which moves on commits to the branch too
+func watchedFiles(r repo) []string {
+	return []string{
+		filepath.Join(r.gitDir, "index"),
+		filepath.Join(r.gitDir, "HEAD"),
+		filepath.Join(r.gitDir, "logs", "HEAD"),
+	}
+}
+
+func watchedMtimes(r repo) []time.Time {
+	var mtimes []time.Time
+	for _, path := range watchedFiles(r) {
+		var mtime time.Time
+		if info, err := os.Stat(path); err == nil {
+			mtime = info.ModTime()
+		}
+		mtimes = append(mtimes, mtime)
+	}
+	return mtimes
+}
+
+func repoState(r repo) string {
+	head, _ := r.git("rev-parse", "HEAD").Output()
+	var lines []string
+	for path, xy := range getFileStatus(r) {
+		lines = append(lines, xy+" "+path)
+	}
+	slices.Sort(lines)
+	return string(head) + strings.Join(lines, "\n")
+}
+
+func takeSnapshot(r repo) repoSnapshot {
+	// git status may refresh the index, so look at the files afterwards
+	state := repoState(r)
+	return repoSnapshot{watchedMtimes(r), state}
+}
+
+// With --watch and nothing listed, load the changes as soon as there are
+// any, editing a file doesn't touch the index
+func (m *model) checkIdleChanges() {
+	if m.job != nil || m.review != nil || len(getFileStatus(m.repo)) == 0 {
+		return
+	}
+	m.reload()
+	m.loadDiff()
+}
+
+func (m model) idle() bool {
+	return watchWhenClean && !m.loading && len(m.files) == 0
+}
+
+// Ask to reload if the index or HEAD changed since the list was loaded.
+// Touching the files without changing what git reports, like another
+// git status refreshing the index, only updates the snapshot.
+func (m *model) checkExternalChanges() {
+	if m.idle() {
+		m.checkIdleChanges()
+		return
+	}
+	if m.loading || m.confirm != nil || m.job != nil || slices.Equal(watchedMtimes(m.repo), m.snapshot.mtimes) {
+		return
+	}
+	snapshot := takeSnapshot(m.repo)
+	if snapshot.state == m.snapshot.state {
+		m.snapshot = snapshot
+		return
+	}
+	m.confirm = &confirmation{
+		message: tr("Repository changed externally — reload?"),
+		onYes: func(m *model) {
+			m.reload()
+			m.loadDiff()
+		},
+		onNo: func(m *model) {
+			// Don't ask again until it changes again
+			m.snapshot = snapshot
+		},
+	}
+}